@@ -0,0 +1,119 @@
+package lazylog
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultAggregateInterval é o intervalo entre resumos de um
+// AggregatingTransport criado com interval <= 0.
+const DefaultAggregateInterval = time.Minute
+
+type aggregateKey struct {
+	level    Level
+	template string
+	group    string
+}
+
+// AggregatingTransport não grava cada entry: mantém contagens por nível,
+// template de mensagem e (opcionalmente) um campo escolhido, e a cada
+// intervalo emite uma entry de resumo por grupo no transporte Target.
+// Útil para pontos de log de altíssimo volume e baixo valor individual.
+type AggregatingTransport struct {
+	Target   Transport
+	Level    Level
+	Interval time.Duration
+	GroupBy  string // campo adicional de agrupamento (opcional)
+
+	mu     sync.Mutex
+	counts map[aggregateKey]int
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewAggregatingTransport cria o transporte e inicia o envio periódico dos
+// resumos (a cada DefaultAggregateInterval se interval <= 0).
+func NewAggregatingTransport(target Transport, level Level, interval time.Duration, groupBy string) *AggregatingTransport {
+	if interval <= 0 {
+		interval = DefaultAggregateInterval
+	}
+	a := &AggregatingTransport{
+		Target:   target,
+		Level:    level,
+		Interval: interval,
+		GroupBy:  groupBy,
+		counts:   make(map[aggregateKey]int),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+func (a *AggregatingTransport) run() {
+	defer close(a.done)
+	ticker := time.NewTicker(a.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = a.Flush()
+		case <-a.stop:
+			return
+		}
+	}
+}
+
+func (a *AggregatingTransport) WriteLog(entry *Entry) error {
+	key := aggregateKey{level: entry.Level, template: MessageTemplate(entry.Message)}
+	if a.GroupBy != "" {
+		if v, ok := entry.Fields[a.GroupBy]; ok {
+			key.group = fmt.Sprint(v)
+		}
+	}
+	a.mu.Lock()
+	a.counts[key]++
+	a.mu.Unlock()
+	return nil
+}
+
+func (a *AggregatingTransport) MinLevel() Level {
+	return a.Level
+}
+
+// Flush emite imediatamente os resumos acumulados e zera as contagens.
+func (a *AggregatingTransport) Flush() error {
+	a.mu.Lock()
+	counts := a.counts
+	a.counts = make(map[aggregateKey]int)
+	a.mu.Unlock()
+
+	now := time.Now()
+	var errs []error
+	for key, n := range counts {
+		fields := map[string]interface{}{
+			"summary":  true,
+			"template": key.template,
+			"count":    n,
+			"interval": a.Interval.String(),
+		}
+		if a.GroupBy != "" {
+			fields[a.GroupBy] = key.group
+		}
+		entry := &Entry{Level: key.level, Timestamp: now, Message: "log summary", Fields: fields}
+		if err := a.Target.WriteLog(entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close interrompe o envio periódico, emite o último resumo e fecha o Target.
+func (a *AggregatingTransport) Close() error {
+	a.once.Do(func() { close(a.stop) })
+	<-a.done
+	return errors.Join(a.Flush(), closeTransport(a.Target))
+}
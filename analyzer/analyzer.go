@@ -0,0 +1,175 @@
+// Package analyzer implementa um go/analysis.Analyzer que aponta usos
+// problemáticos do lazylog:
+//
+//   - mensagens montadas com fmt.Sprintf onde campos deveriam ser usados;
+//   - chamadas a Fatal fora do pacote main (bibliotecas não devem encerrar o processo);
+//   - mapas de campos passados a ComFields e também usados dentro de goroutines;
+//   - pares chave/valor malformados nos métodos *w (Infow, Errorw, ...): número
+//     ímpar de argumentos ou chaves que não são string.
+//
+// Uso via go vet: go vet -vettool=$(which lazylogvet) ./...
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const lazylogPath = "github.com/chmenegatti/lazylog"
+
+// Analyzer é o analisador do lazylog.
+var Analyzer = &analysis.Analyzer{
+	Name:     "lazylog",
+	Doc:      "reporta usos problemáticos do lazylog (mensagens com Sprintf, Fatal em bibliotecas, mapas de campos compartilhados entre goroutines, pares chave/valor malformados)",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// messageMethods são os métodos cujo primeiro argumento é a mensagem.
+var messageMethods = map[string]bool{
+	"Debug": true, "Info": true, "Warn": true, "Error": true, "Fatal": true, "Panic": true,
+	"DebugFields": true, "InfoFields": true, "WarnFields": true, "ErrorFields": true,
+	"Tracew": true, "Debugw": true, "Infow": true, "Warnw": true, "Errorw": true,
+}
+
+// keyValueMethods são os métodos que recebem, após a mensagem, pares
+// chave/valor alternados (ou Fields isolados).
+var keyValueMethods = map[string]bool{
+	"Tracew": true, "Debugw": true, "Infow": true, "Warnw": true, "Errorw": true,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}, func(n ast.Node) {
+		var body *ast.BlockStmt
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			body = fn.Body
+		case *ast.FuncLit:
+			body = fn.Body
+		}
+		if body != nil {
+			checkSharedFields(pass, body)
+		}
+	})
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		name, ok := lazylogMethod(pass, call)
+		if !ok {
+			return
+		}
+		if name == "Fatal" && pass.Pkg.Name() != "main" {
+			pass.Reportf(call.Pos(), "lazylog: Fatal encerra o processo; em bibliotecas, retorne o erro ao chamador")
+		}
+		if messageMethods[name] && len(call.Args) > 0 && isSprintf(pass, call.Args[0]) {
+			pass.Reportf(call.Args[0].Pos(), "lazylog: mensagem montada com fmt.Sprintf; use uma mensagem fixa e campos")
+		}
+		if keyValueMethods[name] && !call.Ellipsis.IsValid() && len(call.Args) > 1 {
+			checkKeyValues(pass, name, call.Args[1:])
+		}
+	})
+	return nil, nil
+}
+
+// lazylogMethod retorna o nome do método se call for um método de um tipo do lazylog.
+func lazylogMethod(pass *analysis.Pass, call *ast.CallExpr) (string, bool) {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != lazylogPath {
+		return "", false
+	}
+	if sig, ok := fn.Type().(*types.Signature); !ok || sig.Recv() == nil {
+		return "", false
+	}
+	return fn.Name(), true
+}
+
+// isSprintf indica se expr é uma chamada a fmt.Sprintf.
+func isSprintf(pass *analysis.Pass, expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == "fmt" && fn.Name() == "Sprintf"
+}
+
+// checkKeyValues reporta chaves que não são string e valores sem par nos
+// argumentos de um método *w. Um lazylog.Field ocupa uma posição sozinho; um
+// argumento de tipo interface pode ou não ser um Field, então a verificação
+// para nele para não gerar falsos positivos.
+func checkKeyValues(pass *analysis.Pass, name string, args []ast.Expr) {
+	for i := 0; i < len(args); i++ {
+		t := pass.TypesInfo.TypeOf(args[i])
+		if t == nil || types.IsInterface(t) {
+			return
+		}
+		if isLazylogField(t) {
+			continue
+		}
+		if i+1 == len(args) {
+			pass.Reportf(args[i].Pos(), "lazylog: número ímpar de argumentos chave/valor em %s; o valor sem par é registrado em \"!BADKEY\"", name)
+			return
+		}
+		if b, ok := t.Underlying().(*types.Basic); !ok || b.Info()&types.IsString == 0 {
+			pass.Reportf(args[i].Pos(), "lazylog: chave de %s não é string (%s); use uma chave string", name, t)
+		}
+		i++
+	}
+}
+
+// isLazylogField indica se t é lazylog.Field.
+func isLazylogField(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == lazylogPath && obj.Name() == "Field"
+}
+
+// checkSharedFields reporta mapas passados a ComFields que também são
+// referenciados dentro de uma goroutine iniciada no mesmo corpo de função.
+func checkSharedFields(pass *analysis.Pass, body *ast.BlockStmt) {
+	uses := map[types.Object][]*ast.CallExpr{}
+	inGoroutine := map[types.Object]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false // analisado separadamente
+		case *ast.GoStmt:
+			ast.Inspect(n.Call, func(m ast.Node) bool {
+				if id, ok := m.(*ast.Ident); ok {
+					if obj := pass.TypesInfo.Uses[id]; obj != nil {
+						inGoroutine[obj] = true
+					}
+				}
+				return true
+			})
+			return false
+		case *ast.CallExpr:
+			if name, ok := lazylogMethod(pass, n); ok && name == "ComFields" && len(n.Args) == 1 {
+				if id, ok := ast.Unparen(n.Args[0]).(*ast.Ident); ok {
+					if obj := pass.TypesInfo.Uses[id]; obj != nil {
+						if _, isMap := obj.Type().Underlying().(*types.Map); isMap {
+							uses[obj] = append(uses[obj], n)
+						}
+					}
+				}
+			}
+		}
+		return true
+	})
+	for obj, calls := range uses {
+		if !inGoroutine[obj] {
+			continue
+		}
+		for _, call := range calls {
+			pass.Reportf(call.Args[0].Pos(), "lazylog: o mapa de campos %q também é usado em uma goroutine; passe uma cópia para ComFields", obj.Name())
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"github.com/chmenegatti/lazylog/analyzer"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), analyzer.Analyzer, "a", "lib")
+}
@@ -0,0 +1,13 @@
+// Command lazylogvet roda o analyzer do lazylog, isoladamente ou via
+// go vet -vettool=$(which lazylogvet).
+package main
+
+import (
+	"github.com/chmenegatti/lazylog/analyzer"
+
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
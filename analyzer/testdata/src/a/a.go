@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/chmenegatti/lazylog"
+)
+
+func main() {
+	var logger *lazylog.Logger
+	id := 42
+	logger.Info(fmt.Sprintf("user %d logged in", id)) // want `mensagem montada com fmt.Sprintf`
+	logger.Info("user logged in")
+	logger.Fatal("ok in main")
+
+	fields := map[string]interface{}{"id": id}
+	logger.ComFields(fields).Info("shared") // want `também é usado em uma goroutine`
+	go func() {
+		fields["done"] = true
+	}()
+
+	own := map[string]interface{}{"id": id}
+	logger.ComFields(own).Info("not shared")
+
+	logger.Infow("pedido criado", "order_id", id, "customer", "acme")
+	logger.Infow("pedido criado", "order_id", id, "customer") // want `número ímpar de argumentos chave/valor em Infow`
+	logger.Errorw("falha", id, "x")                           // want `chave de Errorw não é string \(int\)`
+	logger.Infow("com field", lazylog.String("user", "cesar"), "order_id", id)
+	logger.Infow("com field", "order_id", id, lazylog.String("user", "cesar"))
+	logger.Infow(fmt.Sprintf("pedido %d", id), "order_id", id) // want `mensagem montada com fmt.Sprintf`
+
+	type key string
+	logger.Infow("chave nomeada", key("order_id"), id)
+	var kv []any
+	logger.Infow("repassado", kv...)
+	var dyn any = "order_id"
+	logger.Infow("dinâmico", dyn, id, "resto")
+}
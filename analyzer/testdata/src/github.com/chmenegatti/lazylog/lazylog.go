@@ -0,0 +1,23 @@
+// Stub mínimo do lazylog para os testes do analyzer.
+package lazylog
+
+type Logger struct{}
+
+type EntryBuilder struct{}
+
+type Field struct {
+	Key   string
+	Value any
+}
+
+func String(key, value string) Field { return Field{Key: key, Value: value} }
+
+func (l *Logger) Info(message string)                            {}
+func (l *Logger) Fatal(message string, fields ...map[string]any) {}
+func (l *Logger) ComFields(fields map[string]interface{}) *EntryBuilder {
+	return &EntryBuilder{}
+}
+func (b *EntryBuilder) Info(msg string) {}
+
+func (l *Logger) Infow(msg string, keysAndValues ...any)  {}
+func (l *Logger) Errorw(msg string, keysAndValues ...any) {}
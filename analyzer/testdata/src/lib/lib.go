@@ -0,0 +1,7 @@
+package lib
+
+import "github.com/chmenegatti/lazylog"
+
+func Do(logger *lazylog.Logger) {
+	logger.Fatal("boom") // want `Fatal encerra o processo`
+}
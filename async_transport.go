@@ -0,0 +1,256 @@
+package lazylog
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrQueueFull é retornado por AsyncTransport quando a fila está saturada e a
+// política de overflow é OverflowDrop.
+var ErrQueueFull = errors.New("lazylog: async queue is full")
+
+// OverflowPolicy define o comportamento do AsyncTransport com a fila cheia.
+type OverflowPolicy int
+
+const (
+	OverflowBlock OverflowPolicy = iota // bloqueia o chamador até haver espaço
+	OverflowDrop                        // descarta a entry e retorna ErrQueueFull
+)
+
+// QueueEventKind identifica o tipo de um QueueEvent.
+type QueueEventKind int
+
+const (
+	QueueEnqueued  QueueEventKind = iota // entry enfileirada (inclui a latência)
+	QueueHighWater                       // nova marca máxima de ocupação
+	QueueDropped                         // entry descartada por OverflowDrop
+)
+
+// QueueEvent descreve o estado da fila de um AsyncTransport no momento de um
+// enfileiramento, de uma nova marca máxima ou de um descarte.
+type QueueEvent struct {
+	Kind     QueueEventKind
+	Entry    *Entry
+	Depth    int           // entries aguardando escrita (todas as faixas)
+	Capacity int           // capacidade total das filas
+	Latency  time.Duration // tempo gasto para enfileirar (espera em OverflowBlock)
+	// HighWater é a maior ocupação observada desde a criação do transporte.
+	HighWater int
+}
+
+// AsyncTransport desacopla o chamador da escrita: entries são enfileiradas e
+// gravadas no transporte envolvido por uma goroutine dedicada.
+type AsyncTransport struct {
+	Transport Transport
+	Overflow  OverflowPolicy
+	// OnError recebe os erros de escrita ocorridos em background.
+	OnError func(entry *Entry, err error)
+	// OnQueueEvent, se definido, é chamado de forma síncrona no WriteLog para
+	// cada enfileiramento, nova marca máxima e descarte, permitindo alertar
+	// quando o pipeline de logging se aproxima da saturação. Deve ser rápido.
+	OnQueueEvent func(QueueEvent)
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan *Entry
+	// high é a faixa reservada para ERROR+ (nil quando não há prioridade).
+	high      chan *Entry
+	done      chan struct{}
+	highWater atomic.Int64
+	pending   atomic.Int64 // entries enfileiradas ainda não escritas (Flush)
+}
+
+// NewAsyncTransport cria um AsyncTransport com fila de tamanho size e inicia
+// a goroutine de escrita.
+func NewAsyncTransport(t Transport, size int, overflow OverflowPolicy) *AsyncTransport {
+	a := &AsyncTransport{
+		Transport: t,
+		Overflow:  overflow,
+		queue:     make(chan *Entry, size),
+		done:      make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// NewPriorityAsyncTransport cria um AsyncTransport com duas faixas: entries
+// ERROR+ vão para uma fila reservada de tamanho highSize, que nunca descarta
+// (bloqueia se cheia) e é sempre drenada primeiro; as demais usam a fila de
+// melhor esforço de tamanho size, sujeita à política de overflow. Assim os
+// erros sobrevivem ao descarte de carga.
+func NewPriorityAsyncTransport(t Transport, size, highSize int, overflow OverflowPolicy) *AsyncTransport {
+	a := &AsyncTransport{
+		Transport: t,
+		Overflow:  overflow,
+		queue:     make(chan *Entry, size),
+		high:      make(chan *Entry, highSize),
+		done:      make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+func (a *AsyncTransport) run() {
+	defer close(a.done)
+	high, low := a.high, a.queue
+	for high != nil || low != nil {
+		// A faixa de prioridade é consultada antes de cada entry comum.
+		if high != nil {
+			select {
+			case entry, ok := <-high:
+				if !ok {
+					high = nil
+				} else {
+					a.write(entry)
+				}
+				continue
+			default:
+			}
+		}
+		select {
+		case entry, ok := <-high:
+			if !ok {
+				high = nil
+				continue
+			}
+			a.write(entry)
+		case entry, ok := <-low:
+			if !ok {
+				low = nil
+				continue
+			}
+			a.write(entry)
+		}
+	}
+}
+
+func (a *AsyncTransport) write(entry *Entry) {
+	defer a.pending.Add(-1)
+	if err := a.Transport.WriteLog(entry); err != nil && a.OnError != nil {
+		a.OnError(entry, err)
+	}
+}
+
+// WriteLog enfileira uma cópia da entry (os campos não são compartilhados
+// com o chamador nem com outros transportes).
+func (a *AsyncTransport) WriteLog(entry *Entry) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return errors.New("lazylog: async transport is closed")
+	}
+	e := entry.Clone()
+	start := time.Now()
+	a.pending.Add(1)
+	if a.high != nil && e.Level >= ERROR {
+		a.high <- e
+		a.enqueued(e, start)
+		return nil
+	}
+	if a.Overflow == OverflowDrop {
+		select {
+		case a.queue <- e:
+			a.enqueued(e, start)
+			return nil
+		default:
+			a.pending.Add(-1)
+			a.queueEvent(QueueDropped, e, 0)
+			return ErrQueueFull
+		}
+	}
+	a.queue <- e
+	a.enqueued(e, start)
+	return nil
+}
+
+// enqueued atualiza a marca máxima e notifica OnQueueEvent.
+func (a *AsyncTransport) enqueued(e *Entry, start time.Time) {
+	depth := int64(a.Len())
+	raised := false
+	for {
+		hw := a.highWater.Load()
+		if depth <= hw {
+			break
+		}
+		if a.highWater.CompareAndSwap(hw, depth) {
+			raised = true
+			break
+		}
+	}
+	if a.OnQueueEvent == nil {
+		return
+	}
+	latency := time.Since(start)
+	a.queueEvent(QueueEnqueued, e, latency)
+	if raised {
+		a.queueEvent(QueueHighWater, e, latency)
+	}
+}
+
+func (a *AsyncTransport) queueEvent(kind QueueEventKind, e *Entry, latency time.Duration) {
+	if a.OnQueueEvent == nil {
+		return
+	}
+	a.OnQueueEvent(QueueEvent{
+		Kind:      kind,
+		Entry:     e,
+		Depth:     a.Len(),
+		Capacity:  cap(a.queue) + cap(a.high),
+		Latency:   latency,
+		HighWater: int(a.highWater.Load()),
+	})
+}
+
+func (a *AsyncTransport) MinLevel() Level {
+	return a.Transport.MinLevel()
+}
+
+// Len retorna a quantidade de entries aguardando escrita.
+func (a *AsyncTransport) Len() int {
+	return len(a.queue) + len(a.high)
+}
+
+// Ready delega ao transporte envolvido, se ele implementar ReadinessProber.
+func (a *AsyncTransport) Ready(ctx context.Context) error {
+	if p, ok := a.Transport.(ReadinessProber); ok {
+		return p.Ready(ctx)
+	}
+	return nil
+}
+
+// HighWater retorna a maior ocupação da fila observada até o momento.
+func (a *AsyncTransport) HighWater() int {
+	return int(a.highWater.Load())
+}
+
+// Flush aguarda a escrita das entries já enfileiradas e descarrega o
+// transporte envolvido (se ele implementar Flusher).
+func (a *AsyncTransport) Flush() error {
+	for a.pending.Load() > 0 {
+		select {
+		case <-a.done:
+			return flushTransport(a.Transport)
+		case <-time.After(time.Millisecond):
+		}
+	}
+	return flushTransport(a.Transport)
+}
+
+// Close drena a fila, aguarda a escrita das entries pendentes e fecha o
+// transporte envolvido (se ele implementar io.Closer).
+func (a *AsyncTransport) Close() error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+		if a.high != nil {
+			close(a.high)
+		}
+	}
+	a.mu.Unlock()
+	<-a.done
+	return closeTransport(a.Transport)
+}
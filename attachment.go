@@ -0,0 +1,98 @@
+package lazylog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+)
+
+// AttachmentStore persiste valores grandes fora do stream principal de log e
+// retorna uma referência (caminho, URL de object store etc).
+type AttachmentStore interface {
+	Store(data []byte, sha256Hex string) (ref string, err error)
+}
+
+// AttachmentStoreFunc adapta uma função a AttachmentStore (ex: upload para S3).
+type AttachmentStoreFunc func(data []byte, sha256Hex string) (string, error)
+
+// Store implementa AttachmentStore.
+func (f AttachmentStoreFunc) Store(data []byte, sha256Hex string) (string, error) {
+	return f(data, sha256Hex)
+}
+
+// FileAttachmentStore grava cada anexo em Dir/<sha256>.bin. Conteúdos iguais
+// são gravados uma única vez. Cada gravação usa um arquivo temporário próprio
+// em Dir, renomeado ao final, de modo que gravações concorrentes do mesmo
+// conteúdo (inclusive de outros processos) nunca expõem um anexo parcial.
+type FileAttachmentStore struct {
+	Dir string
+}
+
+// Store implementa AttachmentStore.
+func (s *FileAttachmentStore) Store(data []byte, sha256Hex string) (string, error) {
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(s.Dir, sha256Hex+".bin")
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	f, err := os.CreateTemp(s.Dir, sha256Hex+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Chmod(0o644)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return path, nil
+}
+
+// EnableAttachments registra um before-hook que move campos string ou []byte
+// maiores que threshold bytes (corpos de requisição, dumps de stack) para o
+// store, substituindo-os por {"attachment": ref, "sha256": hash, "size": n}.
+// Se o store falhar, o valor original é mantido.
+func (l *Logger) EnableAttachments(store AttachmentStore, threshold int) {
+	l.AddHook(func(entry *Entry) {
+		for k, v := range entry.Fields {
+			var data []byte
+			switch val := v.(type) {
+			case string:
+				if len(val) <= threshold {
+					continue
+				}
+				data = []byte(val)
+			case []byte:
+				if len(val) <= threshold {
+					continue
+				}
+				data = val
+			default:
+				continue
+			}
+			sum := sha256.Sum256(data)
+			hash := hex.EncodeToString(sum[:])
+			ref, err := store.Store(data, hash)
+			if err != nil {
+				continue
+			}
+			entry.Fields[k] = map[string]interface{}{
+				"attachment": ref,
+				"sha256":     hash,
+				"size":       len(data),
+			}
+		}
+	}, true)
+}
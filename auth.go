@@ -0,0 +1,321 @@
+package lazylog
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuthProvider autentica as requisições dos transportes HTTP. É chamado a cada
+// envio, então credenciais podem ser rotacionadas sem reiniciar o transporte.
+type AuthProvider interface {
+	Authenticate(req *http.Request) error
+}
+
+// HeaderAuth envia uma credencial estática em um header (ex: X-API-Key).
+type HeaderAuth struct {
+	Header string
+	Value  string
+}
+
+func (a *HeaderAuth) Authenticate(req *http.Request) error {
+	req.Header.Set(a.Header, a.Value)
+	return nil
+}
+
+// BearerAuth envia "Authorization: Bearer <token>", obtendo o token via
+// Refresh e mantendo-o em cache até a expiração (com 30s de margem).
+// Um expiry zero indica token sem expiração.
+type BearerAuth struct {
+	Refresh func(ctx context.Context) (token string, expiry time.Time, err error)
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// StaticBearerAuth cria um BearerAuth com token fixo.
+func StaticBearerAuth(token string) *BearerAuth {
+	return &BearerAuth{Refresh: func(context.Context) (string, time.Time, error) {
+		return token, time.Time{}, nil
+	}}
+}
+
+func (a *BearerAuth) Authenticate(req *http.Request) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token == "" || (!a.expiry.IsZero() && time.Now().Add(30*time.Second).After(a.expiry)) {
+		token, expiry, err := a.Refresh(req.Context())
+		if err != nil {
+			return err
+		}
+		a.token, a.expiry = token, expiry
+	}
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+// OAuth2ClientCredentials cria um BearerAuth que obtém tokens pelo fluxo
+// OAuth2 client credentials (RFC 6749, seção 4.4). client pode ser nil.
+func OAuth2ClientCredentials(tokenURL, clientID, clientSecret string, scopes []string, client *http.Client) *BearerAuth {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &BearerAuth{Refresh: func(ctx context.Context) (string, time.Time, error) {
+		form := url.Values{"grant_type": {"client_credentials"}}
+		if len(scopes) > 0 {
+			form.Set("scope", strings.Join(scopes, " "))
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", time.Time{}, fmt.Errorf("lazylog: oauth2 token endpoint returned status %d", resp.StatusCode)
+		}
+		var tok struct {
+			AccessToken string `json:"access_token"`
+			ExpiresIn   int64  `json:"expires_in"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+			return "", time.Time{}, err
+		}
+		var expiry time.Time
+		if tok.ExpiresIn > 0 {
+			expiry = time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+		}
+		return tok.AccessToken, expiry, nil
+	}}
+}
+
+// SigV4Auth assina as requisições com AWS Signature Version 4
+// (ex: Amazon OpenSearch, CloudWatch via API Gateway).
+type SigV4Auth struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // opcional (credenciais temporárias)
+	Region          string
+	Service         string
+	// Credentials, se definido, é consultado a cada requisição e tem
+	// precedência sobre os campos estáticos (rotação de credenciais).
+	Credentials func(ctx context.Context) (accessKeyID, secretAccessKey, sessionToken string, err error)
+	// Now é o relógio usado na assinatura (padrão: time.Now).
+	Now func() time.Time
+}
+
+func (a *SigV4Auth) Authenticate(req *http.Request) error {
+	akid, secret, session := a.AccessKeyID, a.SecretAccessKey, a.SessionToken
+	if a.Credentials != nil {
+		var err error
+		if akid, secret, session, err = a.Credentials(req.Context()); err != nil {
+			return err
+		}
+	}
+	now := a.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+
+	body, err := requestBody(req)
+	if err != nil {
+		return err
+	}
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if session != "" {
+		req.Header.Set("X-Amz-Security-Token", session)
+	}
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host, "x-amz-date": amzDate}
+	if session != "" {
+		headers["x-amz-security-token"] = session
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + strings.TrimSpace(headers[k]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := sigV4Path(req.URL, a.Service)
+	query := sigV4Query(req.URL.Query())
+	canonicalRequest := strings.Join([]string{
+		req.Method, path, query, canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+
+	scope := date + "/" + a.Region + "/" + a.Service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, a.Region)
+	key = hmacSHA256(key, a.Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+akid+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+	return nil
+}
+
+// sigV4Path retorna o caminho canônico da requisição: cada segmento é
+// codificado duas vezes (a forma enviada, já codificada, é codificada de
+// novo), exceto no S3, que usa a codificação simples.
+func sigV4Path(u *url.URL, service string) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	if service == "s3" {
+		return sigV4Escape(u.Path, true)
+	}
+	return sigV4Escape(path, true)
+}
+
+// sigV4Query retorna a query string canônica: pares codificados com
+// sigV4Escape e ordenados por chave e valor.
+func sigV4Query(values url.Values) string {
+	type pair struct{ k, v string }
+	pairs := make([]pair, 0, len(values))
+	for k, vs := range values {
+		for _, v := range vs {
+			pairs = append(pairs, pair{sigV4Escape(k, false), sigV4Escape(v, false)})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].k != pairs[j].k {
+			return pairs[i].k < pairs[j].k
+		}
+		return pairs[i].v < pairs[j].v
+	})
+	var b strings.Builder
+	for i, p := range pairs {
+		if i > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(p.k + "=" + p.v)
+	}
+	return b.String()
+}
+
+// sigV4Escape codifica s como a SigV4 exige: apenas letras, dígitos e
+// "-._~" (e "/", se keepSlash) ficam como estão, e os bytes restantes viram
+// %XX maiúsculo.
+func sigV4Escape(s string, keepSlash bool) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '.', c == '_', c == '~', c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hexDigits[c>>4])
+			b.WriteByte(hexDigits[c&15])
+		}
+	}
+	return b.String()
+}
+
+// requestBody lê o corpo da requisição sem consumi-lo.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// AuthConfig descreve um AuthProvider em LoggerConfig.
+type AuthConfig struct {
+	Type string `yaml:"Type"` // "header", "bearer", "oauth2" ou "sigv4"
+
+	Header string `yaml:"Header"` // header: nome do header
+	Value  string `yaml:"Value"`  // header: valor
+	Token  string `yaml:"Token"`  // bearer: token estático
+
+	TokenURL     string   `yaml:"TokenURL"` // oauth2
+	ClientID     string   `yaml:"ClientID"`
+	ClientSecret string   `yaml:"ClientSecret"`
+	Scopes       []string `yaml:"Scopes"`
+
+	AccessKeyID     string `yaml:"AccessKeyID"` // sigv4
+	SecretAccessKey string `yaml:"SecretAccessKey"`
+	SessionToken    string `yaml:"SessionToken"`
+	Region          string `yaml:"Region"`
+	Service         string `yaml:"Service"`
+}
+
+// Provider constrói o AuthProvider descrito pela configuração.
+func (c *AuthConfig) Provider() (AuthProvider, error) {
+	switch c.Type {
+	case "header":
+		return &HeaderAuth{Header: c.Header, Value: c.Value}, nil
+	case "bearer":
+		return StaticBearerAuth(c.Token), nil
+	case "oauth2":
+		return OAuth2ClientCredentials(c.TokenURL, c.ClientID, c.ClientSecret, c.Scopes, nil), nil
+	case "sigv4":
+		return &SigV4Auth{
+			AccessKeyID:     c.AccessKeyID,
+			SecretAccessKey: c.SecretAccessKey,
+			SessionToken:    c.SessionToken,
+			Region:          c.Region,
+			Service:         c.Service,
+		}, nil
+	default:
+		return nil, fmt.Errorf("lazylog: unknown auth type %q", c.Type)
+	}
+}
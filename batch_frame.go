@@ -0,0 +1,44 @@
+package lazylog
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// BatchFrameMagic identifica um frame de batch do lazylog.
+const BatchFrameMagic = "LZLB"
+
+// ErrInvalidBatchFrame indica um frame truncado ou sem o magic esperado.
+var ErrInvalidBatchFrame = errors.New("lazylog: invalid batch frame")
+
+// BatchHeader descreve um batch de entries para transportes binários
+// (msgpack, OTLP, Kafka): a versão do schema do payload, para que os
+// consumidores possam evoluir, e a compressão aplicada ("" = nenhuma). Os
+// consumidores decodificam os frames com reader.DecodeBatchFrame.
+type BatchHeader struct {
+	SchemaVersion uint16
+	Compression   string
+}
+
+// EncodeBatchFrame comprime payload (se h.Compression != "") e o prefixa com
+// o header: magic "LZLB", versão do schema (uint16), tamanho e nome da
+// compressão, tamanho do payload (uint32), todos big-endian.
+func EncodeBatchFrame(h BatchHeader, payload []byte) ([]byte, error) {
+	if len(h.Compression) > 255 {
+		return nil, fmt.Errorf("lazylog: compression name too long: %q", h.Compression)
+	}
+	if h.Compression != "" {
+		var err error
+		if payload, err = compressBody(h.Compression, payload); err != nil {
+			return nil, err
+		}
+	}
+	frame := make([]byte, 0, 11+len(h.Compression)+len(payload))
+	frame = append(frame, BatchFrameMagic...)
+	frame = binary.BigEndian.AppendUint16(frame, h.SchemaVersion)
+	frame = append(frame, byte(len(h.Compression)))
+	frame = append(frame, h.Compression...)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(len(payload)))
+	return append(frame, payload...), nil
+}
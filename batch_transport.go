@@ -0,0 +1,180 @@
+package lazylog
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultBatchSize é o tamanho de lote usado quando BatchTransport.Size é zero.
+const DefaultBatchSize = 1 << 20
+
+// BatchWriter recebe lotes de entries já serializadas, uma após a outra (no
+// JSONFormatter, uma por linha). batch só é válido durante a chamada: o
+// buffer é reutilizado no lote seguinte e deve ser copiado se for retido.
+type BatchWriter interface {
+	WriteBatch(batch []byte, entries int) error
+}
+
+// BatchWriterFunc adapta uma função a BatchWriter.
+type BatchWriterFunc func(batch []byte, entries int) error
+
+func (f BatchWriterFunc) WriteBatch(batch []byte, entries int) error {
+	return f(batch, entries)
+}
+
+// BatchTransport serializa as entries diretamente em buffers grandes e
+// reutilizados, sem um []byte por entry, e os entrega ao Writer a cada Size
+// bytes, a cada FlushInterval e em Flush/Close. Voltado a pipelines de alta
+// vazão (analytics em processo, envio em lote para Kafka ou S3):
+//
+//	batch := lazylog.NewBatchTransport(lazylog.BatchWriterFunc(func(b []byte, n int) error {
+//	    _, err := producer.Send(bytes.Clone(b))
+//	    return err
+//	}), lazylog.INFO, 4<<20, time.Second)
+//	logger := lazylog.NewLogger(batch)
+//	defer logger.Close()
+//
+// Formatters que implementam AppendFormatter (JSONFormatter) escrevem direto
+// no buffer; os demais têm a saída de Format copiada. Enquanto um lote é
+// entregue, as novas entries vão para um segundo buffer. Um lote cujo
+// WriteBatch falha é descartado e o erro é retornado ao chamador que
+// provocou a entrega (ou por Flush/Close). Stats conta lotes, não entries.
+// Após Close, WriteLog retorna erro e a entry é descartada.
+type BatchTransport struct {
+	Writer        BatchWriter
+	Formatter     Formatter // padrão: JSONFormatter
+	Level         Level
+	Size          int           // bytes por lote (padrão: DefaultBatchSize)
+	FlushInterval time.Duration // 0 = entrega só por tamanho, Flush e Close
+
+	mu     sync.Mutex
+	buf    []byte
+	count  int
+	closed bool
+
+	flushMu sync.Mutex // serializa as entregas, preservando a ordem dos lotes
+	spare   []byte
+
+	stats transportStats
+	stop  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+}
+
+// NewBatchTransport cria um BatchTransport e, se interval > 0, inicia a
+// entrega periódica dos lotes.
+func NewBatchTransport(w BatchWriter, level Level, size int, interval time.Duration) *BatchTransport {
+	t := &BatchTransport{Writer: w, Level: level, Size: size, FlushInterval: interval}
+	if interval > 0 {
+		t.stop = make(chan struct{})
+		t.done = make(chan struct{})
+		go t.run()
+	}
+	return t
+}
+
+func (t *BatchTransport) run() {
+	defer close(t.done)
+	ticker := time.NewTicker(t.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = t.Flush()
+		case <-t.stop:
+			return
+		}
+	}
+}
+
+func (t *BatchTransport) WriteLog(entry *Entry) error {
+	_, err := t.writeLogN(entry)
+	return err
+}
+
+// writeLogN serializa a entry no lote corrente e retorna seu tamanho; entrega
+// o lote se ele atingir Size.
+func (t *BatchTransport) writeLogN(entry *Entry) (int, error) {
+	size := t.Size
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return 0, errors.New("lazylog: batch transport is closed")
+	}
+	if t.buf == nil {
+		t.buf = make([]byte, 0, size+size/8)
+	}
+	start := len(t.buf)
+	var err error
+	switch f := t.Formatter.(type) {
+	case nil:
+		t.buf, err = (&JSONFormatter{}).AppendFormat(t.buf, entry)
+	case AppendFormatter:
+		t.buf, err = f.AppendFormat(t.buf, entry)
+	default:
+		var data []byte
+		if data, err = f.Format(entry); err == nil {
+			t.buf = append(t.buf, data...)
+		}
+	}
+	if err != nil {
+		t.buf = t.buf[:start]
+		t.mu.Unlock()
+		return 0, err
+	}
+	t.count++
+	n, full := len(t.buf)-start, len(t.buf) >= size
+	t.mu.Unlock()
+	if full {
+		return n, t.Flush()
+	}
+	return n, nil
+}
+
+func (t *BatchTransport) writesTypedFields() bool {
+	return typedFormatter(t.Formatter)
+}
+
+func (t *BatchTransport) MinLevel() Level {
+	return t.Level
+}
+
+// Flush entrega imediatamente o lote corrente, se houver entries.
+func (t *BatchTransport) Flush() error {
+	t.flushMu.Lock()
+	defer t.flushMu.Unlock()
+	t.mu.Lock()
+	batch, count := t.buf, t.count
+	if count == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	t.buf, t.count = t.spare[:0], 0
+	t.mu.Unlock()
+	err := t.Writer.WriteBatch(batch, count)
+	t.stats.record(t, len(batch), err)
+	t.spare = batch[:0]
+	return err
+}
+
+// Close interrompe a entrega periódica, entrega o último lote e fecha o
+// Writer (se ele implementar io.Closer).
+func (t *BatchTransport) Close() error {
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+	if t.stop != nil {
+		t.once.Do(func() { close(t.stop) })
+		<-t.done
+	}
+	err := t.Flush()
+	if c, ok := t.Writer.(io.Closer); ok {
+		err = errors.Join(err, c.Close())
+	}
+	return err
+}
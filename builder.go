@@ -0,0 +1,247 @@
+package lazylog
+
+import (
+	"errors"
+	"os"
+)
+
+// Builder compõe um Logger com múltiplos transportes de forma fluente:
+//
+//	logger, err := lazylog.Build().
+//		Console(lazylog.INFO, lazylog.Color()).
+//		File("app.log", lazylog.DEBUG, lazylog.JSON()).
+//		Loki("http://loki:3100/loki/api/v1/push", lazylog.WARN).
+//		Logger()
+//
+// Erros na criação de um transporte (ex: arquivo sem permissão) são
+// acumulados e retornados por Logger, que fecha os transportes já criados.
+type Builder struct {
+	transports []Transport
+	fields     map[string]interface{}
+	errs       []error
+}
+
+// Build inicia um Builder vazio.
+func Build() *Builder {
+	return &Builder{}
+}
+
+// TransportOption ajusta um transporte criado pelo Builder.
+type TransportOption func(*transportSettings)
+
+type transportSettings struct {
+	formatter Formatter
+	color     bool
+	emoji     bool
+	stderr    bool
+	maxLevel  *Level
+	filter    FilterFunc
+	labels    map[string]string
+	async     int
+}
+
+// JSON usa JSONFormatter no transporte.
+func JSON() TransportOption {
+	return func(s *transportSettings) { s.formatter = &JSONFormatter{} }
+}
+
+// Text usa TextFormatter no transporte.
+func Text() TransportOption {
+	return func(s *transportSettings) { s.formatter = &TextFormatter{} }
+}
+
+// WithFormatter usa um formatter arbitrário no transporte.
+func WithFormatter(f Formatter) TransportOption {
+	return func(s *transportSettings) { s.formatter = f }
+}
+
+// Color colore as linhas por nível (ColorFormatter).
+func Color() TransportOption {
+	return func(s *transportSettings) { s.color = true }
+}
+
+// Emoji adiciona o campo emoji por nível (EmojiFormatter).
+func Emoji() TransportOption {
+	return func(s *transportSettings) { s.emoji = true }
+}
+
+// Stderr faz o transporte de console escrever no stderr.
+func Stderr() TransportOption {
+	return func(s *transportSettings) { s.stderr = true }
+}
+
+// MaxLevel descarta entries acima do nível informado.
+func MaxLevel(level Level) TransportOption {
+	return func(s *transportSettings) { s.maxLevel = &level }
+}
+
+// Filter aplica um FilterFunc ao transporte (ver também ParseFilterExpr).
+func Filter(f FilterFunc) TransportOption {
+	return func(s *transportSettings) { s.filter = f }
+}
+
+// Labels define os rótulos fixos do stream Loki.
+func Labels(labels map[string]string) TransportOption {
+	return func(s *transportSettings) { s.labels = labels }
+}
+
+// Async envolve o transporte num AsyncTransport com fila de tamanho size
+// (OverflowBlock).
+func Async(size int) TransportOption {
+	return func(s *transportSettings) { s.async = size }
+}
+
+func applyTransportOptions(def Formatter, opts []TransportOption) transportSettings {
+	s := transportSettings{formatter: def}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	if s.emoji {
+		s.formatter = &EmojiFormatter{Base: s.formatter}
+	}
+	if s.color {
+		s.formatter = &ColorFormatter{Base: s.formatter}
+	}
+	return s
+}
+
+// add aplica filtros e fila assíncrona e registra o transporte.
+func (b *Builder) add(t Transport, s transportSettings) *Builder {
+	filter := s.filter
+	if s.maxLevel != nil {
+		limit, next := *s.maxLevel, filter
+		filter = func(e *Entry) bool { return e.Level <= limit && (next == nil || next(e)) }
+	}
+	if filter != nil {
+		t = &TransportWithFilter{Transport: t, Filter: filter}
+	}
+	if s.async > 0 {
+		t = NewAsyncTransport(t, s.async, OverflowBlock)
+	}
+	b.transports = append(b.transports, t)
+	return b
+}
+
+// Console adiciona um ConsoleTransport (TextFormatter por padrão).
+func (b *Builder) Console(level Level, opts ...TransportOption) *Builder {
+	s := applyTransportOptions(&TextFormatter{}, opts)
+	return b.add(&ConsoleTransport{Level: level, Formatter: s.formatter, ToStdErr: s.stderr}, s)
+}
+
+// File adiciona um FileTransport (JSONFormatter por padrão).
+func (b *Builder) File(path string, level Level, opts ...TransportOption) *Builder {
+	s := applyTransportOptions(&JSONFormatter{}, opts)
+	ft, err := NewFileTransport(path, level, s.formatter)
+	if err != nil {
+		b.errs = append(b.errs, err)
+		return b
+	}
+	return b.add(ft, s)
+}
+
+// HTTP adiciona um HTTPTransport (JSONFormatter por padrão).
+func (b *Builder) HTTP(url string, level Level, opts ...TransportOption) *Builder {
+	s := applyTransportOptions(&JSONFormatter{}, opts)
+	ht, err := NewHTTPTransport(url, level, s.formatter, nil)
+	if err != nil {
+		b.errs = append(b.errs, err)
+		return b
+	}
+	return b.add(ht, s)
+}
+
+// Loki adiciona um HTTPTransport que envia para a API de push do Loki
+// (url completa, ex: http://loki:3100/loki/api/v1/push). A linha usa
+// JSONFormatter por padrão; rótulos fixos via Labels.
+func (b *Builder) Loki(url string, level Level, opts ...TransportOption) *Builder {
+	s := applyTransportOptions(&JSONFormatter{}, opts)
+	ht, err := NewHTTPTransport(url, level, &LokiFormatter{Line: s.formatter, Labels: s.labels}, nil)
+	if err != nil {
+		b.errs = append(b.errs, err)
+		return b
+	}
+	ht.Headers = map[string]string{"Content-Type": "application/json"}
+	return b.add(ht, s)
+}
+
+// Transport adiciona um transporte já construído.
+func (b *Builder) Transport(t Transport) *Builder {
+	b.transports = append(b.transports, t)
+	return b
+}
+
+// Fields define campos fixos incluídos em todas as entries do logger (campos
+// da própria entry têm precedência).
+func (b *Builder) Fields(fields map[string]interface{}) *Builder {
+	if b.fields == nil {
+		b.fields = make(map[string]interface{}, len(fields))
+	}
+	for k, v := range fields {
+		b.fields[k] = v
+	}
+	return b
+}
+
+// Logger cria o Logger. Sem transportes, usa um ConsoleTransport INFO com
+// TextFormatter.
+func (b *Builder) Logger() (*Logger, error) {
+	if err := errors.Join(b.errs...); err != nil {
+		for _, t := range b.transports {
+			closeTransport(t)
+		}
+		return nil, err
+	}
+	transports := b.transports
+	if len(transports) == 0 {
+		transports = []Transport{&ConsoleTransport{Level: INFO, Formatter: &TextFormatter{}}}
+	}
+	logger := NewLogger(transports...)
+	if len(b.fields) > 0 {
+		fixed := b.fields
+		logger.AddHook(func(entry *Entry) {
+			if entry.Fields == nil {
+				entry.Fields = make(map[string]interface{}, len(fixed))
+			}
+			for k, v := range fixed {
+				if _, ok := entry.Fields[k]; !ok {
+					entry.Fields[k] = v
+				}
+			}
+		}, true)
+	}
+	return logger, nil
+}
+
+// HumanTimestampFormat é o formato compacto de horário usado na saída para
+// humanos de NewDualLogger.
+const HumanTimestampFormat = "15:04:05.000"
+
+// NewDualLogger monta o preset mais comum em produção: texto compacto para
+// humanos no stderr (colorido quando o stderr é um terminal e NO_COLOR não está
+// definido), a partir de humanLevel, e JSON completo no transporte machine
+// (arquivo, HTTP, Loki...), que mantém o próprio nível.
+func NewDualLogger(humanLevel Level, machine Transport) *Logger {
+	var human Formatter = &TextFormatter{TimestampFormat: HumanTimestampFormat}
+	if stderrIsTerminal() && os.Getenv("NO_COLOR") == "" {
+		human = &ColorFormatter{Base: human}
+	}
+	return NewLogger(
+		&ConsoleTransport{Level: humanLevel, Formatter: human, ToStdErr: true},
+		machine,
+	)
+}
+
+// Dual é o equivalente de NewDualLogger no Builder: adiciona a saída para
+// humanos no stderr e um arquivo JSON em path.
+func (b *Builder) Dual(humanLevel Level, path string, machineLevel Level) *Builder {
+	opts := []TransportOption{Stderr(), WithFormatter(&TextFormatter{TimestampFormat: HumanTimestampFormat})}
+	if stderrIsTerminal() && os.Getenv("NO_COLOR") == "" {
+		opts = append(opts, Color())
+	}
+	return b.Console(humanLevel, opts...).File(path, machineLevel, JSON())
+}
+
+func stderrIsTerminal() bool {
+	fi, err := os.Stderr.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,108 @@
+package lazylog
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Caller identifica o ponto do código que registrou a entry (EnableCaller).
+type Caller struct {
+	File     string `json:"file"` // caminho completo do arquivo
+	Line     int    `json:"line"`
+	Function string `json:"function"` // nome qualificado (ex: "github.com/acme/app/db.(*Repo).Find")
+	Package  string `json:"package"`  // ex: "github.com/acme/app/db"
+}
+
+// ShortFile retorna o arquivo com apenas o diretório imediato
+// (ex: "db/repo.go").
+func (c Caller) ShortFile() string {
+	slash := strings.LastIndexByte(c.File, '/')
+	if slash < 0 {
+		return c.File
+	}
+	if prev := strings.LastIndexByte(c.File[:slash], '/'); prev >= 0 {
+		return c.File[prev+1:]
+	}
+	return c.File
+}
+
+// String retorna "dir/arquivo.go:linha", a forma usada pelos formatters.
+func (c Caller) String() string {
+	return c.ShortFile() + ":" + strconv.Itoa(c.Line)
+}
+
+// EnableCaller passa a registrar em Entry.Caller o arquivo, a linha e o
+// pacote de quem chamou o logger; TextFormatter, JSONFormatter e
+// LogfmtFormatter o escrevem como "caller". Os frames internos do lazylog
+// (EntryBuilder, métodos *Ctx, Step...) são sempre ignorados; skip pula
+// frames adicionais, para helpers que embrulham o logger:
+//
+//	func logFailure(err error) { logger.WithError(err).Error("failed") } // EnableCaller(1)
+//
+// Tem custo (percorrer a stack a cada entry registrada).
+func (l *Logger) EnableCaller(skip int) {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.caller, l.callerSkip = true, skip
+}
+
+// DisableCaller deixa de registrar Entry.Caller.
+func (l *Logger) DisableCaller() {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.caller = false
+}
+
+// EnableFunction passa a registrar em Entry.Function o nome qualificado da
+// função que chamou o logger, escrito pelos formatters como "function". Pode
+// ser usado junto ou no lugar de EnableCaller; os frames pulados são os
+// mesmos (o skip de EnableCaller vale para ambos).
+func (l *Logger) EnableFunction() {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.function = true
+}
+
+// DisableFunction deixa de registrar Entry.Function.
+func (l *Logger) DisableFunction() {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.function = false
+}
+
+// captureCaller retorna o primeiro frame fora do lazylog, depois de pular
+// skip frames do código do usuário.
+func captureCaller(skip int) *Caller {
+	pcs := make([]uintptr, 32+skip)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !isInternalFrame(f.Function) {
+			if skip == 0 {
+				return &Caller{File: f.File, Line: f.Line, Function: f.Function, Package: funcPackage(f.Function)}
+			}
+			skip--
+		}
+		if !more {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package lazylog
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CanonicalEntry acumula campos e contadores ao longo de uma requisição e
+// emite uma única entry "larga" ao final (padrão canonical log line).
+// Todos os métodos são thread-safe e seguros para receiver nil.
+type CanonicalEntry struct {
+	logger   *Logger
+	start    time.Time
+	mu       sync.Mutex
+	fields   map[string]any
+	counters map[string]int64
+	emitted  bool
+}
+
+// NewCanonicalEntry cria uma canonical entry vinculada ao logger.
+func (l *Logger) NewCanonicalEntry() *CanonicalEntry {
+	if l.core().nop {
+		return nil // métodos de CanonicalEntry aceitam receiver nil
+	}
+	return &CanonicalEntry{
+		logger:   l,
+		start:    time.Now(),
+		fields:   make(map[string]any),
+		counters: make(map[string]int64),
+	}
+}
+
+// Set define (ou sobrescreve) um campo da canonical entry.
+func (c *CanonicalEntry) Set(key string, value any) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fields[key] = value
+}
+
+// Incr soma delta ao contador informado (ex: db_calls, cache_hits).
+func (c *CanonicalEntry) Incr(key string, delta int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counters[key] += delta
+}
+
+// Emit registra a canonical entry com todos os campos e contadores acumulados,
+// mais o campo duration_ms. Chamadas subsequentes são ignoradas.
+func (c *CanonicalEntry) Emit(level Level, message string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	if c.emitted {
+		c.mu.Unlock()
+		return
+	}
+	c.emitted = true
+	fields := make(map[string]any, len(c.fields)+len(c.counters)+1)
+	for k, v := range c.fields {
+		fields[k] = v
+	}
+	for k, v := range c.counters {
+		fields[k] = v
+	}
+	c.mu.Unlock()
+	fields["duration_ms"] = float64(time.Since(c.start)) / float64(time.Millisecond)
+	c.logger.logWithFields(level, message, fields)
+}
+
+type canonicalCtxKey struct{}
+
+// ContextWithCanonicalEntry retorna um context que carrega a canonical entry.
+func ContextWithCanonicalEntry(ctx context.Context, c *CanonicalEntry) context.Context {
+	return context.WithValue(ctx, canonicalCtxKey{}, c)
+}
+
+// CanonicalEntryFromContext recupera a canonical entry do context (ou nil).
+// Como os métodos aceitam receiver nil, o retorno pode ser usado diretamente.
+func CanonicalEntryFromContext(ctx context.Context) *CanonicalEntry {
+	c, _ := ctx.Value(canonicalCtxKey{}).(*CanonicalEntry)
+	return c
+}
@@ -0,0 +1,110 @@
+package lazylog
+
+import (
+	"sort"
+)
+
+// EntryTransform altera uma entry antes da formatação. ChainFormatter passa
+// uma cópia (Entry.Clone), então a transformação pode mutar campos à vontade.
+type EntryTransform func(entry *Entry)
+
+// ChainFormatter aplica Transforms, em ordem, sobre uma cópia da entry e
+// delega a saída ao Terminal. Formaliza o padrão de wrapper de
+// EmojiFormatter num pipeline componível:
+//
+//	f := lazylog.NewChainFormatter(&lazylog.JSONFormatter{},
+//		lazylog.RedactFields("password", "token"),
+//		lazylog.AddFields(map[string]any{"service": "api"}),
+//		lazylog.FlattenFields("."),
+//	)
+type ChainFormatter struct {
+	Transforms []EntryTransform
+	Terminal   Formatter // usa TextFormatter se nil
+}
+
+// NewChainFormatter cria um ChainFormatter.
+func NewChainFormatter(terminal Formatter, transforms ...EntryTransform) *ChainFormatter {
+	return &ChainFormatter{Transforms: transforms, Terminal: terminal}
+}
+
+func (f *ChainFormatter) Format(entry *Entry) ([]byte, error) {
+	terminal := f.Terminal
+	if terminal == nil {
+		terminal = &TextFormatter{}
+	}
+	if len(f.Transforms) == 0 {
+		return terminal.Format(entry)
+	}
+	e := entry.Clone()
+	for _, t := range f.Transforms {
+		t(e)
+	}
+	return terminal.Format(e)
+}
+
+// RedactFields substitui o valor dos campos informados por RedactedValue.
+func RedactFields(keys ...string) EntryTransform {
+	return func(entry *Entry) {
+		for _, k := range keys {
+			if _, ok := entry.Fields[k]; ok {
+				entry.Fields[k] = RedactedValue
+			}
+		}
+	}
+}
+
+// AddFields inclui campos fixos na entry (campos já presentes têm precedência).
+func AddFields(fields map[string]any) EntryTransform {
+	return func(entry *Entry) {
+		if entry.Fields == nil {
+			entry.Fields = make(map[string]interface{}, len(fields))
+		}
+		for k, v := range fields {
+			if _, ok := entry.Fields[k]; !ok {
+				entry.Fields[k] = v
+			}
+		}
+	}
+}
+
+// FlattenFields achata mapas aninhados em chaves unidas por sep
+// (ex: {"http": {"status": 200}} vira {"http.status": 200}), para destinos
+// que não indexam objetos. A posição do campo original em FieldOrder é
+// mantida pelas chaves achatadas.
+func FlattenFields(sep string) EntryTransform {
+	return func(entry *Entry) {
+		var order []string
+		flat := make(map[string]interface{}, len(entry.Fields))
+		for _, k := range entry.orderedKeys() {
+			order = flattenInto(flat, order, k, entry.Fields[k], sep)
+		}
+		entry.Fields = flat
+		if entry.FieldOrder != nil {
+			entry.FieldOrder = order
+		}
+	}
+}
+
+// flattenInto grava v (achatado) em dst e acrescenta as chaves geradas a keys.
+func flattenInto(dst map[string]interface{}, keys []string, key string, v interface{}, sep string) []string {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		dst[key] = v
+		return append(keys, key)
+	}
+	nested := make([]string, 0, len(m))
+	for k := range m {
+		nested = append(nested, k)
+	}
+	sort.Strings(nested)
+	for _, k := range nested {
+		keys = flattenInto(dst, keys, key+sep+k, m[k], sep)
+	}
+	return keys
+}
+
+// AddLevelEmoji inclui o campo "emoji" de acordo com o nível (a
+// transformação usada por EmojiFormatter).
+func AddLevelEmoji(entry *Entry) {
+	entry.Fields = withField(entry.Fields, "emoji", levelEmojis[entry.Level])
+}
@@ -0,0 +1,138 @@
+package lazylog
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EnableUTC registra um before-hook que converte o timestamp de toda entry
+// para UTC e adiciona o campo "tz_offset" com o offset original (ex: "-03:00"),
+// facilitando a correlação de logs de máquinas em fusos diferentes.
+func (l *Logger) EnableUTC() {
+	l.AddHook(func(entry *Entry) {
+		offset := entry.Timestamp.Format("-07:00")
+		entry.Timestamp = entry.Timestamp.UTC()
+		if entry.Fields == nil {
+			entry.Fields = make(map[string]interface{})
+		}
+		entry.Fields["tz_offset"] = offset
+	}, true)
+}
+
+// ntpEpochOffset é a diferença em segundos entre 1900 (NTP) e 1970 (Unix).
+const ntpEpochOffset = 2208988800
+
+// QueryNTPOffset consulta um servidor NTP (SNTP, ex: "pool.ntp.org:123") e
+// retorna o quanto o relógio local está atrasado (positivo) ou adiantado
+// (negativo) em relação a ele.
+func QueryNTPOffset(server string, timeout time.Duration) (time.Duration, error) {
+	conn, err := net.DialTimeout("udp", server, timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	req := make([]byte, 48)
+	req[0] = 0x1B // LI=0, VN=3, Mode=3 (client)
+	t1 := time.Now()
+	if _, err := conn.Write(req); err != nil {
+		return 0, err
+	}
+	resp := make([]byte, 48)
+	n, err := conn.Read(resp)
+	t4 := time.Now()
+	if err != nil {
+		return 0, err
+	}
+	if n < 48 || resp[0]&0x07 != 4 { // Mode=4 (server)
+		return 0, errors.New("lazylog: invalid NTP response")
+	}
+	t2 := ntpTime(resp[32:40])
+	t3 := ntpTime(resp[40:48])
+	return (t2.Sub(t1) + t3.Sub(t4)) / 2, nil
+}
+
+// ntpTime converte um timestamp NTP de 64 bits.
+func ntpTime(b []byte) time.Time {
+	secs := int64(binary.BigEndian.Uint32(b[0:4])) - ntpEpochOffset
+	frac := int64(binary.BigEndian.Uint32(b[4:8]))
+	return time.Unix(secs, (frac*int64(time.Second))>>32)
+}
+
+// DefaultClockSkewInterval é o intervalo entre medições de um
+// ClockSkewMonitor criado com interval <= 0.
+const DefaultClockSkewInterval = 10 * time.Minute
+
+// ClockSkewMonitor mede periodicamente o desvio do relógio local em relação a
+// um servidor NTP de referência.
+type ClockSkewMonitor struct {
+	Server   string
+	Interval time.Duration
+	Timeout  time.Duration
+
+	skew  atomic.Int64
+	valid atomic.Bool
+	once  sync.Once
+	stop  chan struct{}
+}
+
+// NewClockSkewMonitor inicia a medição (imediata e a cada interval, ou
+// DefaultClockSkewInterval se interval <= 0) contra o servidor NTP informado.
+// Chame Close para encerrar.
+func NewClockSkewMonitor(server string, interval time.Duration) *ClockSkewMonitor {
+	if interval <= 0 {
+		interval = DefaultClockSkewInterval
+	}
+	m := &ClockSkewMonitor{Server: server, Interval: interval, Timeout: 5 * time.Second, stop: make(chan struct{})}
+	go m.run()
+	return m
+}
+
+func (m *ClockSkewMonitor) run() {
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+	for {
+		if offset, err := QueryNTPOffset(m.Server, m.Timeout); err == nil {
+			m.skew.Store(int64(offset))
+			m.valid.Store(true)
+		}
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Skew retorna o último desvio medido e se já houve alguma medição.
+func (m *ClockSkewMonitor) Skew() (time.Duration, bool) {
+	return time.Duration(m.skew.Load()), m.valid.Load()
+}
+
+// Hook retorna um before-hook que adiciona o campo "clock_skew_ms" (desvio do
+// relógio local em relação à referência) a toda entry, após a primeira medição:
+//
+//	logger.AddHook(monitor.Hook(), true)
+func (m *ClockSkewMonitor) Hook() Hook {
+	return func(entry *Entry) {
+		skew, ok := m.Skew()
+		if !ok {
+			return
+		}
+		if entry.Fields == nil {
+			entry.Fields = make(map[string]interface{})
+		}
+		entry.Fields["clock_skew_ms"] = float64(skew) / float64(time.Millisecond)
+	}
+}
+
+// Close encerra a medição periódica. Chamadas repetidas não têm efeito.
+func (m *ClockSkewMonitor) Close() error {
+	m.once.Do(func() { close(m.stop) })
+	return nil
+}
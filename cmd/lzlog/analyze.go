@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/chmenegatti/lazylog"
+	"github.com/chmenegatti/lazylog/reader"
+)
+
+// runAnalyze lê arquivos de log (ou o stdin) e escreve o relatório dos pontos
+// de log mais verbosos (ver lazylog.NoisyTracker), agrupando as entries pelo
+// template da mensagem e pelo campo caller, quando presente.
+func runAnalyze(args []string, stdin io.Reader, out io.Writer) error {
+	fs := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	top := fs.Int("top", 20, "quantidade de pontos no relatório (0 = todos)")
+	maxSites := fs.Int("max-sites", 10000, "pontos distintos antes de agrupar em "+lazylog.OtherTemplates)
+	files, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	var inputs []io.Reader
+	for _, name := range files {
+		if name == "-" {
+			inputs = append(inputs, stdin)
+			continue
+		}
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		inputs = append(inputs, f)
+	}
+	if len(inputs) == 0 {
+		inputs = append(inputs, stdin)
+	}
+
+	noisy := lazylog.NewNoisyTracker(*maxSites)
+	for _, r := range inputs {
+		s := reader.NewScanner(r)
+		for s.Scan() {
+			e := reader.ParseLine(s.Line())
+			caller, _ := e.Fields["caller"].(string)
+			noisy.Add(e.Message, caller, e.Level)
+		}
+		if err := s.Err(); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(out, "%d entries, %d pontos de log\n", noisy.Total(), len(noisy.Top(0)))
+	return noisy.Report(out, *top)
+}
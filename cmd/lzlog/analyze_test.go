@@ -0,0 +1,27 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeReport(t *testing.T) {
+	input := strings.Repeat(`{"level":"DEBUG","message":"cache hit for key 17","caller":"cache.go:42"}`+"\n", 3) +
+		`{"level":"WARN","message":"cache hit for key 99","caller":"cache.go:42"}` + "\n" +
+		"2025-03-01T12:00:00Z [ERROR] db timeout caller=db.go:10\n"
+
+	var out bytes.Buffer
+	if err := runAnalyze([]string{"-top", "1"}, strings.NewReader(input), &out); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || lines[0] != "5 entries, 2 pontos de log" {
+		t.Fatalf("unexpected report:\n%s", out.String())
+	}
+	for _, want := range []string{" 4 ", "80.0%", "WARN", "cache.go:42", `"cache hit for key <n>"`} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("missing %q in %q", want, lines[1])
+		}
+	}
+}
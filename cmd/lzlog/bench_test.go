@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBenchFileTransport(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "bench.log")
+	var out bytes.Buffer
+	args := []string{"-transport", "file", "-formatter", "json", "-target", logPath, "-rate", "2000", "-duration", "200ms"}
+	if err := runBench(args, &out); err != nil {
+		t.Fatalf("bench failed: %v\n%s", err, out.String())
+	}
+	report := out.String()
+	for _, want := range []string{"transportes: file (json)", "alvo 2000/s", "descartes:   0 (0.00%)", "p99", "*lazylog.FileTransport"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := bytes.Count(data, []byte("\n")); n < 100 {
+		t.Errorf("expected the paced load to write at least 100 entries, got %d", n)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chmenegatti/lazylog"
+	"github.com/chmenegatti/lazylog/reader"
+)
+
+// runConvert relê arquivos de log (ou o stdin) e os regrava em outro formato
+// usando os formatters do pacote, ou CSV para relatórios.
+func runConvert(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	in := fs.String("in", "auto", "formato de entrada (auto, json, text)")
+	outFormat := fs.String("out", "json", "formato de saída (json, text, logfmt, csv)")
+	columns := fs.String("columns", "", "csv: campos exportados como colunas (padrão: coluna fields com JSON)")
+	output := fs.String("o", "", "arquivo de saída (padrão: stdout)")
+	files, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	var parse func(line []byte) (lazylog.Entry, bool)
+	switch *in {
+	case "auto":
+		parse = func(line []byte) (lazylog.Entry, bool) { return reader.ParseLine(line), true }
+	case "json":
+		parse = reader.ParseJSON
+	case "text":
+		parse = func(line []byte) (lazylog.Entry, bool) { return reader.ParseText(string(line)), true }
+	default:
+		return fmt.Errorf("formato de entrada desconhecido %q", *in)
+	}
+
+	out := stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	write, flush, err := convertWriter(*outFormat, *columns, out)
+	if err != nil {
+		return err
+	}
+
+	var inputs []io.Reader
+	for _, name := range files {
+		if name == "-" {
+			inputs = append(inputs, stdin)
+			continue
+		}
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		inputs = append(inputs, f)
+	}
+	if len(inputs) == 0 {
+		inputs = append(inputs, stdin)
+	}
+
+	skipped := 0
+	for _, r := range inputs {
+		s := reader.NewScanner(r)
+		for s.Scan() {
+			e, ok := parse(s.Line())
+			if !ok {
+				skipped++
+				continue
+			}
+			if err := write(&e); err != nil {
+				return err
+			}
+		}
+		if err := s.Err(); err != nil {
+			return err
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	if skipped > 0 {
+		return fmt.Errorf("%d linha(s) ignoradas por não estarem no formato %s", skipped, *in)
+	}
+	return nil
+}
+
+// convertWriter retorna a função que grava cada entry no formato pedido e a
+// que finaliza a saída.
+func convertWriter(format, columns string, out io.Writer) (write func(*lazylog.Entry) error, flush func() error, err error) {
+	var formatter lazylog.Formatter
+	switch format {
+	case "json":
+		formatter = &lazylog.JSONFormatter{}
+	case "text":
+		formatter = &lazylog.TextFormatter{}
+	case "logfmt":
+		formatter = &lazylog.LogfmtFormatter{}
+	case "csv":
+		return csvWriter(columns, out)
+	case "parquet":
+		return nil, nil, errors.New("parquet não é suportado (exigiria uma dependência externa); use csv")
+	default:
+		return nil, nil, fmt.Errorf("formato de saída desconhecido %q", format)
+	}
+	write = func(e *lazylog.Entry) error {
+		data, err := formatter.Format(e)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	}
+	return write, func() error { return nil }, nil
+}
+
+// csvWriter grava timestamp, level e message seguidos das colunas pedidas
+// ou, sem colunas, de todos os campos serializados como JSON.
+func csvWriter(columns string, out io.Writer) (func(*lazylog.Entry) error, func() error, error) {
+	var cols []string
+	for _, c := range strings.Split(columns, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			cols = append(cols, c)
+		}
+	}
+	header := []string{"timestamp", "level", "message"}
+	if len(cols) > 0 {
+		header = append(header, cols...)
+	} else {
+		header = append(header, "fields")
+	}
+	w := csv.NewWriter(out)
+	if err := w.Write(header); err != nil {
+		return nil, nil, err
+	}
+	write := func(e *lazylog.Entry) error {
+		record := []string{e.Timestamp.Format(time.RFC3339Nano), e.Level.String(), e.Message}
+		if len(cols) == 0 {
+			fields := ""
+			if len(e.Fields) > 0 {
+				data, err := json.Marshal(e.Fields)
+				if err != nil {
+					return err
+				}
+				fields = string(data)
+			}
+			return w.Write(append(record, fields))
+		}
+		for _, c := range cols {
+			v, ok := e.Fields[c]
+			if !ok {
+				record = append(record, "")
+				continue
+			}
+			record = append(record, fmt.Sprint(v))
+		}
+		return w.Write(record)
+	}
+	flush := func() error {
+		w.Flush()
+		return w.Error()
+	}
+	return write, flush, nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const convertInput = `{"timestamp":"2025-03-01T12:00:00Z","level":"INFO","message":"login","user_id":42}
+{"timestamp":"2025-03-01T12:00:01Z","level":"WARN","message":"slow query","table":"users"}
+`
+
+func TestConvertFormats(t *testing.T) {
+	var out bytes.Buffer
+	if err := runConvert([]string{"-out", "logfmt"}, strings.NewReader(convertInput), &out); err != nil {
+		t.Fatal(err)
+	}
+	want := "time=2025-03-01T12:00:00Z level=info msg=login user_id=42\n" +
+		"time=2025-03-01T12:00:01Z level=warn msg=\"slow query\" table=users\n"
+	if out.String() != want {
+		t.Errorf("unexpected logfmt output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := runConvert([]string{"-out", "csv", "-columns", "user_id,table", "-"}, strings.NewReader(convertInput), &out); err != nil {
+		t.Fatal(err)
+	}
+	want = "timestamp,level,message,user_id,table\n" +
+		"2025-03-01T12:00:00Z,INFO,login,42,\n" +
+		"2025-03-01T12:00:01Z,WARN,slow query,,users\n"
+	if out.String() != want {
+		t.Errorf("unexpected csv output:\n%s", out.String())
+	}
+
+	out.Reset()
+	err := runConvert([]string{"-in", "json", "-out", "text"}, strings.NewReader(convertInput+"not json\n"), &out)
+	if err == nil || !strings.Contains(err.Error(), "1 linha(s) ignoradas") {
+		t.Errorf("expected skipped line error, got %v", err)
+	}
+	if strings.Count(out.String(), "\n") != 2 || !strings.Contains(out.String(), "[WARN] slow query table=users") {
+		t.Errorf("unexpected text output:\n%s", out.String())
+	}
+
+	if err := runConvert([]string{"-out", "parquet"}, strings.NewReader(convertInput), &out); err == nil {
+		t.Error("expected parquet to be rejected")
+	}
+}
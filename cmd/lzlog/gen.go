@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// eventsSchema é o formato do arquivo lido por "lzlog gen":
+//
+//	package: events
+//	imports: [time]        # pacotes usados nos tipos dos campos
+//	events:
+//	  - name: user.signup
+//	    level: INFO
+//	    fields:
+//	      - {name: user_id, type: string}
+//	      - {name: plan, type: string}
+type eventsSchema struct {
+	Package string      `yaml:"package"`
+	Type    string      `yaml:"type"`
+	Imports []string    `yaml:"imports"`
+	Events  []eventSpec `yaml:"events"`
+}
+
+type eventSpec struct {
+	Name    string      `yaml:"name"`
+	Method  string      `yaml:"method"`
+	Level   string      `yaml:"level"`
+	Message string      `yaml:"message"`
+	Fields  []fieldSpec `yaml:"fields"`
+}
+
+type fieldSpec struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	Arg  string `yaml:"-"`
+}
+
+// builtinLevels mapeia nomes de nível para as constantes do lazylog.
+var builtinLevels = map[string]string{
+	"DEBUG": "lazylog.DEBUG",
+	"INFO":  "lazylog.INFO",
+	"WARN":  "lazylog.WARN",
+	"ERROR": "lazylog.ERROR",
+}
+
+func parseSchema(data []byte) (*eventsSchema, error) {
+	var s eventsSchema
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if s.Package == "" {
+		s.Package = "events"
+	}
+	if s.Type == "" {
+		s.Type = "Events"
+	}
+	if !token.IsIdentifier(s.Package) || !token.IsIdentifier(s.Type) {
+		return nil, fmt.Errorf("invalid package or type name %q/%q", s.Package, s.Type)
+	}
+	methods := map[string]bool{}
+	for i := range s.Events {
+		ev := &s.Events[i]
+		if ev.Name == "" {
+			return nil, fmt.Errorf("event #%d has no name", i+1)
+		}
+		if ev.Method == "" {
+			ev.Method = goName(ev.Name, true)
+		}
+		if !token.IsIdentifier(ev.Method) || !token.IsExported(ev.Method) {
+			return nil, fmt.Errorf("event %q: invalid method name %q", ev.Name, ev.Method)
+		}
+		if methods[ev.Method] {
+			return nil, fmt.Errorf("event %q: duplicate method %s", ev.Name, ev.Method)
+		}
+		methods[ev.Method] = true
+		if ev.Level == "" {
+			ev.Level = "INFO"
+		}
+		args := map[string]bool{}
+		for j := range ev.Fields {
+			f := &ev.Fields[j]
+			if f.Name == "" || f.Type == "" {
+				return nil, fmt.Errorf("event %q: field #%d needs name and type", ev.Name, j+1)
+			}
+			f.Arg = goName(f.Name, false)
+			if token.IsKeyword(f.Arg) || f.Arg == "l" || args[f.Arg] {
+				f.Arg += "_"
+			}
+			args[f.Arg] = true
+		}
+	}
+	return &s, nil
+}
+
+// goName converte "user.signup"/"user_id" em "UserSignup"/"userID".
+func goName(s string, exported bool) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for i, p := range parts {
+		if i == 0 && !exported {
+			b.WriteString(strings.ToLower(p))
+			continue
+		}
+		if up := strings.ToUpper(p); up == "ID" || up == "URL" || up == "HTTP" || up == "IP" {
+			b.WriteString(up)
+			continue
+		}
+		b.WriteString(strings.ToUpper(p[:1]) + p[1:])
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "X" + name
+	}
+	return name
+}
+
+var genTemplate = template.Must(template.New("gen").Funcs(template.FuncMap{
+	"level": func(name string) string {
+		if c, ok := builtinLevels[strings.ToUpper(name)]; ok {
+			return c
+		}
+		return fmt.Sprintf("lazylog.ParseLevel(%q)", name)
+	},
+}).Parse(`// Code generated by lzlog gen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{range .Imports}}	{{printf "%q" .}}
+{{end}}
+	"github.com/chmenegatti/lazylog"
+)
+
+// {{.Type}} expõe um método tipado por evento do schema.
+type {{.Type}} struct {
+	*lazylog.Logger
+}
+
+// New envolve o logger com os métodos de evento.
+func New(l *lazylog.Logger) {{.Type}} {
+	return {{.Type}}{Logger: l}
+}
+{{range .Events}}{{$ev := .}}
+// {{.Method}} registra o evento "{{.Name}}".
+func (l {{$.Type}}) {{.Method}}({{range $i, $f := .Fields}}{{if $i}}, {{end}}{{$f.Arg}} {{$f.Type}}{{end}}) {
+	l.Event({{printf "%q" .Name}}).AtLevel({{level .Level}}){{if .Message}}.Message({{printf "%q" .Message}}){{end}}{{if .Fields}}.With(
+{{range .Fields}}		lazylog.Field{Key: {{printf "%q" .Name}}, Value: {{.Arg}}},
+{{end}}	){{end}}.Emit()
+}
+{{end}}`))
+
+// generate produz o código Go formatado para o schema.
+func generate(s *eventsSchema) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := genTemplate.Execute(&buf, s); err != nil {
+		return nil, err
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated code does not compile: %w", err)
+	}
+	return code, nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	data, err := os.ReadFile("testdata/events.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := parseSchema(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := generate(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"func (l Events) UserSignedUp(userID string, plan string) {",
+		`lazylog.Field{Key: "user_id", Value: userID},`,
+		"func (l Events) PaymentFailed(amount float64, type_ string, latency time.Duration) {",
+		`l.Event("payment.failed").AtLevel(lazylog.ERROR).Message("payment declined").With(`,
+		`func (l Events) CacheFlush() {`,
+		`l.Event("cache.flush").AtLevel(lazylog.DEBUG).Emit()`,
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("generated code missing %q:\n%s", want, code)
+		}
+	}
+}
+
+func TestParseSchemaRejectsDuplicates(t *testing.T) {
+	_, err := parseSchema([]byte("events:\n  - name: a.b\n  - name: a_b\n"))
+	if err == nil {
+		t.Error("expected duplicate method error")
+	}
+}
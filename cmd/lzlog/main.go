@@ -0,0 +1,116 @@
+// Command lzlog reúne ferramentas de linha de comando do lazylog.
+//
+//	lzlog gen -in events.yaml -out events_gen.go
+//	lzlog config validate -config logger_config.yaml -probe
+//	lzlog bench -transport file -formatter json -rate 50000
+//	lzlog tail app.log -where 'fields.user_id=="42"' -levels WARN,ERROR
+//	lzlog convert -in json -out logfmt app.log
+//	lzlog analyze -top 20 app.log
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	switch os.Args[1] {
+	case "gen":
+		if err := runGen(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "lzlog gen:", err)
+			os.Exit(1)
+		}
+	case "config":
+		if len(os.Args) < 3 || os.Args[2] != "validate" {
+			usage()
+			os.Exit(2)
+		}
+		if err := runConfigValidate(os.Args[3:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "lzlog config validate:", err)
+			os.Exit(1)
+		}
+	case "bench":
+		if err := runBench(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "lzlog bench:", err)
+			os.Exit(1)
+		}
+	case "tail":
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		err := runTail(ctx, os.Args[2:], os.Stdout)
+		stop()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "lzlog tail:", err)
+			os.Exit(1)
+		}
+	case "convert":
+		if err := runConvert(os.Args[2:], os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "lzlog convert:", err)
+			os.Exit(1)
+		}
+	case "analyze":
+		if err := runAnalyze(os.Args[2:], os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "lzlog analyze:", err)
+			os.Exit(1)
+		}
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "uso: lzlog gen -in events.yaml -out events_gen.go")
+	fmt.Fprintln(os.Stderr, "     lzlog config validate -config logger_config.yaml [-profile prod] [-probe] [-timeout 5s]")
+	fmt.Fprintln(os.Stderr, "     lzlog bench [-config logger_config.yaml] [-transport file] [-formatter json] [-rate 50000] [-duration 5s]")
+	fmt.Fprintln(os.Stderr, "     lzlog tail app.log [-where 'fields.user_id==\"42\"'] [-levels WARN,ERROR] [-follow=false] [-from-start]")
+	fmt.Fprintln(os.Stderr, "     lzlog convert [-in auto|json|text] [-out json|text|logfmt|csv] [-columns a,b] [-o out] [arquivo...]")
+	fmt.Fprintln(os.Stderr, "     lzlog analyze [-top 20] [-max-sites 10000] [arquivo...]")
+}
+
+func runGen(args []string) error {
+	fs := flag.NewFlagSet("gen", flag.ExitOnError)
+	in := fs.String("in", "events.yaml", "schema de eventos (YAML)")
+	out := fs.String("out", "", "arquivo Go gerado (padrão: stdout)")
+	fs.Parse(args)
+
+	data, err := os.ReadFile(*in)
+	if err != nil {
+		return err
+	}
+	schema, err := parseSchema(data)
+	if err != nil {
+		return err
+	}
+	code, err := generate(schema)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	return os.WriteFile(*out, code, 0o644)
+}
+
+// parseArgs processa as flags aceitando argumentos posicionais antes, entre
+// ou depois delas (ex: lzlog tail app.log -where ...).
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
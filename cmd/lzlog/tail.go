@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chmenegatti/lazylog"
+	"github.com/chmenegatti/lazylog/reader"
+)
+
+// runTail filtra as entries de um arquivo de log pela expressão de -where
+// (ver lazylog.ParseFilterExpr) e pelos níveis de -levels, escrevendo as
+// linhas originais que passam. Com -follow (padrão), acompanha o arquivo
+// através de rotações até ctx ser cancelado.
+func runTail(ctx context.Context, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("tail", flag.ContinueOnError)
+	where := fs.String("where", "", `filtro (ex: fields.user_id=="42" && message =~ "timeout")`)
+	levels := fs.String("levels", "", "níveis aceitos, separados por vírgula (ex: WARN,ERROR)")
+	follow := fs.Bool("follow", true, "acompanha o arquivo (como tail -F)")
+	fromStart := fs.Bool("from-start", false, "com -follow, lê também o conteúdo existente")
+	poll := fs.Duration("poll", 250*time.Millisecond, "intervalo de verificação do arquivo")
+	paths, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(paths) != 1 {
+		return errors.New("informe exatamente um arquivo")
+	}
+
+	match, err := tailFilter(*where, *levels)
+	if err != nil {
+		return err
+	}
+	emit := func(line []byte) {
+		e := reader.ParseLine(line)
+		if match(&e) {
+			fmt.Fprintf(out, "%s\n", line)
+		}
+	}
+
+	if *follow {
+		return reader.Follow(ctx, paths[0], reader.FollowOptions{FromStart: *fromStart, Poll: *poll}, emit)
+	}
+	f, err := os.Open(paths[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	s := reader.NewScanner(f)
+	for s.Scan() {
+		emit(s.Line())
+	}
+	return s.Err()
+}
+
+// tailFilter combina -where e -levels num único FilterFunc.
+func tailFilter(where, levels string) (lazylog.FilterFunc, error) {
+	var expr lazylog.FilterFunc
+	if where != "" {
+		f, err := lazylog.ParseFilterExpr(where)
+		if err != nil {
+			return nil, err
+		}
+		expr = f
+	}
+	accepted := map[string]bool{}
+	for _, lvl := range strings.Split(levels, ",") {
+		if lvl = strings.TrimSpace(lvl); lvl != "" {
+			accepted[strings.ToUpper(lvl)] = true
+		}
+	}
+	return func(e *lazylog.Entry) bool {
+		if len(accepted) > 0 && !accepted[e.Level.String()] {
+			return false
+		}
+		return expr == nil || expr(e)
+	}, nil
+}
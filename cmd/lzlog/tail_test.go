@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTailFiltersEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	lines := `{"timestamp":"2025-03-01T12:00:00Z","level":"INFO","message":"login","user_id":42}
+{"timestamp":"2025-03-01T12:00:01Z","level":"WARN","message":"slow query","user_id":42}
+{"timestamp":"2025-03-01T12:00:02Z","level":"ERROR","message":"db down","user_id":7}
+2025-03-01T12:00:03Z [ERROR] payment failed user_id=42 code=500
+`
+	if err := os.WriteFile(path, []byte(lines), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	args := []string{path, "--where", `fields.user_id=="42"`, "--levels", "WARN,ERROR", "-follow=false"}
+	if err := runTail(context.Background(), args, &out); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "slow query") || !strings.Contains(got, "payment failed") || strings.Count(got, "\n") != 2 {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+
+	if err := runTail(context.Background(), []string{"-follow=false"}, &out); err == nil {
+		t.Error("expected error without a file")
+	}
+}
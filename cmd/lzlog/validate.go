@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/chmenegatti/lazylog"
+)
+
+// runConfigValidate carrega a configuração, constrói os transportes e, com
+// -probe, executa Logger.Validate contra cada um deles. -profile seleciona o
+// perfil (padrão: LAZYLOG_PROFILE ou Profile do arquivo).
+func runConfigValidate(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("config validate", flag.ContinueOnError)
+	path := fs.String("config", "logger_config.yaml", "arquivo de configuração (JSON ou YAML)")
+	probe := fs.Bool("probe", false, "testa a escrita/conexão de cada transporte")
+	profile := fs.String("profile", "", "perfil da configuração (ex: prod)")
+	timeout := fs.Duration("timeout", 5*time.Second, "tempo máximo do probe")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(*path)
+	if err != nil {
+		return err
+	}
+	cfg, err := lazylog.ParseLoggerConfig(data)
+	if err != nil {
+		return err
+	}
+	if cfg, err = cfg.Resolve(*profile); err != nil {
+		return err
+	}
+	logger, err := lazylog.NewLoggerFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+	if !*probe {
+		fmt.Fprintf(out, "%s: %d transporte(s) válidos\n", *path, len(cfg.Transports))
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	err = logger.Validate(ctx)
+	failed := map[int]error{}
+	var tverr *lazylog.TransportValidationError
+	for _, e := range unwrapJoined(err) {
+		if errors.As(e, &tverr) {
+			failed[tverr.Index] = tverr.Err
+		}
+	}
+	for i, t := range cfg.Transports {
+		if ferr, ok := failed[i]; ok {
+			fmt.Fprintf(out, "FAIL  #%d %s: %v\n", i, t.Type, ferr)
+		} else {
+			fmt.Fprintf(out, "OK    #%d %s\n", i, t.Type)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d de %d transporte(s) falharam", len(failed), len(cfg.Transports))
+	}
+	return nil
+}
+
+// unwrapJoined separa os erros unidos por errors.Join.
+func unwrapJoined(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
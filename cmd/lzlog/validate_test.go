@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigValidateProbe(t *testing.T) {
+	dir := t.TempDir()
+	cfg := filepath.Join(dir, "logger_config.yaml")
+	yaml := "Transports:\n" +
+		"  - Type: file\n    Options: {path: " + filepath.Join(dir, "app.log") + "}\n" +
+		"  - Type: http\n    Options: {url: \"http://127.0.0.1:1/\"}\n"
+	if err := os.WriteFile(cfg, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := runConfigValidate([]string{"-config", cfg}, &out); err != nil {
+		t.Fatalf("static validation should pass: %v", err)
+	}
+	out.Reset()
+	if err := runConfigValidate([]string{"-config", cfg, "-probe"}, &out); err == nil {
+		t.Fatal("expected probe failure for unreachable http transport")
+	}
+	if !strings.Contains(out.String(), "OK    #0 file") || !strings.Contains(out.String(), "FAIL  #1 http") {
+		t.Errorf("unexpected report:\n%s", out.String())
+	}
+}
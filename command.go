@@ -0,0 +1,95 @@
+package lazylog
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"sync"
+)
+
+// maxCommandLine limita o tamanho de uma linha acumulada; linhas maiores são
+// emitidas em pedaços.
+const maxCommandLine = 64 * 1024
+
+// CommandOutput é o destino da saída de um comando configurado por CommandLogger.
+type CommandOutput struct {
+	stdout, stderr *commandLineWriter
+}
+
+// CommandLogger direciona stdout e stderr do comando para o logger: cada
+// linha vira uma entry no nível informado, com os campos cmd, pid e stream
+// ("stdout"/"stderr"). Deve ser chamado antes de cmd.Start. Após cmd.Wait,
+// chame Flush para emitir uma última linha sem "\n" final, se houver.
+func CommandLogger(cmd *exec.Cmd, logger *Logger, level Level) *CommandOutput {
+	name := filepath.Base(cmd.Path)
+	out := &CommandOutput{
+		stdout: &commandLineWriter{logger: logger, level: level, cmd: cmd, name: name, stream: "stdout"},
+		stderr: &commandLineWriter{logger: logger, level: level, cmd: cmd, name: name, stream: "stderr"},
+	}
+	cmd.Stdout = out.stdout
+	cmd.Stderr = out.stderr
+	return out
+}
+
+// Flush emite o conteúdo pendente (linha sem "\n" final) de stdout e stderr.
+func (o *CommandOutput) Flush() {
+	o.stdout.flush()
+	o.stderr.flush()
+}
+
+// commandLineWriter acumula bytes e emite uma entry por linha.
+type commandLineWriter struct {
+	logger *Logger
+	level  Level
+	cmd    *exec.Cmd
+	name   string
+	stream string
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (w *commandLineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.buf = append(w.buf, p...)
+			for len(w.buf) >= maxCommandLine {
+				w.emit(w.buf[:maxCommandLine])
+				w.buf = append(w.buf[:0], w.buf[maxCommandLine:]...)
+			}
+			break
+		}
+		w.buf = append(w.buf, p[:i]...)
+		w.emit(w.buf)
+		w.buf = w.buf[:0]
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+func (w *commandLineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.emit(w.buf)
+		w.buf = w.buf[:0]
+	}
+}
+
+// emit registra uma linha (sem "\r" final). Deve ser chamado com w.mu.
+func (w *commandLineWriter) emit(line []byte) {
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	pid := 0
+	if w.cmd.Process != nil {
+		pid = w.cmd.Process.Pid
+	}
+	w.logger.logWithFieldSlice(w.level, string(line), []Field{
+		{Key: "cmd", Value: w.name},
+		{Key: "pid", Value: pid},
+		{Key: "stream", Value: w.stream},
+	})
+}
@@ -0,0 +1,165 @@
+package lazylog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// New cria um logger de saída única, compatível com o uso comum do logrus:
+//
+//	logger := lazylog.New()
+//	logger.Out = lumberjackLogger
+//	logger.Formatter = &lazylog.JSONFormatter{}
+//
+// A saída padrão é os.Stderr com TextFormatter e nível INFO (SetLevel). Out
+// e Formatter devem ser atribuídos antes de o logger ser usado; para trocá-los
+// com o logger em uso, use SetOutput e SetFormatter. Transportes adicionais
+// podem ser registrados normalmente com AddTransport. Em loggers criados por
+// NewLogger, atribuir Out e Formatter não tem efeito; use SetOutput.
+func New() *Logger {
+	l := NewLogger()
+	l.Out = os.Stderr
+	l.Formatter = &TextFormatter{}
+	l.AddTransport(&outTransport{logger: l})
+	l.SetLevel(INFO)
+	return l
+}
+
+// SetOutput troca Out de forma segura com o logger em uso. Em loggers que não
+// foram criados por New, a primeira chamada com w != nil adiciona o
+// transporte que escreve em Out com Formatter (TextFormatter se nil), ao lado
+// dos transportes já registrados.
+func (l *Logger) SetOutput(w io.Writer) {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.Out = w
+	if w == nil {
+		return
+	}
+	for _, t := range l.transports {
+		if _, ok := t.(*outTransport); ok {
+			return
+		}
+	}
+	l.transports = cowAppend[Transport](l.transports, &outTransport{logger: l})
+	l.refreshMinLevelLocked()
+}
+
+// SetFormatter troca Formatter de forma segura com o logger em uso. Só tem
+// efeito na saída em Out (ver New e SetOutput).
+func (l *Logger) SetFormatter(f Formatter) {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.Formatter = f
+}
+
+// StdLogger é o conjunto de métodos de impressão do *log.Logger da biblioteca
+// padrão, aceito por muitas bibliotecas de terceiros. *Logger o implementa.
+type StdLogger interface {
+	Print(v ...any)
+	Printf(format string, v ...any)
+	Println(v ...any)
+}
+
+var _ StdLogger = (*Logger)(nil)
+
+// SetPrintLevel define o nível das entries geradas por Print, Println e
+// Printf (padrão: INFO).
+func (l *Logger) SetPrintLevel(level Level) {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.printLevel, l.hasPrintLevel = level, true
+}
+
+// printLevelOf retorna o nível configurado por SetPrintLevel.
+func (l *Logger) printLevelOf() Level {
+	c := l.core()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.hasPrintLevel {
+		return INFO
+	}
+	return c.printLevel
+}
+
+// Print registra os argumentos formatados como fmt.Sprint, no nível de
+// SetPrintLevel.
+func (l *Logger) Print(v ...any) {
+	if level := l.printLevelOf(); l.enabledFor(level) {
+		l.log(level, fmt.Sprint(v...))
+	}
+}
+
+// Println registra os argumentos formatados como fmt.Sprintln (sem a quebra
+// de linha final), no nível de SetPrintLevel.
+func (l *Logger) Println(v ...any) {
+	if level := l.printLevelOf(); l.enabledFor(level) {
+		l.log(level, strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+	}
+}
+
+// Printf registra a mensagem formatada como fmt.Sprintf, no nível de
+// SetPrintLevel.
+func (l *Logger) Printf(format string, v ...any) {
+	if level := l.printLevelOf(); l.enabledFor(level) {
+		l.log(level, fmt.Sprintf(format, v...))
+	}
+}
+
+// outTransport é o transporte criado por New: um WriterTransport que escreve
+// em Logger.Out com Logger.Formatter, lidos a cada entry.
+type outTransport struct {
+	logger *Logger
+
+	mu sync.Mutex
+	w  WriterTransport
+}
+
+func (t *outTransport) WriteLog(entry *Entry) error {
+	_, err := t.writeLogN(entry)
+	return err
+}
+
+func (t *outTransport) writeLogN(entry *Entry) (int, error) {
+	t.logger.mu.RLock()
+	out, formatter := t.logger.Out, t.logger.Formatter
+	t.logger.mu.RUnlock()
+	if out == nil {
+		return 0, nil
+	}
+	if !typedFormatter(formatter) {
+		entry.materializeFields() // Formatter pode ter sido trocado por SetFormatter
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.w.Writer, t.w.Formatter = out, formatter
+	return t.w.writeLogN(entry)
+}
+
+func (t *outTransport) writesTypedFields() bool {
+	return true
+}
+
+func (t *outTransport) MinLevel() Level {
+	return TRACE
+}
+
+// Stats retorna as estatísticas de escrita em Out.
+func (t *outTransport) Stats() TransportStats {
+	return t.w.Stats()
+}
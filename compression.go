@@ -0,0 +1,50 @@
+package lazylog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// CompressorFunc cria um writer que comprime os dados gravados em w.
+type CompressorFunc func(w io.Writer) (io.WriteCloser, error)
+
+var (
+	compressorMu sync.RWMutex
+	compressors  = map[string]CompressorFunc{
+		"gzip": func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+	}
+)
+
+// RegisterCompressor registra um algoritmo de compressão para os transportes
+// HTTP, identificado pelo valor do header Content-Encoding (ex: "zstd").
+// Apenas gzip vem embutido, para não adicionar dependências ao lazylog.
+func RegisterCompressor(encoding string, fn CompressorFunc) {
+	compressorMu.Lock()
+	defer compressorMu.Unlock()
+	compressors[encoding] = fn
+}
+
+// compressBody comprime body com o algoritmo informado.
+func compressBody(encoding string, body []byte) ([]byte, error) {
+	compressorMu.RLock()
+	fn, ok := compressors[encoding]
+	compressorMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("lazylog: unknown compression %q", encoding)
+	}
+	var buf bytes.Buffer
+	w, err := fn(&buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
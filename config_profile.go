@@ -0,0 +1,213 @@
+package lazylog
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+)
+
+// ConfigProfileEnv é a variável de ambiente que seleciona o perfil de
+// LoggerConfig (ex: LAZYLOG_PROFILE=prod). Tem precedência sobre
+// LoggerConfig.Profile.
+const ConfigProfileEnv = "LAZYLOG_PROFILE"
+
+// ProfileConfig é um perfil nomeado de LoggerConfig (dev, staging, prod...).
+// O perfil herda a seção base (ou o perfil em Extends) e só declara o que
+// muda:
+//
+//	Transports:
+//	  - {Name: main, Type: console, Level: DEBUG}
+//	Profiles:
+//	  prod:
+//	    Transports:
+//	      - {Name: main, Level: INFO, Formatter: json}
+//	      - {Type: file, Level: ERROR, Options: {path: "${LOG_DIR:-/var/log}/errors.log"}}
+//
+// Transportes com Name igual a um herdado são mesclados a ele (campos não
+// vazios substituem, Options é mesclado chave a chave); os demais são
+// acrescentados.
+type ProfileConfig struct {
+	Extends       string            `yaml:"Extends"` // perfil herdado; vazio = seção base
+	Transports    []TransportConfig `yaml:"Transports"`
+	PackageLevels string            `yaml:"PackageLevels"` // substitui o herdado se não vazio
+}
+
+// Resolve retorna a configuração efetiva do perfil: a seção base com o perfil
+// (e seus Extends) aplicado e as referências ${VAR} (ou ${VAR:-padrão}) dos
+// valores substituídas pelas variáveis de ambiente. Com profile vazio, usa
+// ConfigProfileEnv e depois LoggerConfig.Profile; sem perfil, só a
+// interpolação é aplicada. NewLoggerFromConfig e ApplyConfig chamam Resolve
+// automaticamente.
+func (cfg LoggerConfig) Resolve(profile string) (LoggerConfig, error) {
+	if cfg.resolved {
+		return cfg, nil
+	}
+	if profile == "" {
+		profile = os.Getenv(ConfigProfileEnv)
+	}
+	if profile == "" {
+		profile = cfg.Profile
+	}
+	out := LoggerConfig{
+		Transports:    append([]TransportConfig(nil), cfg.Transports...),
+		PackageLevels: cfg.PackageLevels,
+	}
+	if profile != "" {
+		var chain []ProfileConfig
+		seen := map[string]bool{}
+		for name := profile; name != ""; {
+			if seen[name] {
+				return LoggerConfig{}, fmt.Errorf("lazylog: config profile %q extends itself", name)
+			}
+			seen[name] = true
+			p, ok := cfg.Profiles[name]
+			if !ok {
+				return LoggerConfig{}, fmt.Errorf("lazylog: unknown config profile %q", name)
+			}
+			chain = append(chain, p)
+			name = p.Extends
+		}
+		for i := len(chain) - 1; i >= 0; i-- {
+			out.applyProfile(chain[i])
+		}
+	}
+	if err := expandConfigEnv(reflect.ValueOf(&out).Elem()); err != nil {
+		return LoggerConfig{}, err
+	}
+	out.resolved = true
+	return out, nil
+}
+
+func (cfg *LoggerConfig) applyProfile(p ProfileConfig) {
+	if p.PackageLevels != "" {
+		cfg.PackageLevels = p.PackageLevels
+	}
+	for _, t := range p.Transports {
+		merged := false
+		if t.Name != "" {
+			for i := range cfg.Transports {
+				if cfg.Transports[i].Name == t.Name {
+					cfg.Transports[i] = mergeTransportConfig(cfg.Transports[i], t)
+					merged = true
+					break
+				}
+			}
+		}
+		if !merged {
+			cfg.Transports = append(cfg.Transports, t)
+		}
+	}
+}
+
+// mergeTransportConfig aplica os campos não vazios de override sobre base.
+func mergeTransportConfig(base, override TransportConfig) TransportConfig {
+	options := make(map[string]any, len(base.Options)+len(override.Options))
+	for k, v := range base.Options {
+		options[k] = v
+	}
+	for k, v := range override.Options {
+		options[k] = v
+	}
+	b := reflect.ValueOf(&base).Elem()
+	o := reflect.ValueOf(override)
+	for i := 0; i < b.NumField(); i++ {
+		if f := o.Field(i); !f.IsZero() {
+			b.Field(i).Set(f)
+		}
+	}
+	base.Options = options
+	return base
+}
+
+var configEnvRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// expandConfigEnv substitui ${VAR} e ${VAR:-padrão} em todas as strings
+// alcançáveis a partir de v. Variáveis não definidas e sem padrão são erro.
+func expandConfigEnv(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.String:
+		s, err := expandEnvRefs(v.String())
+		if err != nil {
+			return err
+		}
+		v.SetString(s)
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(v.Elem())
+		if err := expandConfigEnv(c.Elem()); err != nil {
+			return err
+		}
+		v.Set(c)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				if err := expandConfigEnv(v.Field(i)); err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		// Copia antes de alterar: o slice pode ser compartilhado com a
+		// configuração original.
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(c, v)
+		for i := 0; i < c.Len(); i++ {
+			if err := expandConfigEnv(c.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(c)
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			if err := expandConfigEnv(elem); err != nil {
+				return err
+			}
+			c.SetMapIndex(iter.Key(), elem)
+		}
+		v.Set(c)
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		if err := expandConfigEnv(elem); err != nil {
+			return err
+		}
+		v.Set(elem)
+	}
+	return nil
+}
+
+func expandEnvRefs(s string) (string, error) {
+	var err error
+	out := configEnvRef.ReplaceAllStringFunc(s, func(ref string) string {
+		m := configEnvRef.FindStringSubmatch(ref)
+		val, ok := os.LookupEnv(m[1])
+		switch {
+		case ok && (val != "" || m[2] == ""):
+			return val
+		case m[2] != "":
+			return m[3]
+		}
+		if err == nil {
+			err = fmt.Errorf("lazylog: environment variable %s is not set", m[1])
+		}
+		return ""
+	})
+	return out, err
+}
@@ -9,9 +9,18 @@ type ConsoleTransport struct {
 	Level     Level
 	Formatter Formatter
 	ToStdErr  bool // Se true, escreve no stderr; senão, no stdout
+
+	stats transportStats
 }
 
 func (c *ConsoleTransport) WriteLog(entry *Entry) error {
+	_, err := c.writeLogN(entry)
+	return err
+}
+
+// writeLogN escreve a entry e retorna a quantidade de bytes gravados.
+func (c *ConsoleTransport) writeLogN(entry *Entry) (n int, err error) {
+	defer func() { c.stats.record(c, n, err) }()
 	out := os.Stdout
 	if c.ToStdErr {
 		out = os.Stderr
@@ -22,11 +31,13 @@ func (c *ConsoleTransport) WriteLog(entry *Entry) error {
 	}
 	bytes, err := formatter.Format(entry)
 	if err != nil {
-		_, err2 := out.Write([]byte(entry.Timestamp.Format("2006-01-02T15:04:05Z07:00") + " [" + entry.Level.String() + "] " + entry.Message + "\n"))
-		return err2
+		return out.Write([]byte(entry.Timestamp.Format("2006-01-02T15:04:05Z07:00") + " [" + entry.Level.String() + "] " + entry.Message + "\n"))
 	}
-	_, err = out.Write(bytes)
-	return err
+	return out.Write(bytes)
+}
+
+func (c *ConsoleTransport) writesTypedFields() bool {
+	return typedFormatter(c.Formatter)
 }
 
 func (c *ConsoleTransport) MinLevel() Level {
@@ -0,0 +1,184 @@
+package lazylog
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// CorrelationHeader é o header HTTP que transporta os campos de correlação
+// entre serviços, no formato "chave=valor" separados por vírgula (valores
+// escapados como em URLs, ex: "request_id=abc123,tenant_id=acme").
+const CorrelationHeader = "X-Lazylog-Correlation"
+
+// DefaultCorrelationKeys são os campos propagados quando SetCorrelationKeys
+// não foi chamado.
+var DefaultCorrelationKeys = []string{"request_id", "correlation_id", "tenant_id"}
+
+// maxCorrelationValue limita o tamanho de cada valor aceito de um header
+// externo.
+const maxCorrelationValue = 256
+
+// SetCorrelationKeys define quais campos vinculados (WithFields) são
+// propagados por InjectHeaders e aceitos por ExtractCorrelation. Campos fora
+// da lista nunca saem nem entram pelo header.
+func (l *Logger) SetCorrelationKeys(keys ...string) {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.correlationKeys = append([]string(nil), keys...)
+}
+
+func (l *Logger) correlationKeyList() []string {
+	c := l.core()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.correlationKeys == nil {
+		return DefaultCorrelationKeys
+	}
+	return c.correlationKeys
+}
+
+// CorrelationFields retorna os campos de correlação vinculados ao logger
+// (ex: por WithFields no middleware da requisição atual).
+func (l *Logger) CorrelationFields() map[string]any {
+	if l.core().nop || len(l.bound) == 0 {
+		return nil
+	}
+	var fields map[string]any
+	for _, k := range l.correlationKeyList() {
+		if v, ok := l.bound[k]; ok {
+			if fields == nil {
+				fields = make(map[string]any)
+			}
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+// InjectHeaders grava os campos de correlação do logger em CorrelationHeader,
+// para que o serviço chamado registre os mesmos request_id/tenant_id:
+//
+//	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+//	reqLogger.InjectHeaders(req.Header)
+//
+// Sem campos de correlação, o header não é alterado.
+func (l *Logger) InjectHeaders(h http.Header) {
+	fields := l.CorrelationFields()
+	if len(fields) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = url.QueryEscape(k) + "=" + url.QueryEscape(fmt.Sprint(fields[k]))
+	}
+	h.Set(CorrelationHeader, strings.Join(pairs, ","))
+}
+
+// ExtractCorrelation lê os campos de correlação de CorrelationHeader,
+// aceitando apenas as chaves configuradas (SetCorrelationKeys) e valores de
+// até 256 bytes. Pares malformados são ignorados.
+func (l *Logger) ExtractCorrelation(h http.Header) map[string]any {
+	raw := h.Get(CorrelationHeader)
+	if raw == "" {
+		return nil
+	}
+	allowed := make(map[string]bool)
+	for _, k := range l.correlationKeyList() {
+		allowed[k] = true
+	}
+	var fields map[string]any
+	for _, pair := range strings.Split(raw, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		key, err1 := url.QueryUnescape(k)
+		value, err2 := url.QueryUnescape(v)
+		if err1 != nil || err2 != nil || !allowed[key] || value == "" || len(value) > maxCorrelationValue {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]any)
+		}
+		fields[key] = value
+	}
+	return fields
+}
+
+// CorrelationMiddleware é um middleware net/http que extrai os campos de
+// correlação da requisição e coloca no context um logger derivado com eles
+// (ver LoggerFromContext), para que os logs do handler e as chamadas feitas
+// com CorrelationTransport levem os mesmos campos. trace_id e span_id do
+// header traceparent são acumulados no context (AppendCtxFields).
+func (l *Logger) CorrelationMiddleware(next http.Handler) http.Handler {
+	if l.core().nop {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqLogger := l
+		if fields := l.ExtractCorrelation(r.Header); len(fields) > 0 {
+			reqLogger = l.WithFields(fields)
+		}
+		ctx := ContextWithLogger(r.Context(), reqLogger)
+		if tc, ok := TraceContextFromHeaders(r.Header); ok {
+			ctx = AppendCtxFields(ctx, tc.Fields())
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+type loggerCtxKey struct{}
+
+// ContextWithLogger retorna um context que carrega o logger.
+func ContextWithLogger(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, l)
+}
+
+// LoggerFromContext retorna o logger do context, com os campos de
+// AppendCtxFields vinculados, ou nil (que se comporta como Nop) se não houver.
+func LoggerFromContext(ctx context.Context) *Logger {
+	l, _ := ctx.Value(loggerCtxKey{}).(*Logger)
+	if fields := ctxFields(ctx); l != nil && len(fields) > 0 {
+		return l.WithFields(fields)
+	}
+	return l
+}
+
+// CorrelationTransport é um http.RoundTripper que injeta os campos de
+// correlação do logger do context da requisição (LoggerFromContext) nas
+// chamadas de saída:
+//
+//	client := &http.Client{Transport: &lazylog.CorrelationTransport{}}
+//	req, _ := http.NewRequestWithContext(r.Context(), "GET", url, nil) // r: requisição atual
+//	client.Do(req)
+type CorrelationTransport struct {
+	Base http.RoundTripper // usa http.DefaultTransport se nil
+}
+
+func (t *CorrelationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	l := LoggerFromContext(req.Context())
+	if len(l.CorrelationFields()) == 0 {
+		return base.RoundTrip(req)
+	}
+	// RoundTrippers não devem alterar a requisição original.
+	clone := req.Clone(req.Context())
+	l.InjectHeaders(clone.Header)
+	return base.RoundTrip(clone)
+}
@@ -0,0 +1,202 @@
+package lazylog
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// CostStage é o estágio de degradação de um CostGuardTransport.
+type CostStage int
+
+const (
+	CostNormal     CostStage = iota // tudo é enviado
+	CostSampling                    // entries abaixo de ERROR são amostradas
+	CostErrorsOnly                  // orçamento estourado: só ERROR ou acima
+)
+
+func (s CostStage) String() string {
+	switch s {
+	case CostSampling:
+		return "sampling"
+	case CostErrorsOnly:
+		return "errors-only"
+	}
+	return "normal"
+}
+
+// CostGuardConfig descreve o orçamento diário de um CostGuardTransport (e o
+// campo CostGuard de TransportConfig).
+type CostGuardConfig struct {
+	MaxBytesPerDay   int64   `yaml:"MaxBytesPerDay"`   // 0 = sem limite de bytes
+	MaxEntriesPerDay int64   `yaml:"MaxEntriesPerDay"` // 0 = sem limite de entries
+	SampleAt         float64 `yaml:"SampleAt"`         // fração do orçamento que ativa a amostragem (padrão 0.8)
+	SampleRate       float64 `yaml:"SampleRate"`       // fração mantida durante a amostragem (padrão 0.1)
+	Timezone         string  `yaml:"Timezone"`         // fuso da virada do dia (padrão UTC)
+}
+
+// CostGuardTransport é um decorator que protege transportes remotos (serviços
+// de log cobrados por volume) com um orçamento diário de bytes e/ou entries.
+// Ao atingir SampleAt do orçamento, passa a amostrar as entries abaixo de
+// ERROR; ao esgotá-lo, envia apenas ERROR ou acima até a virada do dia. Cada
+// mudança de estágio gera uma entry de diagnóstico (ver SetDiagnostics).
+//
+// Bytes são contados pelo tamanho formatado quando o transporte o informa
+// (File, Writer, HTTP...); nos demais, pelo tamanho da entry em JSON.
+type CostGuardTransport struct {
+	Transport Transport
+	Name      string // identifica o transporte nas entries de diagnóstico
+	// Now é o relógio que define o dia do orçamento (padrão: time.Now). O
+	// Timestamp da entry não é usado: entries reenviadas ou retroativas
+	// (LogAt, LogEntry, Replay) não podem zerar o orçamento do dia corrente.
+	Now func() time.Time
+
+	cfg      CostGuardConfig
+	loc      *time.Location
+	mu       sync.Mutex
+	day      string
+	bytes    int64
+	entries  int64
+	stage    CostStage
+	rejected int64
+}
+
+// NewCostGuardTransport cria um CostGuardTransport com o orçamento de cfg.
+func NewCostGuardTransport(t Transport, name string, cfg CostGuardConfig) (*CostGuardTransport, error) {
+	if cfg.MaxBytesPerDay <= 0 && cfg.MaxEntriesPerDay <= 0 {
+		return nil, fmt.Errorf("lazylog: cost guard needs MaxBytesPerDay or MaxEntriesPerDay")
+	}
+	if cfg.SampleAt <= 0 || cfg.SampleAt > 1 {
+		cfg.SampleAt = 0.8
+	}
+	if cfg.SampleRate <= 0 || cfg.SampleRate > 1 {
+		cfg.SampleRate = 0.1
+	}
+	loc := time.UTC
+	if cfg.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(cfg.Timezone); err != nil {
+			return nil, fmt.Errorf("lazylog: invalid cost guard timezone %q: %w", cfg.Timezone, err)
+		}
+	}
+	return &CostGuardTransport{Transport: t, Name: name, cfg: cfg, loc: loc}, nil
+}
+
+func (t *CostGuardTransport) WriteLog(entry *Entry) error {
+	if !t.admit(entry) {
+		return nil
+	}
+	n, err := writeLogMeasured(t.Transport, entry)
+	if err != nil {
+		return err
+	}
+	t.record(int64(n))
+	return nil
+}
+
+// admit decide, pelo estágio atual, se a entry será enviada.
+func (t *CostGuardTransport) admit(entry *Entry) bool {
+	var reset map[string]any
+	defer func() {
+		if reset != nil {
+			diagnose(INFO, "cost budget reset", reset)
+		}
+	}()
+	now := time.Now
+	if t.Now != nil {
+		now = t.Now
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if day := now().In(t.loc).Format(time.DateOnly); day != t.day {
+		if t.day != "" && t.stage != CostNormal {
+			reset = t.diagnosticFieldsLocked(CostNormal)
+		}
+		t.day, t.bytes, t.entries, t.stage, t.rejected = day, 0, 0, CostNormal, 0
+	}
+	keep := true
+	switch {
+	case entry.Level >= ERROR:
+	case t.stage == CostErrorsOnly:
+		keep = false
+	case t.stage == CostSampling:
+		keep = rand.Float64() < t.cfg.SampleRate
+	}
+	if !keep {
+		t.rejected++
+	}
+	return keep
+}
+
+// record contabiliza uma entry enviada e avança o estágio se necessário. A
+// entry de diagnóstico é emitida fora do lock: o logger de diagnóstico pode
+// escrever neste mesmo transporte.
+func (t *CostGuardTransport) record(n int64) {
+	t.mu.Lock()
+	t.bytes += n
+	t.entries++
+	usage := 0.0
+	if t.cfg.MaxBytesPerDay > 0 {
+		usage = float64(t.bytes) / float64(t.cfg.MaxBytesPerDay)
+	}
+	if t.cfg.MaxEntriesPerDay > 0 {
+		usage = max(usage, float64(t.entries)/float64(t.cfg.MaxEntriesPerDay))
+	}
+	stage := CostNormal
+	switch {
+	case usage >= 1:
+		stage = CostErrorsOnly
+	case usage >= t.cfg.SampleAt:
+		stage = CostSampling
+	}
+	var degraded map[string]any
+	if stage > t.stage {
+		t.stage = stage
+		degraded = t.diagnosticFieldsLocked(stage)
+	}
+	t.mu.Unlock()
+	if degraded != nil {
+		diagnose(WARN, "cost budget degraded", degraded)
+	}
+}
+
+func (t *CostGuardTransport) diagnosticFieldsLocked(stage CostStage) map[string]any {
+	name := t.Name
+	if name == "" {
+		name = fmt.Sprintf("%T", t.Transport)
+	}
+	return map[string]any{
+		"transport":           name,
+		"stage":               stage.String(),
+		"bytes":               t.bytes,
+		"entries":             t.entries,
+		"rejected":            t.rejected,
+		"max_bytes_per_day":   t.cfg.MaxBytesPerDay,
+		"max_entries_per_day": t.cfg.MaxEntriesPerDay,
+	}
+}
+
+// Stage retorna o estágio atual de degradação.
+func (t *CostGuardTransport) Stage() CostStage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stage
+}
+
+// Usage retorna os bytes e entries enviados e as entries descartadas pelo
+// orçamento no dia corrente.
+func (t *CostGuardTransport) Usage() (bytes, entries, rejected int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.bytes, t.entries, t.rejected
+}
+
+func (t *CostGuardTransport) MinLevel() Level {
+	return t.Transport.MinLevel()
+}
+
+// Close fecha o transporte envolvido (se ele implementar io.Closer).
+func (t *CostGuardTransport) Close() error {
+	return closeTransport(t.Transport)
+}
@@ -0,0 +1,114 @@
+package lazylog
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// crashMonitorEnv marca o processo monitor iniciado por MonitorCrashes.
+const crashMonitorEnv = "LAZYLOG_CRASH_MONITOR"
+
+// MonitorCrashes garante que panics não recuperados e erros fatais do runtime
+// (throws, faults, deadlocks) virem uma entry estruturada final, mesmo que o
+// processo morra sem executar defers. Deve ser a primeira chamada do main.
+//
+// No processo principal, re-executa o próprio binário como monitor e direciona
+// para ele a saída de crash do runtime (debug.SetCrashOutput). O monitor lê o
+// relatório e, se o processo principal tiver falhado, cria um logger com
+// newLogger e registra uma entry PANIC "process crashed" com os campos panic
+// (panic.value e panic.stack, no mesmo formato de Panic), crash_report
+// (relatório completo) e pid; depois encerra.
+// newLogger só é chamado no processo monitor.
+func MonitorCrashes(newLogger func() *Logger) error {
+	if os.Getenv(crashMonitorEnv) != "" {
+		runCrashMonitor(newLogger)
+		os.Exit(0)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Env = append(os.Environ(), crashMonitorEnv+"="+strconv.Itoa(os.Getpid()))
+	cmd.Stdin = r
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		r.Close()
+		w.Close()
+		return err
+	}
+	r.Close()
+	// SetCrashOutput duplica o descritor: o pipe fica aberto até o processo
+	// terminar, e o monitor recebe EOF (sem conteúdo) numa saída normal.
+	err = debug.SetCrashOutput(w, debug.CrashOptions{})
+	w.Close()
+	go cmd.Wait() // evita processo zumbi se o monitor terminar antes
+	return err
+}
+
+// runCrashMonitor lê o relatório de crash do processo principal pelo stdin.
+func runCrashMonitor(newLogger func() *Logger) {
+	report, _ := io.ReadAll(os.Stdin)
+	if len(report) == 0 {
+		return
+	}
+	text := string(report)
+	pid, _ := strconv.Atoi(os.Getenv(crashMonitorEnv))
+	logger := newLogger()
+	logger.LogEntry(&Entry{
+		Level:     PANIC,
+		Timestamp: time.Now(),
+		Message:   "process crashed",
+		Fields: map[string]interface{}{
+			"panic":        crashPanicFields(text),
+			"crash_report": text,
+			"pid":          pid,
+		},
+		FieldOrder: []string{"panic", "pid", "crash_report"},
+	})
+	logger.Close()
+}
+
+// crashPanicFields extrai de um relatório de crash do runtime o valor (a
+// primeira linha, sem o prefixo "panic: " ou "fatal error: ") e a pilha da
+// goroutine que falhou, como lista de "func (file:line)". O tipo do valor não
+// aparece no relatório, por isso panic.type é omitido.
+func crashPanicFields(report string) map[string]any {
+	first, rest, _ := strings.Cut(report, "\n")
+	value := first
+	for _, prefix := range []string{"panic: ", "fatal error: "} {
+		if v, ok := strings.CutPrefix(first, prefix); ok {
+			value = v
+			break
+		}
+	}
+	var stack []string
+	lines := strings.Split(rest, "\n")
+	for i := 0; i < len(lines); i++ {
+		if !strings.HasPrefix(lines[i], "goroutine ") {
+			continue
+		}
+		for i++; i+1 < len(lines) && lines[i] != ""; i += 2 {
+			fn := strings.TrimPrefix(lines[i], "created by ")
+			if j := strings.LastIndex(fn, " in goroutine "); j >= 0 {
+				fn = fn[:j]
+			} else if j := strings.LastIndex(fn, "("); j > 0 && strings.HasSuffix(fn, ")") {
+				fn = fn[:j]
+			}
+			loc, _, _ := strings.Cut(strings.TrimSpace(lines[i+1]), " +0x")
+			stack = append(stack, fn+" ("+loc+")")
+		}
+		break
+	}
+	return map[string]any{"value": value, "stack": stack}
+}
@@ -0,0 +1,117 @@
+package lazylog
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// crashDumper mantém as entries recentes e grava relatórios de crash.
+type crashDumper struct {
+	dir string
+
+	mu     sync.Mutex
+	recent []*Entry // buffer circular
+	next   int
+	full   bool
+}
+
+// EnableCrashDump faz com que Fatal e Panic gravem, antes de encerrar, um
+// relatório completo em dir/crash-<timestamp>-<pid>.txt com a mensagem, as
+// informações de build, as últimas recent entries registradas e a pilha de
+// todas as goroutines, para que exista material de post-mortem mesmo quando
+// os sinks remotos estavam inacessíveis.
+func (l *Logger) EnableCrashDump(dir string, recent int) {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	if recent <= 0 {
+		recent = 100
+	}
+	d := &crashDumper{dir: dir, recent: make([]*Entry, recent)}
+	l.mu.Lock()
+	l.crashDump = d
+	l.mu.Unlock()
+	l.AddHook(d.record, false)
+}
+
+// record guarda uma cópia da entry no buffer circular (after-hook).
+func (d *crashDumper) record(entry *Entry) {
+	c := entry.Clone()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.recent[d.next] = c
+	d.next = (d.next + 1) % len(d.recent)
+	if d.next == 0 {
+		d.full = true
+	}
+}
+
+// writeCrashDump grava o relatório, se EnableCrashDump estiver ativo, e
+// retorna o caminho do arquivo ("" se desativado ou em caso de erro).
+func (l *Logger) writeCrashDump(kind, message string) string {
+	l = l.core()
+	l.mu.RLock()
+	d := l.crashDump
+	l.mu.RUnlock()
+	if d == nil {
+		return ""
+	}
+	now := time.Now()
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s: %s\n", kind, message)
+	fmt.Fprintf(&b, "time: %s\npid: %d\ngo: %s %s/%s\n", now.Format(time.RFC3339Nano), os.Getpid(), runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if info, ok := debug.ReadBuildInfo(); ok {
+		b.WriteString("\n=== build info ===\n")
+		b.WriteString(info.String())
+	}
+	b.WriteString("\n=== recent entries ===\n")
+	json := &JSONFormatter{}
+	for _, e := range d.snapshot() {
+		if out, err := json.Format(e); err == nil {
+			b.Write(out)
+		}
+	}
+	b.WriteString("\n=== goroutines ===\n")
+	b.Write(allGoroutineStacks())
+
+	if err := os.MkdirAll(d.dir, 0o755); err != nil {
+		return ""
+	}
+	path := filepath.Join(d.dir, fmt.Sprintf("crash-%s-%d.txt", now.UTC().Format("20060102T150405.000000000Z"), os.Getpid()))
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		return ""
+	}
+	return path
+}
+
+// snapshot retorna as entries recentes em ordem cronológica.
+func (d *crashDumper) snapshot() []*Entry {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.full {
+		return append([]*Entry(nil), d.recent[:d.next]...)
+	}
+	return append(append([]*Entry(nil), d.recent[d.next:]...), d.recent[:d.next]...)
+}
+
+// allGoroutineStacks retorna a pilha de todas as goroutines.
+func allGoroutineStacks() []byte {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		if len(buf) >= 64<<20 {
+			return buf
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,36 @@
+package lazylog
+
+import "context"
+
+type ctxFieldsKey struct{}
+
+// AppendCtxFields retorna um context que carrega fields além dos campos já
+// acumulados em ctx (fields prevalece em chaves repetidas). Os métodos *Ctx e
+// os loggers obtidos com LoggerFromContext incluem esses campos, propagando-os
+// pela pilha de chamadas sem passar o logger adiante:
+//
+//	ctx = lazylog.AppendCtxFields(ctx, map[string]any{"order_id": id})
+//	...
+//	logger.InfoCtx(ctx, "payment captured", nil) // inclui order_id
+//
+// Em cada entry, os campos explícitos prevalecem sobre os do context, que
+// prevalecem sobre os vinculados com WithFields.
+func AppendCtxFields(ctx context.Context, fields map[string]any) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxFieldsKey{}, mergeBoundFields(ctxFields(ctx), fields))
+}
+
+// CtxFields retorna uma cópia dos campos acumulados em ctx por
+// AppendCtxFields (nil se não houver).
+func CtxFields(ctx context.Context) map[string]any {
+	return cloneFields(ctxFields(ctx))
+}
+
+// ctxFields retorna o mapa guardado no context, que nunca é alterado depois
+// de criado.
+func ctxFields(ctx context.Context) map[string]any {
+	fields, _ := ctx.Value(ctxFieldsKey{}).(map[string]any)
+	return fields
+}
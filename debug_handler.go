@@ -0,0 +1,151 @@
+package lazylog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed debug_ui.html
+var debugUI []byte
+
+// DebugHandler serve um visualizador de logs para o RingBufferTransport, para
+// deploys pequenos terem onde olhar os logs sem infraestrutura:
+//
+//	ring := lazylog.NewRingBufferTransport(5000, lazylog.DEBUG)
+//	logger.AddTransport(ring)
+//	http.Handle("/debug/logs/", http.StripPrefix("/debug/logs", lazylog.DebugHandler(ring)))
+//
+// Rotas (relativas ao prefixo):
+//
+//	/         página HTML com live tail, filtro por nível, busca e JSON expandido
+//	/entries  entries guardadas em JSON
+//	/tail     live tail em Server-Sent Events
+//	/query    busca via QueryHandler (mais recentes primeiro)
+//
+// /entries e /tail aceitam os parâmetros de ParseQuery (exceto limit) mais
+// after (sequência) e where (expressão de ParseFilterExpr). Não há
+// autenticação: proteja a rota como os demais endpoints de debug.
+func DebugHandler(ring *RingBufferTransport) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimSuffix(r.URL.Path, "/") {
+		case "", "/index.html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write(debugUI)
+		case "/entries":
+			serveRingEntries(ring, w, r)
+		case "/tail":
+			serveRingTail(ring, w, r)
+		case "/query":
+			QueryHandler(ring).ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// ringQuery filtra as entries consultadas pelo DebugHandler.
+type ringQuery struct {
+	Query
+	where FilterFunc
+}
+
+func (q ringQuery) match(e *Entry) bool {
+	return q.Query.Match(e) && (q.where == nil || q.where(e))
+}
+
+func parseRingQuery(r *http.Request) (ringQuery, uint64, error) {
+	params := r.URL.Query()
+	var q ringQuery
+	var after uint64
+	if s := params.Get("after"); s != "" {
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return q, 0, fmt.Errorf("invalid after %q", s)
+		}
+		after = v
+	}
+	var err error
+	if q.Query, err = ParseQuery(params); err != nil {
+		return q, 0, err
+	}
+	if s := params.Get("where"); s != "" {
+		if q.where, err = ParseFilterExpr(s); err != nil {
+			return q, 0, err
+		}
+	}
+	return q, after, nil
+}
+
+func serveRingEntries(ring *RingBufferTransport, w http.ResponseWriter, r *http.Request) {
+	q, after, err := parseRingQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	records := []RingRecord{}
+	for _, rec := range ring.Records(after) {
+		if q.match(&rec.Entry) {
+			records = append(records, rec)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(records)
+}
+
+func serveRingTail(ring *RingBufferTransport, w http.ResponseWriter, r *http.Request) {
+	q, after, err := parseRingQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	ch, backlog := ring.subscribe(after)
+	defer ring.unsubscribe(ch)
+	send := func(rec RingRecord) error {
+		if rec.Seq <= after {
+			return nil
+		}
+		after = rec.Seq
+		if !q.match(&rec.Entry) {
+			return nil
+		}
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", rec.Seq, data)
+		return err
+	}
+	for _, rec := range backlog {
+		if send(rec) != nil {
+			return
+		}
+	}
+	rc.Flush()
+	keepAlive := time.NewTicker(15 * time.Second)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case rec := <-ch:
+			if send(rec) != nil {
+				return
+			}
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		if rc.Flush() != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package debugonly_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/chmenegatti/lazylog"
+	"github.com/chmenegatti/lazylog/debugonly"
+)
+
+func TestDebugOnly(t *testing.T) {
+	var buf bytes.Buffer
+	logger := lazylog.NewLogger()
+	logger.AddTransport(&lazylog.WriterTransport{Writer: &buf, Level: lazylog.TRACE, Formatter: &lazylog.JSONFormatter{}})
+
+	debugonly.Debug(logger, "debug message")
+	debugonly.Trace(logger, "trace message")
+	debugonly.DebugFields(logger, "debug fields", lazylog.Field{Key: "k", Value: 1})
+	debugonly.TraceFields(logger, "trace fields", lazylog.Field{Key: "k", Value: 2})
+
+	lines := strings.Count(buf.String(), "\n")
+	if !debugonly.Enabled {
+		if lines != 0 {
+			t.Errorf("without the lazylogdebug tag nothing must be logged, got:\n%s", buf.String())
+		}
+		return
+	}
+	if lines != 4 || !strings.Contains(buf.String(), `"level":"TRACE","message":"trace fields","k":2`) {
+		t.Errorf("unexpected output with lazylogdebug:\n%s", buf.String())
+	}
+}
+
+func TestDebugOnlyCallerSkipsFacade(t *testing.T) {
+	if !debugonly.Enabled {
+		t.Skip("requires -tags lazylogdebug")
+	}
+	var buf bytes.Buffer
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: &buf, Level: lazylog.TRACE, Formatter: &lazylog.JSONFormatter{}})
+	logger.EnableCaller(0)
+	debugonly.Debug(logger, "from test")
+	if !strings.Contains(buf.String(), `"caller":"debugonly/debugonly_test.go:`) {
+		t.Errorf("caller must point at the test, not the facade:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	logger.SetPackageLevel("github.com/chmenegatti/lazylog/debugonly_test", lazylog.INFO)
+	debugonly.Debug(logger, "filtered")
+	if buf.Len() != 0 {
+		t.Errorf("package level must apply to the caller's package:\n%s", buf.String())
+	}
+}
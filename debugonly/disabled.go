@@ -0,0 +1,20 @@
+//go:build !lazylogdebug
+
+package debugonly
+
+import "github.com/chmenegatti/lazylog"
+
+// Enabled indica se o binário foi compilado com a build tag lazylogdebug.
+const Enabled = false
+
+// Debug não faz nada sem a build tag lazylogdebug.
+func Debug(l *lazylog.Logger, msg string) {}
+
+// Trace não faz nada sem a build tag lazylogdebug.
+func Trace(l *lazylog.Logger, msg string) {}
+
+// DebugFields não faz nada sem a build tag lazylogdebug.
+func DebugFields(l *lazylog.Logger, msg string, fields ...lazylog.Field) {}
+
+// TraceFields não faz nada sem a build tag lazylogdebug.
+func TraceFields(l *lazylog.Logger, msg string, fields ...lazylog.Field) {}
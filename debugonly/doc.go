@@ -0,0 +1,18 @@
+// Package debugonly é uma fachada de logging de depuração removida na
+// compilação: sem a build tag lazylogdebug, Debug, Trace e as variantes
+// *Fields são funções vazias que o compilador elimina, permitindo que
+// bibliotecas sensíveis a desempenho mantenham instrumentação detalhada sem
+// custo nas builds de release.
+//
+//	debugonly.Debug(logger, "cache miss")
+//	debugonly.TraceFields(logger, "frame decoded", lazylog.Int("size", n))
+//
+// Os argumentos continuam sendo avaliados; para preparar dados caros, use a
+// constante Enabled, que elimina o bloco inteiro nas builds de release:
+//
+//	if debugonly.Enabled {
+//	    debugonly.DebugFields(logger, "state", lazylog.Any("dump", expensiveDump()))
+//	}
+//
+// Para habilitar: go build -tags lazylogdebug ./...
+package debugonly
@@ -0,0 +1,28 @@
+//go:build lazylogdebug
+
+package debugonly
+
+import "github.com/chmenegatti/lazylog"
+
+// Enabled indica se o binário foi compilado com a build tag lazylogdebug.
+const Enabled = true
+
+// Debug registra uma mensagem DEBUG no logger.
+func Debug(l *lazylog.Logger, msg string) {
+	l.Debug(msg)
+}
+
+// Trace registra uma mensagem TRACE no logger.
+func Trace(l *lazylog.Logger, msg string) {
+	l.Trace(msg)
+}
+
+// DebugFields registra uma mensagem DEBUG com os campos informados.
+func DebugFields(l *lazylog.Logger, msg string, fields ...lazylog.Field) {
+	l.DebugFields(msg, fields...)
+}
+
+// TraceFields registra uma mensagem TRACE com os campos informados.
+func TraceFields(l *lazylog.Logger, msg string, fields ...lazylog.Field) {
+	l.TraceFields(msg, fields...)
+}
@@ -0,0 +1,29 @@
+package lazylog
+
+// SetDefaultFields define campos estáticos (env, region, service...)
+// adicionados a toda entry antes dos hooks, sem o custo de um before-hook.
+// Campos explícitos e os de WithFields prevalecem; nil remove os campos
+// padrão. O mapa é copiado.
+func (l *Logger) SetDefaultFields(fields map[string]any) {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.defaultFields = cloneFields(fields)
+	l.refreshStaticFieldsLocked()
+}
+
+// refreshStaticFieldsLocked recalcula o mapa (imutável) mesclado nas entries
+// pelo dispatchEntry. Deve ser chamado com l.mu travado.
+func (l *Logger) refreshStaticFieldsLocked() {
+	switch {
+	case len(l.defaultFields) == 0:
+		l.staticFields = l.processFields
+	case len(l.processFields) == 0:
+		l.staticFields = l.defaultFields
+	default:
+		l.staticFields = mergeBoundFields(l.processFields, l.defaultFields)
+	}
+}
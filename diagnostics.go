@@ -0,0 +1,27 @@
+package lazylog
+
+import "sync/atomic"
+
+// DiagnosticsField marca as entries geradas internamente pelo lazylog.
+const DiagnosticsField = "lazylog_internal"
+
+var diagnostics atomic.Pointer[Logger]
+
+// SetDiagnostics define o logger que recebe as entries internas do lazylog,
+// como a recuperação de um transporte após falhas (com downtime_ms e a
+// quantidade de entries perdidas em dropped), úteis para montar a linha do
+// tempo de incidentes. As entries levam o campo DiagnosticsField. nil (o
+// padrão) desativa.
+//
+// O próprio logger da aplicação pode ser usado: a entry de recuperação é
+// emitida depois que o transporte volta a aceitar escritas.
+func SetDiagnostics(l *Logger) {
+	diagnostics.Store(l)
+}
+
+// diagnose registra uma entry interna no logger de diagnóstico, se houver.
+func diagnose(level Level, message string, fields map[string]any) {
+	if l := diagnostics.Load(); l != nil {
+		l.logWithFields(level, message, withField(fields, DiagnosticsField, true))
+	}
+}
@@ -1,10 +1,148 @@
 package lazylog
 
-import "time"
+import (
+	"context"
+	"sort"
+	"time"
+)
 
 type Entry struct {
 	Level     Level
 	Timestamp time.Time
 	Message   string
 	Fields    map[string]interface{} // Para metadata/contexto extra
+	// FieldOrder, se definido, é a ordem de inserção das chaves de Fields
+	// (preenchida pela API de campos tipados). Os formatters a respeitam e
+	// escrevem as demais chaves depois.
+	FieldOrder []string
+	// Caller é o ponto do código que registrou a entry (nil se EnableCaller
+	// não estiver ativo).
+	Caller *Caller
+	// Function é o nome qualificado da função que registrou a entry
+	// (ex: "github.com/acme/app/db.(*Repo).Find"; vazio se EnableFunction não
+	// estiver ativo).
+	Function string
+
+	// typed guarda os campos das variantes *Fields enquanto a entry segue pelo
+	// caminho rápido (sem hooks, encoders ou transportes que leiam Fields):
+	// TextFormatter e JSONFormatter os escrevem em ordem, sem montar o mapa.
+	// materializeFields os move para Fields antes de qualquer código que leia
+	// o mapa. Com typed != nil, Fields e FieldOrder são nil.
+	typed []Field
+
+	ctx context.Context // context da chamada (métodos *Ctx), se houver
+}
+
+// Context retorna o context.Context associado à entry pelos métodos *Ctx,
+// ou context.Background() quando a entry foi criada sem context.
+func (e *Entry) Context() context.Context {
+	if e.ctx == nil {
+		return context.Background()
+	}
+	return e.ctx
+}
+
+// shadowsMeta informa se o campo k colide com um metadado escrito pelos
+// formatters (caller, function) e deve receber o prefixo "fields.".
+func (e *Entry) shadowsMeta(k string) bool {
+	return k == "caller" && e.Caller != nil || k == "function" && e.Function != ""
+}
+
+// Clone retorna uma cópia da entry cujo mapa de campos (incluindo mapas
+// aninhados) não é compartilhado com o original. Deve ser usado sempre que
+// uma entry cruza uma fronteira (fila, goroutine, transporte paralelo).
+func (e *Entry) Clone() *Entry {
+	c := *e
+	c.Fields = cloneFields(e.Fields)
+	if e.typed != nil {
+		c.typed = append([]Field(nil), e.typed...)
+	}
+	if e.FieldOrder != nil {
+		c.FieldOrder = append([]string(nil), e.FieldOrder...)
+	}
+	return &c
+}
+
+// materializeFields move os campos tipados para Fields e FieldOrder.
+func (e *Entry) materializeFields() {
+	if e.typed == nil {
+		return
+	}
+	e.Fields = fieldsToMap(e.typed)
+	e.FieldOrder = fieldKeys(e.typed)
+	e.typed = nil
+}
+
+// hasFields indica se a entry tem campos, no mapa ou tipados.
+func (e *Entry) hasFields() bool {
+	return len(e.Fields) > 0 || len(e.typed) > 0
+}
+
+// cloneFields copia fields recursivamente para mapas aninhados.
+func cloneFields(fields map[string]interface{}) map[string]interface{} {
+	if fields == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		if m, ok := v.(map[string]interface{}); ok {
+			v = cloneFields(m)
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// withField retorna uma cópia de fields com key=value, sem alterar o mapa
+// original (que pode pertencer ao chamador).
+func withField(fields map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(fields)+1)
+	for k, v := range fields {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
+
+// orderedKeys retorna as chaves de fields na ordem de FieldOrder, seguidas das
+// chaves restantes (ex: adicionadas por hooks) em ordem alfabética.
+func (e *Entry) orderedKeys() []string {
+	if e.orderCoversFields() {
+		return e.FieldOrder
+	}
+	keys := make([]string, 0, len(e.Fields))
+	seen := make(map[string]bool, len(e.FieldOrder))
+	for _, k := range e.FieldOrder {
+		if _, ok := e.Fields[k]; ok && !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	var rest []string
+	for k := range e.Fields {
+		if !seen[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+// orderCoversFields indica se FieldOrder já lista cada campo exatamente uma
+// vez (o caso comum das variantes *Fields sem hooks), dispensando a cópia.
+func (e *Entry) orderCoversFields() bool {
+	if len(e.FieldOrder) != len(e.Fields) || len(e.FieldOrder) > 16 {
+		return false
+	}
+	for i, k := range e.FieldOrder {
+		if _, ok := e.Fields[k]; !ok {
+			return false
+		}
+		for _, prev := range e.FieldOrder[:i] {
+			if prev == k {
+				return false
+			}
+		}
+	}
+	return true
 }
@@ -0,0 +1,92 @@
+package lazylog
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EntryWireVersion é a versão atual do formato EntryWire.
+const EntryWireVersion = 1
+
+// EntryWire é o formato estável de serialização de uma Entry, usado por
+// Entry.MarshalJSON/UnmarshalJSON e pelo ForwardTransport, para que entries
+// possam ser trocadas entre processos, enfileiradas e reconstruídas sem perder
+// nível e timestamp. Novos campos serão sempre opcionais; mudanças
+// incompatíveis incrementam Version.
+type EntryWire struct {
+	Version    int                    `json:"v"`
+	Level      string                 `json:"level"`
+	LevelNum   int                    `json:"level_num"` // valor numérico (níveis customizados não registrados no leitor)
+	Timestamp  time.Time              `json:"timestamp"` // RFC 3339 com nanossegundos e offset original
+	Message    string                 `json:"message"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+	FieldOrder []string               `json:"field_order,omitempty"` // ordem dos campos tipados
+	Caller     *Caller                `json:"caller,omitempty"`
+	Function   string                 `json:"function,omitempty"`
+}
+
+// Wire converte a entry para o formato de serialização.
+func (e *Entry) Wire() EntryWire {
+	return EntryWire{
+		Version:    EntryWireVersion,
+		Level:      e.Level.String(),
+		LevelNum:   int(e.Level),
+		Timestamp:  e.Timestamp,
+		Message:    e.Message,
+		Fields:     e.Fields,
+		FieldOrder: e.FieldOrder,
+		Caller:     e.Caller,
+		Function:   e.Function,
+	}
+}
+
+// Entry reconstrói uma entry. O nível é resolvido pelo nome e, se o nome não
+// estiver registrado neste processo, pelo valor numérico.
+func (w EntryWire) Entry() (Entry, error) {
+	if w.Version > EntryWireVersion {
+		return Entry{}, fmt.Errorf("lazylog: unsupported entry wire version %d", w.Version)
+	}
+	level, ok := lookupLevel(w.Level)
+	if !ok {
+		level = Level(w.LevelNum)
+	}
+	return Entry{
+		Level:      level,
+		Timestamp:  w.Timestamp,
+		Message:    w.Message,
+		Fields:     w.Fields,
+		FieldOrder: w.FieldOrder,
+		Caller:     w.Caller,
+		Function:   w.Function,
+	}, nil
+}
+
+// MarshalJSON serializa a entry no formato EntryWire. Campos que não podem ser
+// codificados são degradados individualmente (como no JSONFormatter), com os
+// erros em fields._encode_error.
+func (e Entry) MarshalJSON() ([]byte, error) {
+	w := e.Wire()
+	data, err := json.Marshal(w)
+	if err == nil {
+		return data, nil
+	}
+	encodeErrs := make(map[string]string)
+	w.Fields = jsonSafeFields(e.Fields, "", encodeErrs, map[uintptr]bool{})
+	w.Fields["_encode_error"] = encodeErrs
+	return json.Marshal(w)
+}
+
+// UnmarshalJSON reconstrói a entry a partir do formato EntryWire.
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	var w EntryWire
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	entry, err := w.Entry()
+	if err != nil {
+		return err
+	}
+	*e = entry
+	return nil
+}
@@ -0,0 +1,179 @@
+package lazylog
+
+import (
+	"sync"
+	"time"
+)
+
+// ErrorBudgetConfig configura o acompanhamento da taxa de erros.
+type ErrorBudgetConfig struct {
+	Window  time.Duration // janela deslizante (padrão: 5m)
+	Buckets int           // resolução da janela (padrão: 10)
+	// Threshold é a fração máxima de entries ERROR+ na janela (ex: 0.01 = 1%).
+	Threshold float64
+	// MinEntries evita alarmes com pouco tráfego: a taxa só é avaliada com
+	// pelo menos esse número de entries na janela (padrão: 1).
+	MinEntries uint64
+	// OnExceeded é chamado quando a taxa cruza o Threshold (uma vez por
+	// cruzamento; volta a ser chamado depois que a taxa ficar abaixo).
+	OnExceeded func(ErrorBudgetStatus)
+	// SummaryEvery, se > 0, emite periodicamente uma entry de resumo
+	// (WARN quando o orçamento estiver estourado, senão INFO).
+	SummaryEvery time.Duration
+}
+
+// ErrorBudgetStatus é o estado da janela deslizante.
+type ErrorBudgetStatus struct {
+	Window   time.Duration
+	Total    uint64           // entries na janela
+	Errors   uint64           // entries ERROR+ na janela
+	ByLevel  map[Level]uint64 // histograma de severidade na janela
+	Rate     float64          // Errors / Total
+	Exceeded bool             // Rate > Threshold (com MinEntries atingido)
+}
+
+// ErrorBudget acompanha a taxa de erros de um logger; criado por
+// EnableErrorBudget.
+type ErrorBudget struct {
+	logger *Logger
+	cfg    ErrorBudgetConfig
+	width  time.Duration
+
+	mu       sync.Mutex
+	buckets  []budgetBucket
+	exceeded bool
+
+	stop chan struct{}
+	once sync.Once
+}
+
+type budgetBucket struct {
+	start  time.Time // início do intervalo (zero se vazio)
+	counts map[Level]uint64
+}
+
+// EnableErrorBudget passa a contar as entries por nível numa janela
+// deslizante e a comparar a taxa de ERROR+ com cfg.Threshold, para alertas
+// simples de SLO sem sistemas externos. Chame Close no ErrorBudget retornado
+// para interromper os resumos periódicos.
+func (l *Logger) EnableErrorBudget(cfg ErrorBudgetConfig) *ErrorBudget {
+	if cfg.Window <= 0 {
+		cfg.Window = 5 * time.Minute
+	}
+	if cfg.Buckets <= 0 {
+		cfg.Buckets = 10
+	}
+	if cfg.MinEntries == 0 {
+		cfg.MinEntries = 1
+	}
+	b := &ErrorBudget{
+		logger:  l,
+		cfg:     cfg,
+		width:   cfg.Window / time.Duration(cfg.Buckets),
+		buckets: make([]budgetBucket, cfg.Buckets),
+		stop:    make(chan struct{}),
+	}
+	l.AddHook(b.observe, false)
+	if cfg.SummaryEvery > 0 {
+		go b.run()
+	}
+	return b
+}
+
+func (b *ErrorBudget) observe(entry *Entry) {
+	if isBudgetSummary(entry) {
+		return
+	}
+	now := time.Now()
+	b.mu.Lock()
+	start := now.Truncate(b.width)
+	bk := &b.buckets[int(start.UnixNano()/int64(b.width))%len(b.buckets)]
+	if !bk.start.Equal(start) {
+		bk.start, bk.counts = start, make(map[Level]uint64)
+	}
+	bk.counts[entry.Level]++
+	status := b.statusLocked(now)
+	crossed := status.Exceeded && !b.exceeded
+	b.exceeded = status.Exceeded
+	b.mu.Unlock()
+
+	if crossed && b.cfg.OnExceeded != nil {
+		b.cfg.OnExceeded(status)
+	}
+}
+
+// Status retorna o estado atual da janela.
+func (b *ErrorBudget) Status() ErrorBudgetStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.statusLocked(time.Now())
+}
+
+func (b *ErrorBudget) statusLocked(now time.Time) ErrorBudgetStatus {
+	s := ErrorBudgetStatus{Window: b.cfg.Window, ByLevel: make(map[Level]uint64)}
+	for _, bk := range b.buckets {
+		if bk.start.IsZero() || now.Sub(bk.start) >= b.cfg.Window {
+			continue
+		}
+		for lvl, n := range bk.counts {
+			s.ByLevel[lvl] += n
+			s.Total += n
+			if lvl >= ERROR {
+				s.Errors += n
+			}
+		}
+	}
+	if s.Total > 0 {
+		s.Rate = float64(s.Errors) / float64(s.Total)
+	}
+	s.Exceeded = s.Total >= b.cfg.MinEntries && s.Rate > b.cfg.Threshold
+	return s
+}
+
+func (b *ErrorBudget) run() {
+	ticker := time.NewTicker(b.cfg.SummaryEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			b.emitSummary()
+		}
+	}
+}
+
+// emitSummary registra a entry de resumo com o histograma da janela.
+func (b *ErrorBudget) emitSummary() {
+	s := b.Status()
+	histogram := make(map[string]interface{}, len(s.ByLevel))
+	for lvl, n := range s.ByLevel {
+		histogram[lvl.String()] = n
+	}
+	level := INFO
+	if s.Exceeded {
+		level = WARN
+	}
+	b.logger.logWithFields(level, "error budget summary", map[string]interface{}{
+		"error_budget": true,
+		"window":       s.Window.String(),
+		"total":        s.Total,
+		"errors":       s.Errors,
+		"error_rate":   s.Rate,
+		"threshold":    b.cfg.Threshold,
+		"exceeded":     s.Exceeded,
+		"by_level":     histogram,
+	})
+}
+
+// isBudgetSummary evita que as entries de resumo entrem na própria contagem.
+func isBudgetSummary(entry *Entry) bool {
+	v, _ := entry.Fields["error_budget"].(bool)
+	return v
+}
+
+// Close interrompe os resumos periódicos (a contagem continua).
+func (b *ErrorBudget) Close() error {
+	b.once.Do(func() { close(b.stop) })
+	return nil
+}
@@ -0,0 +1,91 @@
+package lazylog
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrorKey é a chave usada por WithError (padrão "error"). As chaves
+// auxiliares derivam dela: "<ErrorKey>_chain" e "<ErrorKey>_stack". Deve ser
+// alterada apenas na inicialização.
+var ErrorKey = "error"
+
+// WithError retorna um logger derivado com o erro anexado em ErrorKey. Se o
+// erro envolver outros (fmt.Errorf com %w, errors.Join), as mensagens das
+// causas vão em "<ErrorKey>_chain"; se algum erro da cadeia carregar uma pilha
+// (StackTrace(), como github.com/pkg/errors, ou Stack() []byte), ela vai em
+// "<ErrorKey>_stack". Um erro nil não adiciona campos.
+func (l *Logger) WithError(err error) *Logger {
+	if err == nil {
+		return l
+	}
+	if c := l.core(); c.nop {
+		return c
+	}
+	return l.WithFields(errorFields(err))
+}
+
+// WithError é o equivalente de Logger.WithError para o EntryBuilder.
+func (b *EntryBuilder) WithError(err error) *EntryBuilder {
+	if err == nil || b.disabled {
+		return b
+	}
+	nb := *b
+	nb.fields = mergeBoundFields(b.fields, errorFields(err))
+	return &nb
+}
+
+// errorFields monta os campos de um erro.
+func errorFields(err error) map[string]any {
+	fields := map[string]any{ErrorKey: err.Error()}
+	var chain []string
+	var stack []string
+	var walk func(e error, top bool)
+	walk = func(e error, top bool) {
+		if e == nil {
+			return
+		}
+		if !top {
+			chain = append(chain, e.Error())
+		}
+		if stack == nil {
+			stack = errorStack(e)
+		}
+		switch u := e.(type) {
+		case interface{ Unwrap() error }:
+			walk(u.Unwrap(), false)
+		case interface{ Unwrap() []error }:
+			for _, inner := range u.Unwrap() {
+				walk(inner, false)
+			}
+		}
+	}
+	walk(err, true)
+	if len(chain) > 0 {
+		fields[ErrorKey+"_chain"] = chain
+	}
+	if len(stack) > 0 {
+		fields[ErrorKey+"_stack"] = stack
+	}
+	return fields
+}
+
+// errorStack extrai a pilha carregada pelo erro, se houver: StackTrace() que
+// retorna uma slice (ex: pkg/errors.StackTrace, formatada com %+v) ou
+// Stack() []byte (ex: go-errors).
+func errorStack(err error) []string {
+	if s, ok := err.(interface{ Stack() []byte }); ok {
+		return strings.Split(strings.TrimSpace(string(s.Stack())), "\n")
+	}
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 || m.Type().Out(0).Kind() != reflect.Slice {
+		return nil
+	}
+	frames := m.Call(nil)[0]
+	out := make([]string, 0, frames.Len())
+	for i := 0; i < frames.Len(); i++ {
+		out = append(out, strings.ReplaceAll(fmt.Sprintf("%+v", frames.Index(i).Interface()), "\n\t", " "))
+	}
+	return out
+}
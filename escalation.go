@@ -0,0 +1,92 @@
+package lazylog
+
+import (
+	"sync"
+	"time"
+)
+
+// Padrões de EscalationConfig para Threshold <= 0 e Window <= 0.
+const (
+	DefaultEscalationThreshold = 10
+	DefaultEscalationWindow    = 5 * time.Minute
+)
+
+// EscalationConfig configura a escalada de erros repetidos.
+type EscalationConfig struct {
+	Threshold int           // ocorrências necessárias para escalar (padrão: DefaultEscalationThreshold)
+	Window    time.Duration // janela em que as ocorrências são contadas (padrão: DefaultEscalationWindow)
+	Level     Level         // nível da entry de alerta (padrão: ERROR)
+	Message   string        // mensagem da entry de alerta (padrão: "repeated error escalated")
+}
+
+type escalationState struct {
+	first time.Time
+	count int
+}
+
+type escalator struct {
+	logger *Logger
+	cfg    EscalationConfig
+	mu     sync.Mutex
+	seen   map[string]*escalationState
+}
+
+// EnableEscalation passa a acompanhar entries ERROR repetidas (agrupadas pelo
+// template da mensagem) e emite uma única entry de alerta quando um mesmo erro
+// ocorre Threshold vezes dentro de Window. A entry de alerta traz os campos
+// escalated, occurrences, window e original_message.
+func (l *Logger) EnableEscalation(cfg EscalationConfig) {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = DefaultEscalationThreshold
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultEscalationWindow
+	}
+	if cfg.Level < ERROR {
+		cfg.Level = ERROR
+	}
+	if cfg.Message == "" {
+		cfg.Message = "repeated error escalated"
+	}
+	e := &escalator{logger: l, cfg: cfg, seen: make(map[string]*escalationState)}
+	l.AddHook(e.observe, false)
+}
+
+func (e *escalator) observe(entry *Entry) {
+	if entry.Level < ERROR {
+		return
+	}
+	if escalated, _ := entry.Fields["escalated"].(bool); escalated {
+		return
+	}
+	key := MessageTemplate(entry.Message)
+	now := entry.Timestamp
+
+	e.mu.Lock()
+	st, ok := e.seen[key]
+	if !ok || now.Sub(st.first) > e.cfg.Window {
+		st = &escalationState{first: now}
+		e.seen[key] = st
+	}
+	st.count++
+	fire := st.count >= e.cfg.Threshold
+	if fire {
+		delete(e.seen, key)
+	}
+	// Remove estados expirados para não crescer indefinidamente
+	for k, s := range e.seen {
+		if now.Sub(s.first) > e.cfg.Window {
+			delete(e.seen, k)
+		}
+	}
+	e.mu.Unlock()
+
+	if fire {
+		e.logger.logWithFields(e.cfg.Level, e.cfg.Message, map[string]interface{}{
+			"escalated":        true,
+			"occurrences":      e.cfg.Threshold,
+			"window":           e.cfg.Window.String(),
+			"original_message": entry.Message,
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package lazylog
+
+// EventField é o campo que carrega o nome do evento.
+const EventField = "event"
+
+// EventBuilder constrói uma entry identificada por um nome de evento
+// (ex: "user.signup") em vez de uma mensagem livre. Os métodos são seguros
+// para receiver nil (retornado por loggers Nop).
+type EventBuilder struct {
+	logger  *Logger
+	name    string
+	level   Level
+	message string
+	fields  []Field
+}
+
+// Event inicia um evento nomeado. O nome vira o campo "event" (primeiro na
+// saída) e pode ser usado por filtros (EventFilter) e por EnableSchemaValidation.
+// O nível padrão é INFO e a mensagem padrão é o próprio nome.
+func (l *Logger) Event(name string) *EventBuilder {
+	if l.core().nop {
+		return nil
+	}
+	return &EventBuilder{logger: l, name: name, level: INFO}
+}
+
+// With adiciona campos ao evento, preservando a ordem.
+func (e *EventBuilder) With(fields ...Field) *EventBuilder {
+	if e == nil {
+		return nil
+	}
+	e.fields = append(e.fields, fields...)
+	return e
+}
+
+// AtLevel define o nível do evento.
+func (e *EventBuilder) AtLevel(level Level) *EventBuilder {
+	if e == nil {
+		return nil
+	}
+	e.level = level
+	return e
+}
+
+// Message define uma mensagem legível para o evento.
+func (e *EventBuilder) Message(msg string) *EventBuilder {
+	if e == nil {
+		return nil
+	}
+	e.message = msg
+	return e
+}
+
+// Emit registra o evento.
+func (e *EventBuilder) Emit() {
+	if e == nil || !e.logger.enabledFor(e.level) {
+		return
+	}
+	msg := e.message
+	if msg == "" {
+		msg = e.name
+	}
+	fields := make([]Field, 0, len(e.fields)+1)
+	fields = append(fields, Field{Key: EventField, Value: e.name})
+	fields = append(fields, e.fields...)
+	e.logger.logWithFieldSlice(e.level, msg, fields)
+}
+
+// EventName retorna o nome do evento da entry ("" se não for um evento).
+func EventName(entry *Entry) string {
+	name, _ := entry.Fields[EventField].(string)
+	return name
+}
+
+// EventFilter retorna um FilterFunc que aceita apenas os eventos informados,
+// para rotear eventos a transportes específicos via TransportWithFilter.
+func EventFilter(names ...string) FilterFunc {
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[n] = true
+	}
+	return func(entry *Entry) bool {
+		return set[EventName(entry)]
+	}
+}
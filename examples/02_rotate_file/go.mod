@@ -7,4 +7,6 @@ require (
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1
 )
 
+require gopkg.in/yaml.v3 v3.0.1 // indirect
+
 replace github.com/chmenegatti/lazylog => ../../
@@ -28,5 +28,5 @@ func main() {
 	// Exemplo: uso de child logger
 	child := logger.WithFields(map[string]any{"service": "auth", "env": os.Getenv("ENV")})
 	child.Info("Log do serviço de autenticação")
-	child.Error("Erro no serviço de autenticação", map[string]any{"code": 401})
+	child.ComFields(map[string]any{"code": 401}).Error("Erro no serviço de autenticação")
 }
@@ -0,0 +1,76 @@
+package lazylog
+
+import (
+	"context"
+	"math/rand/v2"
+)
+
+// RedactedValue substitui o valor de campos redigidos.
+const RedactedValue = "[REDACTED]"
+
+// FlagProvider avalia feature flags. Para OpenFeature, basta um adaptador que
+// chame client.BooleanValue/FloatValue com attrs como EvaluationContext.
+type FlagProvider interface {
+	Bool(ctx context.Context, flag string, def bool, attrs map[string]any) bool
+	Float(ctx context.Context, flag string, def float64, attrs map[string]any) float64
+}
+
+// FeatureFlagTransport é um decorator cujos controles (debug, amostragem e
+// redação) são decididos por feature flags a cada entry, permitindo ajustar
+// por ambiente/tenant sem redeploy. As flags recebem os atributos
+// "environment", "tenant", "level" e "event". Flags com nome vazio são ignoradas.
+//
+// O transporte envolvido deve aceitar DEBUG para que DebugFlag tenha efeito.
+type FeatureFlagTransport struct {
+	Transport   Transport
+	Flags       FlagProvider
+	Environment string
+	TenantField string // campo da entry com o tenant (ex: "tenant_id")
+
+	DebugFlag      string   // bool: libera entries DEBUG (padrão false)
+	SampleRateFlag string   // float 0..1: fração mantida das entries abaixo de WARN (padrão 1)
+	RedactFlag     string   // bool: redige RedactFields (padrão false)
+	RedactFields   []string // campos substituídos por RedactedValue
+}
+
+func (t *FeatureFlagTransport) WriteLog(entry *Entry) error {
+	if t.Flags == nil {
+		return t.Transport.WriteLog(entry)
+	}
+	ctx := entry.Context()
+	attrs := map[string]any{
+		"environment": t.Environment,
+		"level":       entry.Level.String(),
+		"event":       EventName(entry),
+	}
+	if t.TenantField != "" {
+		attrs["tenant"] = entry.Fields[t.TenantField]
+	}
+	if entry.Level <= DEBUG && t.DebugFlag != "" && !t.Flags.Bool(ctx, t.DebugFlag, false, attrs) {
+		return nil
+	}
+	if entry.Level < WARN && t.SampleRateFlag != "" {
+		if rate := t.Flags.Float(ctx, t.SampleRateFlag, 1, attrs); rand.Float64() >= rate {
+			return nil
+		}
+	}
+	if t.RedactFlag != "" && len(t.RedactFields) > 0 && t.Flags.Bool(ctx, t.RedactFlag, false, attrs) {
+		redacted := entry.Clone()
+		for _, k := range t.RedactFields {
+			if _, ok := redacted.Fields[k]; ok {
+				redacted.Fields[k] = RedactedValue
+			}
+		}
+		entry = redacted
+	}
+	return t.Transport.WriteLog(entry)
+}
+
+func (t *FeatureFlagTransport) MinLevel() Level {
+	return t.Transport.MinLevel()
+}
+
+// Close fecha o transporte envolvido, se ele implementar io.Closer.
+func (t *FeatureFlagTransport) Close() error {
+	return closeTransport(t.Transport)
+}
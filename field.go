@@ -0,0 +1,213 @@
+package lazylog
+
+import (
+	"runtime/debug"
+	"time"
+)
+
+// Field é um par chave/valor usado pelas variantes *Fields dos métodos de log.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// String cria um campo string.
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int cria um campo int.
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int64 cria um campo int64.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Float64 cria um campo float64.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool cria um campo bool.
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Duration cria um campo time.Duration (formatado pelos FieldEncoders
+// registrados, ex: DurationAsMillis).
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Time cria um campo time.Time.
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Any cria um campo com um valor qualquer.
+func Any(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err cria o campo ErrorKey com a mensagem do erro. Um erro nil gera um
+// campo vazio, ignorado pelas variantes *Fields:
+//
+//	logger.ErrorFields("falha ao salvar", lazylog.Err(err), lazylog.String("user", "cesar"))
+func Err(err error) Field {
+	if err == nil {
+		return Field{}
+	}
+	return Field{Key: ErrorKey, Value: err.Error()}
+}
+
+// LazyValue é um valor de campo calculado apenas no momento do despacho da
+// entry (útil com defer, cujos argumentos são avaliados antecipadamente).
+type LazyValue func() any
+
+// fieldsToMap converte campos em mapa.
+func fieldsToMap(fields []Field) map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+	m := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		if f.Key != "" {
+			m[f.Key] = f.Value
+		}
+	}
+	return m
+}
+
+// fieldKeys retorna as chaves dos campos na ordem informada, sem os campos
+// vazios (ex: Err(nil)).
+func fieldKeys(fields []Field) []string {
+	keys := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f.Key != "" {
+			keys = append(keys, f.Key)
+		}
+	}
+	return keys
+}
+
+// maxTypedFields limita o caminho rápido das variantes *Fields: acima disso a
+// verificação de chaves duplicadas deixa de compensar e o mapa é montado.
+const maxTypedFields = 16
+
+// typedEntry aloca a entry junto com o espaço dos campos tipados, para que o
+// caminho rápido custe uma única alocação.
+type typedEntry struct {
+	Entry
+	buf [8]Field
+}
+
+// typedFieldsOK indica se os campos podem seguir sem o mapa: sem chaves
+// repetidas (no mapa, o último valor vence) e sem LazyValue.
+func typedFieldsOK(fields []Field) bool {
+	if len(fields) > maxTypedFields {
+		return false
+	}
+	for i, f := range fields {
+		if _, lazy := f.Value.(LazyValue); lazy {
+			return false
+		}
+		if f.Key == "" {
+			continue
+		}
+		for _, prev := range fields[:i] {
+			if prev.Key == f.Key {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// logWithFieldSlice registra uma entry preservando a ordem dos campos tipados.
+// No caso comum os campos seguem como []Field até o formatter (ver
+// Entry.typed); o mapa só é montado quando algum estágio precisa dele.
+func (l *Logger) logWithFieldSlice(level Level, message string, fields []Field) error {
+	if !l.enabledFor(level) {
+		return nil
+	}
+	snap := l.snapshot()
+	stack := snap.stacktrace.Enabled && snap.stacktrace.Levels[level]
+	if !stack && typedFieldsOK(fields) {
+		te := &typedEntry{Entry: Entry{Level: level, Timestamp: time.Now(), Message: message}}
+		typed := te.buf[:0]
+		if len(fields) > len(te.buf) {
+			typed = make([]Field, 0, len(fields))
+		}
+		for _, f := range fields {
+			if f.Key != "" {
+				typed = append(typed, f)
+			}
+		}
+		if len(typed) > 0 {
+			te.typed = typed
+		}
+		return dispatchEntry(snap, &te.Entry, nil)
+	}
+	entry := Entry{
+		Level:      level,
+		Timestamp:  time.Now(),
+		Message:    message,
+		Fields:     fieldsToMap(fields),
+		FieldOrder: fieldKeys(fields),
+	}
+	if stack {
+		entry.Fields = withField(entry.Fields, "stacktrace", string(debug.Stack()))
+	}
+	return dispatchEntry(snap, &entry, nil)
+}
+
+// resolveLazyFields avalia os LazyValue presentes nos campos, sem alterar o
+// mapa original.
+func resolveLazyFields(fields map[string]interface{}) map[string]interface{} {
+	var out map[string]interface{}
+	for k, v := range fields {
+		lv, ok := v.(LazyValue)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]interface{}, len(fields))
+			for k2, v2 := range fields {
+				out[k2] = v2
+			}
+		}
+		out[k] = lv()
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// TraceFields registra uma mensagem TRACE com os campos informados.
+func (l *Logger) TraceFields(msg string, fields ...Field) {
+	l.logWithFieldSlice(TRACE, msg, fields)
+}
+
+// DebugFields registra uma mensagem DEBUG com os campos informados.
+func (l *Logger) DebugFields(msg string, fields ...Field) {
+	l.logWithFieldSlice(DEBUG, msg, fields)
+}
+
+// InfoFields registra uma mensagem INFO com os campos informados.
+func (l *Logger) InfoFields(msg string, fields ...Field) {
+	l.logWithFieldSlice(INFO, msg, fields)
+}
+
+// WarnFields registra uma mensagem WARN com os campos informados.
+func (l *Logger) WarnFields(msg string, fields ...Field) {
+	l.logWithFieldSlice(WARN, msg, fields)
+}
+
+// ErrorFields registra uma mensagem ERROR com os campos informados.
+func (l *Logger) ErrorFields(msg string, fields ...Field) {
+	l.logWithFieldSlice(ERROR, msg, fields)
+}
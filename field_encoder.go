@@ -0,0 +1,155 @@
+package lazylog
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// FieldEncoder converte o valor de um campo antes da formatação.
+type FieldEncoder func(v any) any
+
+type fieldEncoderEntry struct {
+	typ reflect.Type
+	enc FieldEncoder
+}
+
+var (
+	encoderMu sync.RWMutex
+	// encodersByType guarda encoders para tipos concretos (match exato).
+	encodersByType = map[reflect.Type]FieldEncoder{}
+	// encodersByIface guarda encoders para interfaces, na ordem de registro.
+	encodersByIface []fieldEncoderEntry
+)
+
+// RegisterFieldEncoder registra um encoder global para valores do tipo T.
+// Se T for uma interface (ex: fmt.Stringer, error), o encoder é aplicado a
+// qualquer valor que a implemente; tipos concretos têm precedência.
+// Os encoders são aplicados a todos os formatters, antes da formatação.
+func RegisterFieldEncoder[T any](enc func(T) any) {
+	typ := reflect.TypeFor[T]()
+	fn := func(v any) any { return enc(v.(T)) }
+	encoderMu.Lock()
+	defer encoderMu.Unlock()
+	if typ.Kind() == reflect.Interface {
+		for i, e := range encodersByIface {
+			if e.typ == typ {
+				encodersByIface[i].enc = fn
+				return
+			}
+		}
+		encodersByIface = append(encodersByIface, fieldEncoderEntry{typ: typ, enc: fn})
+		return
+	}
+	encodersByType[typ] = fn
+}
+
+// ResetFieldEncoders remove todos os encoders registrados.
+func ResetFieldEncoders() {
+	encoderMu.Lock()
+	defer encoderMu.Unlock()
+	encodersByType = map[reflect.Type]FieldEncoder{}
+	encodersByIface = nil
+}
+
+// DurationAsMillis representa time.Duration em milissegundos (float64).
+func DurationAsMillis(d time.Duration) any {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// BytesAsBase64 representa []byte como string base64.
+func BytesAsBase64(b []byte) any {
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// BytesAsHex representa []byte como string hexadecimal.
+func BytesAsHex(b []byte) any {
+	return hex.EncodeToString(b)
+}
+
+// StringerAsString chama String() apenas no momento da formatação.
+func StringerAsString(s fmt.Stringer) any {
+	return s.String()
+}
+
+// ErrorAsString representa um error pela sua mensagem.
+func ErrorAsString(err error) any {
+	return err.Error()
+}
+
+// lookupFieldEncoder retorna o encoder aplicável ao valor, se houver.
+// Deve ser chamado com encoderMu em modo leitura.
+func lookupFieldEncoder(v any) (FieldEncoder, bool) {
+	if v == nil {
+		return nil, false
+	}
+	typ := reflect.TypeOf(v)
+	if enc, ok := encodersByType[typ]; ok {
+		return enc, true
+	}
+	for _, e := range encodersByIface {
+		if typ.Implements(e.typ) {
+			return e.enc, true
+		}
+	}
+	return nil, false
+}
+
+// hasFieldEncoders indica se há encoders registrados (os campos tipados então
+// precisam passar pelo mapa, onde os encoders são aplicados).
+func hasFieldEncoders() bool {
+	encoderMu.RLock()
+	defer encoderMu.RUnlock()
+	return len(encodersByType) > 0 || len(encodersByIface) > 0
+}
+
+// encodeFields aplica os encoders registrados aos campos, incluindo mapas aninhados.
+// O mapa original nunca é alterado: uma cópia é feita apenas se algum valor mudar.
+func encodeFields(fields map[string]interface{}) map[string]interface{} {
+	if len(fields) == 0 {
+		return fields
+	}
+	encoderMu.RLock()
+	defer encoderMu.RUnlock()
+	if len(encodersByType) == 0 && len(encodersByIface) == 0 {
+		return fields
+	}
+	return encodeFieldsLocked(fields)
+}
+
+func encodeFieldsLocked(fields map[string]interface{}) map[string]interface{} {
+	var out map[string]interface{}
+	for k, v := range fields {
+		var nv interface{}
+		if enc, ok := lookupFieldEncoder(v); ok {
+			nv = enc(v)
+		} else if m, ok := v.(map[string]interface{}); ok {
+			nm := encodeFieldsLocked(m)
+			if sameMap(nm, m) {
+				continue
+			}
+			nv = nm
+		} else {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]interface{}, len(fields))
+			for k2, v2 := range fields {
+				out[k2] = v2
+			}
+		}
+		out[k] = nv
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// sameMap indica se dois mapas são a mesma instância.
+func sameMap(a, b map[string]interface{}) bool {
+	return reflect.ValueOf(a).UnsafePointer() == reflect.ValueOf(b).UnsafePointer()
+}
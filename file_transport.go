@@ -10,6 +10,8 @@ type FileTransport struct {
 	File      *os.File
 	Level     Level
 	Formatter Formatter
+
+	stats transportStats
 }
 
 func NewFileTransport(path string, level Level, formatter Formatter) (*FileTransport, error) {
@@ -25,23 +27,37 @@ func NewFileTransport(path string, level Level, formatter Formatter) (*FileTrans
 }
 
 func (f *FileTransport) WriteLog(entry *Entry) error {
+	_, err := f.writeLogN(entry)
+	return err
+}
+
+// writeLogN escreve a entry e retorna a quantidade de bytes gravados.
+func (f *FileTransport) writeLogN(entry *Entry) (n int, err error) {
+	defer func() { f.stats.record(f, n, err) }()
 	formatter := f.Formatter
 	if formatter == nil {
 		formatter = &TextFormatter{}
 	}
 	bytes, err := formatter.Format(entry)
 	if err != nil {
-		_, err2 := io.WriteString(f.File, entry.Timestamp.Format("2006-01-02T15:04:05Z07:00")+" ["+entry.Level.String()+"] "+entry.Message+"\n")
-		return err2
+		return io.WriteString(f.File, entry.Timestamp.Format("2006-01-02T15:04:05Z07:00")+" ["+entry.Level.String()+"] "+entry.Message+"\n")
 	}
-	_, err = f.File.Write(bytes)
-	return err
+	return f.File.Write(bytes)
+}
+
+func (f *FileTransport) writesTypedFields() bool {
+	return typedFormatter(f.Formatter)
 }
 
 func (f *FileTransport) MinLevel() Level {
 	return f.Level
 }
 
+// Flush grava em disco (fsync) os dados já escritos no arquivo.
+func (f *FileTransport) Flush() error {
+	return f.File.Sync()
+}
+
 func (f *FileTransport) Close() error {
 	return f.File.Close()
 }
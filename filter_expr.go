@@ -0,0 +1,141 @@
+package lazylog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ParseFilterExpr compila uma expressão de filtro declarativa (usada em
+// TransportConfig.Filter) num FilterFunc. A gramática é propositalmente
+// simples: condições unidas por "&&" e "||" ("&&" tem precedência), onde cada
+// condição é uma das formas:
+//
+//	chave == valor    chave != valor    chave =~ regexp    chave    !chave
+//
+// A chave pode ser "message", "level" ou o nome de um campo (também aceito
+// como "fields.nome"); a forma sem operador testa a existência do campo.
+// Valores podem vir entre aspas simples ou duplas e são comparados com o
+// valor formatado via fmt.Sprint. Exemplo:
+//
+//	level == ERROR && service =~ "^billing" || event == "user.signup"
+func ParseFilterExpr(expr string) (FilterFunc, error) {
+	var alternatives [][]filterCond
+	for _, alt := range strings.Split(expr, "||") {
+		var conds []filterCond
+		for _, raw := range strings.Split(alt, "&&") {
+			c, err := parseFilterCond(strings.TrimSpace(raw))
+			if err != nil {
+				return nil, fmt.Errorf("lazylog: invalid filter %q: %w", expr, err)
+			}
+			conds = append(conds, c)
+		}
+		alternatives = append(alternatives, conds)
+	}
+	return func(entry *Entry) bool {
+		for _, conds := range alternatives {
+			matched := true
+			for _, c := range conds {
+				if !c.match(entry) {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+type filterCond struct {
+	key    string
+	op     string // "==", "!=", "=~", "exists", "missing"
+	value  string
+	regexp *regexp.Regexp
+}
+
+func parseFilterCond(s string) (filterCond, error) {
+	if s == "" {
+		return filterCond{}, fmt.Errorf("empty condition")
+	}
+	for _, op := range []string{"==", "!=", "=~"} {
+		key, value, ok := strings.Cut(s, op)
+		if !ok {
+			continue
+		}
+		c := filterCond{key: filterKey(key), op: op, value: unquoteFilterValue(strings.TrimSpace(value))}
+		if c.key == "" {
+			return c, fmt.Errorf("missing key in %q", s)
+		}
+		if op == "=~" {
+			re, err := regexp.Compile(c.value)
+			if err != nil {
+				return c, err
+			}
+			c.regexp = re
+		}
+		return c, nil
+	}
+	if key, ok := strings.CutPrefix(s, "!"); ok {
+		return filterCond{key: filterKey(key), op: "missing"}, nil
+	}
+	if strings.ContainsAny(s, " \t=<>") {
+		return filterCond{}, fmt.Errorf("unsupported condition %q", s)
+	}
+	return filterCond{key: filterKey(s), op: "exists"}, nil
+}
+
+func filterKey(s string) string {
+	s = strings.TrimSpace(s)
+	if k, ok := strings.CutPrefix(s, "fields."); ok {
+		return "\x00" + k // campo explícito, mesmo que se chame message/level
+	}
+	return s
+}
+
+func unquoteFilterValue(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// lookup retorna o valor da chave na entry como string.
+func (c filterCond) lookup(entry *Entry) (string, bool) {
+	switch c.key {
+	case "message":
+		return entry.Message, true
+	case "level":
+		return entry.Level.String(), true
+	}
+	v, ok := entry.Fields[strings.TrimPrefix(c.key, "\x00")]
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprint(v), true
+}
+
+func (c filterCond) match(entry *Entry) bool {
+	v, ok := c.lookup(entry)
+	switch c.op {
+	case "exists":
+		return ok
+	case "missing":
+		return !ok
+	case "==":
+		if c.key == "level" {
+			return ok && strings.EqualFold(v, c.value)
+		}
+		return ok && v == c.value
+	case "!=":
+		if c.key == "level" {
+			return !strings.EqualFold(v, c.value)
+		}
+		return !ok || v != c.value
+	case "=~":
+		return ok && c.regexp.MatchString(v)
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package lazylog
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// lazylogPkgPrefix identifica frames internos do pacote, ignorados na busca do caller.
+const lazylogPkgPrefix = "github.com/chmenegatti/lazylog."
+
+// debugonlyPkgPrefix identifica os frames da fachada debugonly, que apenas
+// repassa as chamadas e também é ignorada na busca do caller.
+const debugonlyPkgPrefix = "github.com/chmenegatti/lazylog/debugonly."
+
+// isInternalFrame indica se a função pertence ao lazylog (ou ao debugonly).
+func isInternalFrame(function string) bool {
+	return strings.HasPrefix(function, lazylogPkgPrefix) || strings.HasPrefix(function, debugonlyPkgPrefix)
+}
+
+var (
+	templateQuoted = regexp.MustCompile(`"[^"]*"|'[^']*'`)
+	templateUUID   = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	templateHex    = regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b`)
+	templateNumber = regexp.MustCompile(`\d+(\.\d+)?`)
+)
+
+// MessageTemplate normaliza uma mensagem substituindo partes variáveis
+// (strings entre aspas, UUIDs, hexadecimais e números) por placeholders,
+// de forma que "user 42 not found" e "user 7 not found" gerem o mesmo template.
+func MessageTemplate(message string) string {
+	t := templateQuoted.ReplaceAllString(message, "<str>")
+	t = templateUUID.ReplaceAllString(t, "<uuid>")
+	t = templateHex.ReplaceAllString(t, "<hex>")
+	return templateNumber.ReplaceAllString(t, "<n>")
+}
+
+// Fingerprint calcula um identificador estável a partir do template da mensagem
+// e do frame de origem (ex: "pkg.Func:42").
+func Fingerprint(message, frame string) string {
+	sum := sha1.Sum([]byte(MessageTemplate(message) + "|" + frame))
+	return hex.EncodeToString(sum[:8])
+}
+
+// FingerprintHook retorna um hook (use com before=true) que adiciona o campo
+// "fingerprint" às entries de nível ERROR ou superior, permitindo agrupar
+// erros recorrentes sem depender de ferramentas externas.
+func FingerprintHook() Hook {
+	return func(e *Entry) {
+		if e.Level < ERROR {
+			return
+		}
+		e.Fields = withField(e.Fields, "fingerprint", Fingerprint(e.Message, callerFrame()))
+	}
+}
+
+// callerFrame retorna o primeiro frame fora do pacote lazylog, no formato "func:line".
+func callerFrame() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !isInternalFrame(f.Function) {
+			return f.Function + ":" + strconv.Itoa(f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
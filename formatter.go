@@ -2,8 +2,12 @@ package lazylog
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"strconv"
 	"time"
 )
 
@@ -13,14 +17,55 @@ type Formatter interface {
 	Format(entry *Entry) ([]byte, error)
 }
 
+// AppendFormatter é implementado por formatters que sabem serializar a entry
+// no fim de um buffer existente, sem alocar um []byte por entry (usado por
+// BatchTransport). JSONFormatter o implementa.
+type AppendFormatter interface {
+	AppendFormat(dst []byte, entry *Entry) ([]byte, error)
+}
+
 // --- Implementação do TextFormatter ---
 
 // TextFormatter formata logs como texto simples.
 type TextFormatter struct {
 	// TimestampFormat especifica o formato do timestamp. Usa time.RFC3339 se vazio.
 	TimestampFormat string
+	// Labels, se definido, traduz os nomes dos níveis (ex: LocaleLabels("pt-BR")).
+	Labels LevelLabels
+	// Composite, Struct e Bytes controlam como valores não escalares são
+	// impressos. Os padrões (valor zero) geram saída interpretável: JSON para
+	// maps/slices, %+v para structs e hexadecimal para []byte.
+	Composite TextCompositeStyle
+	Struct    TextStructStyle
+	Bytes     TextBytesStyle
 }
 
+// TextCompositeStyle define a impressão de maps, slices e arrays no TextFormatter.
+type TextCompositeStyle int
+
+const (
+	TextCompositeJSON TextCompositeStyle = iota // {"a":1} / [1,2]
+	TextCompositeGo                             // map[a:1] / [1 2] (%v)
+)
+
+// TextStructStyle define a impressão de structs no TextFormatter.
+type TextStructStyle int
+
+const (
+	TextStructFields TextStructStyle = iota // {Name:x Age:3} (%+v)
+	TextStructJSON                          // {"Name":"x","Age":3}
+	TextStructGo                            // {x 3} (%v)
+)
+
+// TextBytesStyle define a impressão de []byte no TextFormatter.
+type TextBytesStyle int
+
+const (
+	TextBytesHex    TextBytesStyle = iota // 68690a
+	TextBytesBase64                       // aGkK
+	TextBytesGo                           // [104 105 10] (%v)
+)
+
 // Format implementa a interface Formatter para TextFormatter.
 func (f *TextFormatter) Format(entry *Entry) ([]byte, error) {
 	timestampFormat := f.TimestampFormat
@@ -29,26 +74,56 @@ func (f *TextFormatter) Format(entry *Entry) ([]byte, error) {
 	}
 
 	var b bytes.Buffer
+	b.Grow(64 + len(entry.Message) + 16*(len(entry.Fields)+len(entry.typed)))
 
 	// Escreve o timestamp formatado
-	b.WriteString(entry.Timestamp.Format(timestampFormat))
+	b.Write(entry.Timestamp.AppendFormat(b.AvailableBuffer(), timestampFormat))
 
 	// Adiciona um espaço
 	b.WriteString(" ")
 
-	// Escreve o nível
-	b.WriteString(fmt.Sprintf("[%s]", entry.Level.String()))
+	// Escreve o nível (tag pré-computada, ou rótulo localizado)
+	if f.Labels != nil {
+		b.WriteByte('[')
+		b.WriteString(f.Labels.label(entry.Level))
+		b.WriteByte(']')
+	} else {
+		b.WriteString(entry.Level.textTag())
+	}
 
 	// Adiciona outro espaço
 	b.WriteString(" ")
 
 	// Escreve a mensagem
 	b.WriteString(entry.Message)
-	if len(entry.Fields) > 0 {
+	hasFields := entry.hasFields()
+	if hasFields {
 		b.WriteString(" ")
-		for k, v := range entry.Fields {
-			b.WriteString(fmt.Sprintf("%s=%v ", k, v))
+		if entry.typed != nil {
+			for _, fd := range entry.typed {
+				f.writeField(&b, fd.Key, fd.Value)
+			}
+		} else if entry.FieldOrder != nil {
+			for _, k := range entry.orderedKeys() {
+				f.writeField(&b, k, entry.Fields[k])
+			}
+		} else {
+			for k, v := range entry.Fields {
+				f.writeField(&b, k, v)
+			}
+		}
+	}
+	if entry.Caller != nil {
+		if !hasFields {
+			b.WriteString(" ")
 		}
+		f.writeField(&b, "caller", entry.Caller.String())
+	}
+	if entry.Function != "" {
+		if !hasFields && entry.Caller == nil {
+			b.WriteString(" ")
+		}
+		f.writeField(&b, "function", entry.Function)
 	}
 	// Adiciona uma nova linha no final
 	b.WriteString("\n")
@@ -56,27 +131,274 @@ func (f *TextFormatter) Format(entry *Entry) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// writeField escreve "k=v " no buffer, aplicando as regras de coerção.
+func (f *TextFormatter) writeField(b *bytes.Buffer, k string, v interface{}) {
+	b.WriteString(k)
+	b.WriteByte('=')
+	f.writeValue(b, v)
+	b.WriteByte(' ')
+}
+
+func (f *TextFormatter) writeValue(b *bytes.Buffer, v interface{}) {
+	switch val := v.(type) {
+	case string:
+		b.WriteString(val)
+		return
+	case nil, bool, int, int64, float64:
+		fmt.Fprint(b, v)
+		return
+	case []byte:
+		switch f.Bytes {
+		case TextBytesHex:
+			b.WriteString(hex.EncodeToString(val))
+		case TextBytesBase64:
+			b.WriteString(base64.StdEncoding.EncodeToString(val))
+		default:
+			fmt.Fprint(b, val)
+		}
+		return
+	case error:
+		b.WriteString(val.Error())
+		return
+	case fmt.Stringer:
+		b.WriteString(val.String())
+		return
+	}
+	kind := reflect.TypeOf(v).Kind()
+	if kind == reflect.Pointer {
+		kind = reflect.TypeOf(v).Elem().Kind()
+	}
+	switch kind {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		if f.Composite == TextCompositeJSON && writeJSONValue(b, v) {
+			return
+		}
+	case reflect.Struct:
+		switch f.Struct {
+		case TextStructFields:
+			fmt.Fprintf(b, "%+v", v)
+			return
+		case TextStructJSON:
+			if writeJSONValue(b, v) {
+				return
+			}
+		}
+	}
+	fmt.Fprint(b, v)
+}
+
+// writeJSONValue escreve v como JSON, indicando se conseguiu.
+func writeJSONValue(b *bytes.Buffer, v interface{}) bool {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+	b.Write(data)
+	return true
+}
+
 // --- Implementação do JSONFormatter ---
 
 // JSONFormatter formata logs como JSON.
 type JSONFormatter struct{}
 
 // Format implementa a interface Formatter para JSONFormatter.
+// As chaves fixas (timestamp, level, message) vêm primeiro, seguidas dos
+// campos em ordem alfabética.
 func (f *JSONFormatter) Format(entry *Entry) ([]byte, error) {
-	// Para serializar o nível como string, criamos um tipo anônimo.
-	data := map[string]interface{}{
-		"timestamp": entry.Timestamp.Format(time.RFC3339Nano), // JSON geralmente usa alta precisão
-		"level":     entry.Level.String(),
-		"message":   entry.Message,
-	}
-	mergeFields(data, entry.Fields)
-	b, err := json.Marshal(data)
+	return f.AppendFormat(nil, entry)
+}
+
+// AppendFormat implementa AppendFormatter: escreve a mesma saída de Format no
+// fim de dst.
+func (f *JSONFormatter) AppendFormat(dst []byte, entry *Entry) ([]byte, error) {
+	var fieldsJSON []byte
+	ordered := len(entry.typed) > 0 || len(entry.Fields) > 0 && entry.FieldOrder != nil
+	if len(entry.Fields) > 0 && !ordered {
+		data := make(map[string]interface{}, len(entry.Fields))
+		mergeFields(data, entry.Fields)
+		// Campos do usuário não podem sobrescrever as chaves reservadas
+		// (ex: um campo "level" vindo de input externo forjando o nível).
+		for _, k := range jsonReservedKeys {
+			if v, ok := data[k]; ok {
+				delete(data, k)
+				data["fields."+k] = v
+			}
+		}
+		for _, k := range []string{"caller", "function"} {
+			if v, ok := data[k]; ok && entry.shadowsMeta(k) {
+				delete(data, k)
+				data["fields."+k] = v
+			}
+		}
+		var err error
+		if fieldsJSON, err = json.Marshal(data); err != nil {
+			// Degrada apenas os campos problemáticos (NaN, Inf, ciclos, canais...)
+			// em vez de perder a entry estruturada inteira.
+			encodeErrs := make(map[string]string)
+			data = jsonSafeFields(data, "", encodeErrs, map[uintptr]bool{})
+			data["_encode_error"] = encodeErrs
+			if fieldsJSON, err = json.Marshal(data); err != nil {
+				return dst, err
+			}
+		}
+	}
+	b := bytes.NewBuffer(dst)
+	b.Grow(64 + len(entry.Message) + len(fieldsJSON))
+	b.WriteString(`{"timestamp":"`)
+	// JSON geralmente usa alta precisão
+	b.Write(entry.Timestamp.AppendFormat(b.AvailableBuffer(), time.RFC3339Nano))
+	b.WriteString(`",`)
+	b.WriteString(entry.Level.jsonPair()) // `"level":"INFO",` pré-computado
+	b.WriteString(`"message":`)
+	if !appendJSONScalar(b, entry.Message) {
+		message, _ := json.Marshal(entry.Message)
+		b.Write(message)
+	}
+	if entry.Caller != nil {
+		b.WriteString(`,"caller":`)
+		b.Write(strconv.AppendQuote(b.AvailableBuffer(), entry.Caller.String()))
+	}
+	if entry.Function != "" {
+		b.WriteString(`,"function":`)
+		b.Write(strconv.AppendQuote(b.AvailableBuffer(), entry.Function))
+	}
+	switch {
+	case ordered:
+		writeOrderedFieldsJSON(b, entry)
+		b.WriteByte('}')
+	case len(fieldsJSON) > 2: // mais que "{}"
+		b.WriteByte(',')
+		b.Write(fieldsJSON[1:])
+	default:
+		b.WriteByte('}')
+	}
+	// Adiciona uma nova linha para que cada log JSON fique em sua própria linha
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
+
+// writeOrderedFieldsJSON escreve os campos como pares ,"chave":valor
+// respeitando a ordem dos campos tipados ou de Entry.FieldOrder. Valores não
+// serializáveis são degradados individualmente.
+func writeOrderedFieldsJSON(b *bytes.Buffer, entry *Entry) {
+	var encodeErrs map[string]string
+	if entry.typed != nil {
+		for _, f := range entry.typed {
+			writeFieldJSON(b, entry, f.Key, f.Value, &encodeErrs)
+		}
+	} else {
+		for _, k := range entry.orderedKeys() {
+			writeFieldJSON(b, entry, k, entry.Fields[k], &encodeErrs)
+		}
+	}
+	if encodeErrs != nil {
+		errsJSON, _ := json.Marshal(encodeErrs)
+		b.WriteString(`,"_encode_error":`)
+		b.Write(errsJSON)
+	}
+}
+
+// writeFieldJSON escreve um par ,"chave":valor, prefixando com "fields." as
+// chaves reservadas e as que sombreiam caller/function.
+func writeFieldJSON(b *bytes.Buffer, entry *Entry, k string, v any, encodeErrs *map[string]string) {
+	for _, reserved := range jsonReservedKeys {
+		if k == reserved {
+			k = "fields." + k
+			break
+		}
+	}
+	if entry.shadowsMeta(k) {
+		k = "fields." + k
+	}
+	b.WriteByte(',')
+	if !appendJSONScalar(b, k) {
+		key, _ := json.Marshal(k)
+		b.Write(key)
+	}
+	b.WriteByte(':')
+	if appendJSONScalar(b, v) {
+		return
+	}
+	val, err := json.Marshal(v)
 	if err != nil {
-		return nil, err
+		if *encodeErrs == nil {
+			*encodeErrs = make(map[string]string)
+		}
+		(*encodeErrs)[k] = err.Error()
+		val, _ = json.Marshal(jsonFallbackValue(v))
 	}
+	b.Write(val)
+}
 
-	// Adiciona uma nova linha para que cada log JSON fique em sua própria linha
-	return append(b, '\n'), nil
+// jsonReservedKeys são as chaves fixas do JSONFormatter.
+var jsonReservedKeys = []string{"timestamp", "level", "message"}
+
+// appendJSONScalar escreve diretamente os valores mais comuns dos campos
+// tipados (String, Int, Int64, Bool), evitando json.Marshal; retorna false
+// para os demais tipos e para strings que exigiriam escape.
+func appendJSONScalar(b *bytes.Buffer, v any) bool {
+	switch v := v.(type) {
+	case string:
+		for i := 0; i < len(v); i++ {
+			if c := v[i]; c < 0x20 || c >= 0x7f || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+				return false
+			}
+		}
+		b.WriteByte('"')
+		b.WriteString(v)
+		b.WriteByte('"')
+	case int:
+		b.Write(strconv.AppendInt(b.AvailableBuffer(), int64(v), 10))
+	case int64:
+		b.Write(strconv.AppendInt(b.AvailableBuffer(), v, 10))
+	case bool:
+		b.Write(strconv.AppendBool(b.AvailableBuffer(), v))
+	default:
+		return false
+	}
+	return true
+}
+
+// jsonSafeFields retorna uma cópia de fields em que os valores que não podem
+// ser serializados em JSON são substituídos por uma representação em string,
+// registrando o erro de cada chave (com caminho pontuado) em errs. visiting
+// guarda os mapas do caminho atual para detectar ciclos.
+func jsonSafeFields(fields map[string]interface{}, prefix string, errs map[string]string, visiting map[uintptr]bool) map[string]interface{} {
+	ptr := reflect.ValueOf(fields).Pointer()
+	visiting[ptr] = true
+	defer delete(visiting, ptr)
+	out := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		if m, ok := v.(map[string]interface{}); ok {
+			if visiting[reflect.ValueOf(m).Pointer()] {
+				errs[prefix+k] = "json: unsupported value: encountered a cycle"
+				out[k] = jsonFallbackValue(v)
+				continue
+			}
+			out[k] = jsonSafeFields(m, prefix+k+".", errs, visiting)
+			continue
+		}
+		if _, err := json.Marshal(v); err != nil {
+			errs[prefix+k] = err.Error()
+			v = jsonFallbackValue(v)
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// jsonFallbackValue representa um valor não serializável. Floats especiais
+// viram "NaN", "+Inf" ou "-Inf"; demais tipos viram "<tipo>" (sem usar %v,
+// que entraria em loop em valores cíclicos).
+func jsonFallbackValue(v interface{}) interface{} {
+	switch f := v.(type) {
+	case float64:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(f), 'g', -1, 32)
+	}
+	return fmt.Sprintf("<%T>", v)
 }
 
 // mergeFields faz merge recursivo de campos, suportando campos aninhados.
@@ -95,36 +417,63 @@ func mergeFields(dst, src map[string]interface{}) {
 	}
 }
 
-// EmojiFormatter adiciona emojis de acordo com o nível do log.
+// EmojiFormatter adiciona emojis de acordo com o nível do log (equivale a um
+// ChainFormatter com AddLevelEmoji).
 type EmojiFormatter struct {
 	Base Formatter // Formatter base (TextFormatter, JSONFormatter, etc)
 }
 
 var levelEmojis = map[Level]string{
+	TRACE: "🔍",
 	DEBUG: "🐛",
 	INFO:  "ℹ️",
 	WARN:  "⚠️",
 	ERROR: "❌",
+	PANIC: "🔥",
+	FATAL: "💀",
 }
 
 func (f *EmojiFormatter) Format(entry *Entry) ([]byte, error) {
-	if f.Base == nil {
-		f.Base = &TextFormatter{}
-	}
-	emoji := levelEmojis[entry.Level]
+	chain := ChainFormatter{Transforms: []EntryTransform{AddLevelEmoji}, Terminal: f.Base}
+	return chain.Format(entry)
+}
 
-	// Cria cópia da entry para não mutar a original
-	copiedFields := make(map[string]interface{})
-	for k, v := range entry.Fields {
-		copiedFields[k] = v
-	}
-	copiedFields["emoji"] = emoji
+// ColorFormatter colore cada linha com códigos ANSI de acordo com o nível,
+// para leitura em terminais.
+type ColorFormatter struct {
+	Base Formatter // Formatter base (usa TextFormatter se nil)
+}
+
+var levelColors = map[Level]string{
+	TRACE: "\x1b[2;90m", // cinza esmaecido
+	DEBUG: "\x1b[90m",   // cinza
+	INFO:  "\x1b[36m",   // ciano
+	WARN:  "\x1b[33m",   // amarelo
+	ERROR: "\x1b[31m",   // vermelho
+	PANIC: "\x1b[35m",   // magenta
+	FATAL: "\x1b[1;31m", // vermelho em negrito
+}
 
-	copiedEntry := &Entry{
-		Level:     entry.Level,
-		Timestamp: entry.Timestamp,
-		Message:   entry.Message,
-		Fields:    copiedFields,
+func (f *ColorFormatter) Format(entry *Entry) ([]byte, error) {
+	base := f.Base
+	if base == nil {
+		base = &TextFormatter{}
+	}
+	out, err := base.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	color, ok := levelColors[entry.Level]
+	if !ok {
+		return out, nil
+	}
+	line := bytes.TrimSuffix(out, []byte("\n"))
+	b := make([]byte, 0, len(out)+len(color)+5)
+	b = append(b, color...)
+	b = append(b, line...)
+	b = append(b, "\x1b[0m"...)
+	if len(line) < len(out) {
+		b = append(b, '\n')
 	}
-	return f.Base.Format(copiedEntry)
+	return b, nil
 }
@@ -0,0 +1,118 @@
+package lazylog
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"sync"
+)
+
+// ForwardTransport encaminha as entries de um processo filho (ex: worker de
+// um servidor prefork) ao processo pai por um pipe ou socket. O pai, que é o
+// dono dos transportes de arquivo/rede, as recebe com ServeForwarded, evitando
+// rotação e conexões duplicadas.
+type ForwardTransport struct {
+	Writer io.Writer
+	Level  Level
+
+	mu    sync.Mutex
+	stats transportStats
+}
+
+// NewForwardTransport cria um ForwardTransport que escreve em w (ex: o pipe
+// herdado via exec.Cmd.ExtraFiles, os.NewFile(3, "lazylog")).
+func NewForwardTransport(w io.Writer, level Level) *ForwardTransport {
+	return &ForwardTransport{Writer: w, Level: level}
+}
+
+// DialForwardTransport conecta ao socket do processo pai (ex: "unix", "/run/app/log.sock").
+func DialForwardTransport(network, address string, level Level) (*ForwardTransport, error) {
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return NewForwardTransport(conn, level), nil
+}
+
+func (f *ForwardTransport) WriteLog(entry *Entry) error {
+	_, err := f.writeLogN(entry)
+	return err
+}
+
+// writeLogN escreve a entry como uma linha JSON e retorna os bytes gravados.
+func (f *ForwardTransport) writeLogN(entry *Entry) (n int, err error) {
+	defer func() { f.stats.record(f, n, err) }()
+	line, err := marshalForwarded(entry)
+	if err != nil {
+		return 0, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.Writer.Write(line)
+}
+
+// marshalForwarded serializa a entry como uma linha JSON (EntryWire, com "\n").
+func marshalForwarded(entry *Entry) ([]byte, error) {
+	line, err := entry.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
+
+// unmarshalForwarded reconstrói uma entry a partir de uma linha JSON.
+func unmarshalForwarded(line []byte) (Entry, error) {
+	var entry Entry
+	err := entry.UnmarshalJSON(line)
+	return entry, err
+}
+
+func (f *ForwardTransport) MinLevel() Level {
+	return f.Level
+}
+
+// Close fecha o writer, se ele implementar io.Closer.
+func (f *ForwardTransport) Close() error {
+	if c, ok := f.Writer.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// ServeForwarded lê entries encaminhadas por ForwardTransport de r e as
+// despacha pelos transportes e hooks do logger, preservando nível e
+// timestamp originais. Retorna quando r chega ao fim (nil) ou falha.
+func (l *Logger) ServeForwarded(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		entry, err := unmarshalForwarded(sc.Bytes())
+		if err != nil {
+			continue // linha corrompida (ex: filho morto no meio da escrita)
+		}
+		if int64(entry.Level) < l.core().minLevelOf() {
+			continue
+		}
+		dispatchEntry(l.snapshot(), &entry, nil)
+	}
+	return sc.Err()
+}
+
+// ListenForwarded aceita conexões de processos filhos em ln e serve cada uma
+// com ServeForwarded. Retorna quando ln é fechado.
+func (l *Logger) ListenForwarded(ln net.Listener) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			l.ServeForwarded(conn)
+		}()
+	}
+}
@@ -0,0 +1,84 @@
+package lazylog
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// EnableGoroutineInfo registra um before-hook que adiciona o campo
+// "goroutine_id" a toda entry e, se withCreator for true, também
+// "goroutine_created_by" com a instrução go que criou a goroutine
+// ("pkg.func (file:line)").
+//
+// É CARO: cada entry captura a pilha via runtime.Stack (e, com withCreator, a
+// pilha completa). Use apenas para depurar problemas de concorrência; fica
+// desativado por padrão.
+func (l *Logger) EnableGoroutineInfo(withCreator bool) {
+	l.AddHook(func(entry *Entry) {
+		stack := goroutineStack(withCreator)
+		id, ok := parseGoroutineID(stack)
+		if !ok {
+			return
+		}
+		if entry.Fields == nil {
+			entry.Fields = make(map[string]interface{})
+		}
+		entry.Fields["goroutine_id"] = id
+		if withCreator {
+			if site := parseGoroutineCreator(stack); site != "" {
+				entry.Fields["goroutine_created_by"] = site
+			}
+		}
+	}, true)
+}
+
+// goroutineStack retorna a pilha da goroutine atual; apenas o cabeçalho se
+// full for false.
+func goroutineStack(full bool) []byte {
+	if !full {
+		buf := make([]byte, 64)
+		return buf[:runtime.Stack(buf, false)]
+	}
+	buf := make([]byte, 4096)
+	for {
+		n := runtime.Stack(buf, false)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// parseGoroutineID extrai o ID de "goroutine 123 [running]:".
+func parseGoroutineID(stack []byte) (int64, bool) {
+	rest, ok := bytes.CutPrefix(stack, []byte("goroutine "))
+	if !ok {
+		return 0, false
+	}
+	end := bytes.IndexByte(rest, ' ')
+	if end < 0 {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(string(rest[:end]), 10, 64)
+	return id, err == nil
+}
+
+// parseGoroutineCreator extrai o local de criação das linhas
+// "created by pkg.func in goroutine N" e "\t/path/file.go:42 +0x1d".
+func parseGoroutineCreator(stack []byte) string {
+	i := bytes.LastIndex(stack, []byte("\ncreated by "))
+	if i < 0 {
+		return "" // goroutine principal
+	}
+	lines := bytes.SplitN(stack[i+len("\ncreated by "):], []byte("\n"), 3)
+	fn, _, _ := bytes.Cut(lines[0], []byte(" in goroutine "))
+	if len(lines) < 2 {
+		return string(fn)
+	}
+	loc := bytes.TrimSpace(lines[1])
+	if j := bytes.LastIndex(loc, []byte(" +0x")); j >= 0 {
+		loc = loc[:j]
+	}
+	return string(fn) + " (" + string(loc) + ")"
+}
@@ -0,0 +1,107 @@
+package lazylog
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// statusRecorder captura o status e os bytes escritos pela resposta.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Flush repassa o flush ao writer original, para respostas em streaming (SSE).
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		if r.status == 0 {
+			r.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// Hijack repassa o hijack ao writer original (ex: upgrade para WebSocket).
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("lazylog: %T does not implement http.Hijacker: %w", r.ResponseWriter, http.ErrNotSupported)
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && r.status == 0 {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap permite que http.ResponseController acesse o writer original.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// CanonicalMiddleware é um middleware net/http que cria uma CanonicalEntry por
+// requisição (acessível via CanonicalEntryFromContext) e a emite ao final com
+// method, path, status e bytes_out, além dos campos de correlação recebidos em
+// CorrelationHeader e de trace_id/span_id do header traceparent (que também
+// chegam aos métodos *Ctx do handler). Respostas 5xx são emitidas como ERROR.
+// Se o handler entrar em panic, a entry é emitida com status 500 e o campo
+// "panic" (ver LogPanic) e o panic é repropagado.
+func (l *Logger) CanonicalMiddleware(next http.Handler) http.Handler {
+	if l.core().nop {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ce := l.NewCanonicalEntry()
+		for k, v := range l.ExtractCorrelation(r.Header) {
+			ce.Set(k, v)
+		}
+		ctx := ContextWithCanonicalEntry(r.Context(), ce)
+		if tc, ok := TraceContextFromHeaders(r.Header); ok {
+			ce.Set("trace_id", tc.TraceID)
+			ce.Set("span_id", tc.SpanID)
+			ctx = AppendCtxFields(ctx, tc.Fields())
+		}
+		rec := &statusRecorder{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v != nil {
+				rec.status = http.StatusInternalServerError
+				ce.Set("panic", panicFields(v))
+			} else if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			ce.Set("method", r.Method)
+			ce.Set("path", r.URL.Path)
+			ce.Set("status", rec.status)
+			ce.Set("bytes_out", rec.bytes)
+			level := INFO
+			if rec.status >= http.StatusInternalServerError {
+				level = ERROR
+			}
+			ce.Emit(level, "request completed")
+			if v != nil {
+				panic(v)
+			}
+		}()
+		next.ServeHTTP(rec, r.WithContext(ctx))
+	})
+}
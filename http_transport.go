@@ -0,0 +1,150 @@
+package lazylog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrInFlightLimit indica que uma escrita do HTTPTransport desistiu de esperar
+// por uma vaga de MaxInFlight.
+var ErrInFlightLimit = errors.New("lazylog: http transport in-flight limit reached")
+
+// HTTPTransport envia cada entry formatada via POST para um endpoint HTTP(S).
+type HTTPTransport struct {
+	URL       string
+	Level     Level
+	Formatter Formatter         // usa JSONFormatter se nil
+	Client    *http.Client      // usa um client com timeout de 10s se nil
+	Headers   map[string]string // headers extras enviados em toda requisição
+	// Compression comprime o corpo ("gzip" ou um algoritmo registrado via
+	// RegisterCompressor) e define o header Content-Encoding. Vazio desativa.
+	Compression string
+	// Auth autentica cada requisição (API key, bearer, OAuth2, SigV4...).
+	Auth AuthProvider
+	// MaxInFlight limita as requisições simultâneas deste transporte (0 = sem
+	// limite), evitando saturar a rede em rajadas. Escritas acima do limite
+	// esperam por uma vaga até InFlightWait e então falham com
+	// ErrInFlightLimit. O valor é lido na primeira escrita: alterações
+	// posteriores não redimensionam o limite.
+	MaxInFlight int
+	// InFlightWait limita a espera por uma vaga de MaxInFlight. Se zero, usa o
+	// Timeout do client (ou 10s, se o client não tiver timeout).
+	InFlightWait time.Duration
+
+	inFlightOnce sync.Once
+	inFlight     chan struct{}
+
+	stats transportStats
+}
+
+// NewHTTPTransport cria um HTTPTransport, aplicando as opções de TLS (opcionais).
+// Para proxy ou dialer customizado, substitua Client usando NewHTTPClient.
+func NewHTTPTransport(url string, level Level, formatter Formatter, tlsOpts *TLSConfigOptions) (*HTTPTransport, error) {
+	client, err := NewHTTPClient(tlsOpts, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &HTTPTransport{
+		URL:       url,
+		Level:     level,
+		Formatter: formatter,
+		Client:    client,
+	}, nil
+}
+
+func (h *HTTPTransport) WriteLog(entry *Entry) error {
+	_, err := h.writeLogN(entry)
+	return err
+}
+
+// writeLogN envia a entry e retorna a quantidade de bytes do corpo.
+func (h *HTTPTransport) writeLogN(entry *Entry) (n int, err error) {
+	defer func() { h.stats.record(h, n, err) }()
+	formatter := h.Formatter
+	if formatter == nil {
+		formatter = &JSONFormatter{}
+	}
+	body, err := formatter.Format(entry)
+	if err != nil {
+		return 0, err
+	}
+	if h.Compression != "" {
+		if body, err = compressBody(h.Compression, body); err != nil {
+			return 0, err
+		}
+	}
+	// O contexto da entry carrega valores (trace, credenciais), mas não seu
+	// cancelamento: logs de uma requisição cujo cliente desconectou são
+	// justamente os que mais importam. O limite de tempo vem do client.
+	ctx := context.WithoutCancel(entry.Context())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := formatter.(*JSONFormatter); ok {
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	if h.Compression != "" {
+		req.Header.Set("Content-Encoding", h.Compression)
+	}
+	for k, v := range h.Headers {
+		req.Header.Set(k, v)
+	}
+	if h.Auth != nil {
+		if err := h.Auth.Authenticate(req); err != nil {
+			return 0, err
+		}
+	}
+	client := h.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if h.MaxInFlight > 0 {
+		h.inFlightOnce.Do(func() { h.inFlight = make(chan struct{}, h.MaxInFlight) })
+		wait := h.InFlightWait
+		if wait <= 0 {
+			wait = client.Timeout
+		}
+		if wait <= 0 {
+			wait = 10 * time.Second
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case h.inFlight <- struct{}{}:
+			timer.Stop()
+			defer func() { <-h.inFlight }()
+		case <-timer.C:
+			return 0, ErrInFlightLimit
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("lazylog: http transport got status %d", resp.StatusCode)
+	}
+	return len(body), nil
+}
+
+func (h *HTTPTransport) MinLevel() Level {
+	return h.Level
+}
+
+// Close libera as conexões ociosas do client HTTP.
+func (h *HTTPTransport) Close() error {
+	if h.Client != nil {
+		h.Client.CloseIdleConnections()
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+//go:build integration
+
+package lazylog_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chmenegatti/lazylog"
+)
+
+// Testes de integração contra sinks reais. Rode com scripts/integration.sh,
+// que sobe os containers e exporta as variáveis LAZYLOG_IT_*.
+
+func integrationURL(t *testing.T, env string) string {
+	t.Helper()
+	url := os.Getenv(env)
+	if url == "" {
+		t.Skipf("%s não definido", env)
+	}
+	return url
+}
+
+func TestIntegrationElasticsearchDelivery(t *testing.T) {
+	base := integrationURL(t, "LAZYLOG_IT_ES_URL")
+	index := fmt.Sprintf("lazylog-it-%d", time.Now().UnixNano())
+	tr, err := lazylog.NewHTTPTransport(base+"/"+index+"/_doc", lazylog.DEBUG, &lazylog.JSONFormatter{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	async := lazylog.NewAsyncTransport(tr, 64, lazylog.OverflowBlock)
+	logger := lazylog.NewLogger(async)
+	const n = 20
+	for i := 0; i < n; i++ {
+		logger.ComFields(map[string]interface{}{"seq": i}).Info("integration")
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Get(base + "/" + index + "/_search?refresh=true&size=100&sort=seq:asc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				Source map[string]interface{} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Hits.Hits) != n {
+		t.Fatalf("expected %d documents, got %d", n, len(result.Hits.Hits))
+	}
+	var prev time.Time
+	for i, hit := range result.Hits.Hits {
+		ts, _ := time.Parse(time.RFC3339Nano, fmt.Sprint(hit.Source["timestamp"]))
+		if hit.Source["seq"] != float64(i) || ts.Before(prev) {
+			t.Errorf("out of order delivery at %d: %v", i, hit.Source)
+		}
+		prev = ts
+	}
+}
+
+func TestIntegrationHTTPErrorReported(t *testing.T) {
+	base := integrationURL(t, "LAZYLOG_IT_ES_URL")
+	tr, err := lazylog.NewHTTPTransport(base+"/_nonexistent_endpoint/_doc/", lazylog.DEBUG, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := lazylog.NewLogger(tr)
+	var got error
+	logger.AddErrorHook(func(e *lazylog.Entry, tr lazylog.Transport, err error) { got = err })
+	logger.Info("should fail")
+	if got == nil || !strings.Contains(got.Error(), "status") {
+		t.Errorf("expected HTTP status error, got %v", got)
+	}
+}
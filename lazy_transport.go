@@ -0,0 +1,135 @@
+package lazylog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrTransportNotReady é retornado por LazyTransport enquanto a conexão não
+// pôde ser estabelecida.
+var ErrTransportNotReady = errors.New("lazylog: transport not ready")
+
+// DefaultLazyRetryInterval é o intervalo mínimo entre tentativas de conexão
+// de um LazyTransport quando RetryInterval é zero.
+const DefaultLazyRetryInterval = time.Second
+
+// ReadinessProber é implementado por transportes capazes de informar se estão
+// prontos para entregar entries (ex: conexão estabelecida).
+type ReadinessProber interface {
+	Ready(ctx context.Context) error
+}
+
+// LazyTransport adia a criação de um transporte de rede até a primeira escrita
+// (ou até Ready), permitindo construir o logger antes de a rede estar de pé
+// (boot inicial, sidecar ainda subindo). Falhas de conexão são retentadas no
+// máximo a cada RetryInterval; nesse meio tempo as escritas retornam
+// ErrTransportNotReady.
+type LazyTransport struct {
+	Level Level
+	// Dial cria o transporte real (ex: DialForwardTransport, NewSyslogTransport).
+	Dial          func(ctx context.Context) (Transport, error)
+	RetryInterval time.Duration
+
+	mu        sync.Mutex
+	inner     Transport
+	lastErr   error
+	nextRetry time.Time
+	closed    bool
+}
+
+// NewLazyTransport cria um LazyTransport que conecta apenas na primeira escrita.
+func NewLazyTransport(level Level, dial func(ctx context.Context) (Transport, error)) *LazyTransport {
+	return &LazyTransport{Level: level, Dial: dial}
+}
+
+// NewEagerTransport conecta imediatamente (aguardando até ctx expirar) e
+// retorna um LazyTransport já pronto; se a conexão falhar, retorna o erro.
+func NewEagerTransport(ctx context.Context, level Level, dial func(ctx context.Context) (Transport, error)) (*LazyTransport, error) {
+	t := NewLazyTransport(level, dial)
+	if err := t.Ready(ctx); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// connect retorna o transporte real, conectando se necessário. force ignora
+// o intervalo entre tentativas.
+func (t *LazyTransport) connect(ctx context.Context, force bool) (Transport, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil, errors.New("lazylog: lazy transport is closed")
+	}
+	if t.inner != nil {
+		return t.inner, nil
+	}
+	if !force && time.Now().Before(t.nextRetry) {
+		return nil, fmt.Errorf("%w: %v", ErrTransportNotReady, t.lastErr)
+	}
+	inner, err := t.Dial(ctx)
+	if err != nil {
+		interval := t.RetryInterval
+		if interval <= 0 {
+			interval = DefaultLazyRetryInterval
+		}
+		t.lastErr = err
+		t.nextRetry = time.Now().Add(interval)
+		return nil, fmt.Errorf("%w: %v", ErrTransportNotReady, err)
+	}
+	t.inner = inner
+	return inner, nil
+}
+
+func (t *LazyTransport) WriteLog(entry *Entry) error {
+	// Como no HTTPTransport, o cancelamento da entry não deve abortar a conexão.
+	inner, err := t.connect(context.WithoutCancel(entry.Context()), false)
+	if err != nil {
+		return err
+	}
+	return inner.WriteLog(entry)
+}
+
+func (t *LazyTransport) MinLevel() Level {
+	return t.Level
+}
+
+// Ready conecta (ignorando o intervalo entre tentativas) e, se o transporte
+// real também implementar ReadinessProber, delega a verificação a ele.
+func (t *LazyTransport) Ready(ctx context.Context) error {
+	inner, err := t.connect(ctx, true)
+	if err != nil {
+		return err
+	}
+	if p, ok := inner.(ReadinessProber); ok {
+		return p.Ready(ctx)
+	}
+	return nil
+}
+
+// Close fecha o transporte real, se ele já tiver sido criado.
+func (t *LazyTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	if t.inner == nil {
+		return nil
+	}
+	return closeTransport(t.inner)
+}
+
+// Ready verifica todos os transportes que implementam ReadinessProber (ex:
+// para um endpoint de readiness) e retorna os erros encontrados.
+func (l *Logger) Ready(ctx context.Context) error {
+	var errs []error
+	for _, t := range l.snapshot().transports {
+		if p, ok := t.(ReadinessProber); ok {
+			if err := p.Ready(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
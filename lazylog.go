@@ -3,11 +3,14 @@ package lazylog
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -15,6 +18,10 @@ import (
 
 type Hook func(entry *Entry)
 
+// BackpressureHandler é chamado quando um transporte assíncrono descarta uma
+// entry por estar saturado (ErrQueueFull).
+type BackpressureHandler func(entry *Entry, transport Transport)
+
 // TransportErrorHook é chamado quando um transporte falha ao gravar.
 type TransportErrorHook func(entry *Entry, transport Transport, err error)
 
@@ -24,25 +31,115 @@ type StacktraceConfig struct {
 	Levels  map[Level]bool // Níveis que devem incluir stacktrace
 }
 
-// Logger é o logger principal, thread-safe para uso concorrente.
+// Logger é o logger principal, thread-safe para uso concorrente. Transportes
+// e hooks podem ser adicionados e removidos enquanto outras goroutines
+// registram logs.
 type Logger struct {
-	mu          sync.RWMutex
+	// Out e Formatter são a saída e o formato dos loggers criados por New
+	// (superfície compatível com o logrus). Atribuídos diretamente, só têm
+	// efeito nesses loggers; nos criados por NewLogger, use SetOutput, que
+	// instala a saída em Out na primeira chamada.
+	Out       io.Writer
+	Formatter Formatter
+
+	mu sync.RWMutex
+	// Os slices abaixo são copy-on-write: mutações sempre criam um novo slice,
+	// de forma que o snapshot usado por um log em andamento nunca é alterado.
 	transports  []Transport
 	beforeHooks []Hook
 	afterHooks  []Hook
 	errorHooks  []TransportErrorHook
 	stacktrace  StacktraceConfig
+	onBackpress BackpressureHandler
+	onceSeen    sync.Map // chave -> time.Time da última emissão (Once/OnceEvery)
+	sanitize    bool
+	sampler     *sampler // EnableSampling
+	caller      bool     // EnableCaller
+	callerSkip  int
+	function    bool // EnableFunction
+
+	processInfo   bool           // EnableProcessInfo
+	processFields map[string]any // nil se desativado
+	appName       string         // SetAppInfo
+	appVersion    string
+	defaultFields map[string]any // SetDefaultFields
+	staticFields  map[string]any // defaultFields sobre processFields (ver refreshStaticFieldsLocked)
+
+	correlationKeys []string // SetCorrelationKeys; nil = DefaultCorrelationKeys
+
+	// Nível mínimo do logger inteiro (SetLevel), aplicado antes dos
+	// transportes.
+	level    Level
+	hasLevel bool
+
+	// Nível usado por Print, Println e Printf (SetPrintLevel; padrão INFO).
+	printLevel    Level
+	hasPrintLevel bool
+
+	// Cache do menor nível aceito entre os transportes (e não abaixo do nível
+	// do logger), para descartar com uma única comparação as entries que
+	// nenhum transporte quer. Só é consultado com EnableLevelCache.
+	minLevel      atomic.Int64
+	minLevelValid atomic.Bool
+	levelCache    atomic.Bool
+
+	// Overrides de nível por pacote (SetPackageLevel). hasPkgLevels evita a
+	// detecção do caller quando não há overrides.
+	pkgLevels    []packageLevel
+	hasPkgLevels atomic.Bool
+
+	crashDump    *crashDumper // EnableCrashDump
+	exitHandlers []func()     // OnExit
+	closed       bool         // Close já executado
+
+	// Transportes substituídos por ApplyConfig aguardando o período de
+	// carência (SetReloadGrace); Close fecha closing para encerrá-los já.
+	reloadGrace    time.Duration
+	hasReloadGrace bool
+	retiring       sync.WaitGroup
+	closing        chan struct{}
+
+	// Overrides de nível por nome de logger (SetNameLevel).
+	nameLevels    []nameLevel
+	hasNameLevels atomic.Bool
+
+	// Loggers derivados (WithFields, Named) compartilham o estado do pai e
+	// apenas carregam os campos vinculados e o nome.
+	parent *Logger
+	bound  map[string]any
+	name   string
+
+	nop bool // Nop: descarta tudo e ignora alterações de configuração
+}
+
+// core retorna o logger que detém o estado compartilhado (o próprio logger,
+// ou o pai de um logger derivado via WithFields). Um logger nil é tratado como
+// Nop.
+func (l *Logger) core() *Logger {
+	if l == nil {
+		return nopLogger
+	}
+	if l.parent != nil {
+		return l.parent
+	}
+	return l
 }
 
 // NewLogger cria um logger com zero ou mais transportes.
 func NewLogger(transports ...Transport) *Logger {
-	return &Logger{
-		transports: transports,
+	l := &Logger{
+		transports: append([]Transport(nil), transports...),
 	}
+	l.refreshMinLevelLocked()
+	return l
 }
 
 // EnableStacktrace ativa stacktrace automático para os níveis informados.
 func (l *Logger) EnableStacktrace(levels ...Level) {
+	l = l.core()
+	if l.nop {
+		return
+	}
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.stacktrace.Enabled = true
@@ -54,18 +151,29 @@ func (l *Logger) EnableStacktrace(levels ...Level) {
 
 // AddTransport adiciona um novo transporte ao logger.
 func (l *Logger) AddTransport(t Transport) {
+	l = l.core()
+	if l.nop {
+		return
+	}
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	l.transports = append(l.transports, t)
+	l.transports = cowAppend(l.transports, t)
+	l.refreshMinLevelLocked()
 }
 
 // RemoveTransport remove um transporte do logger (por comparação de ponteiro).
+// Logs já em andamento podem ainda escrever no transporte removido; aguarde-os
+// (ou use um transporte tolerante) antes de fechá-lo.
 func (l *Logger) RemoveTransport(t Transport) {
+	l = l.core()
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	for i, tr := range l.transports {
 		if tr == t {
-			l.transports = append(l.transports[:i], l.transports[i+1:]...)
+			next := make([]Transport, 0, len(l.transports)-1)
+			next = append(next, l.transports[:i]...)
+			l.transports = append(next, l.transports[i+1:]...)
+			l.refreshMinLevelLocked()
 			return
 		}
 	}
@@ -73,38 +181,150 @@ func (l *Logger) RemoveTransport(t Transport) {
 
 // AddHook adiciona um hook para ser executado antes ou depois do log.
 func (l *Logger) AddHook(hook Hook, before bool) {
+	l = l.core()
+	if l.nop {
+		return
+	}
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	if before {
-		l.beforeHooks = append(l.beforeHooks, hook)
+		l.beforeHooks = cowAppend(l.beforeHooks, hook)
 	} else {
-		l.afterHooks = append(l.afterHooks, hook)
+		l.afterHooks = cowAppend(l.afterHooks, hook)
 	}
+	l.refreshMinLevelLocked()
 }
 
 // AddErrorHook adiciona um hook para erros de transporte.
 func (l *Logger) AddErrorHook(hook TransportErrorHook) {
+	l = l.core()
+	if l.nop {
+		return
+	}
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	l.errorHooks = append(l.errorHooks, hook)
+	l.errorHooks = cowAppend(l.errorHooks, hook)
 }
 
-// Close fecha todos os transportes que implementam io.Closer.
-func (l *Logger) Close() error {
+// OnBackpressure registra um callback chamado quando um transporte assíncrono
+// saturado descarta uma entry, permitindo que serviços sensíveis à latência
+// reduzam a carga de logging deliberadamente.
+func (l *Logger) OnBackpressure(fn BackpressureHandler) {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onBackpress = fn
+}
+
+// OnExit registra um handler executado por Fatal antes de encerrar o processo
+// (os.Exit não executa defers). Os handlers rodam na ordem de registro; um
+// panic em um handler é ignorado para não impedir os demais.
+func (l *Logger) OnExit(fn func()) {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.exitHandlers = append(l.exitHandlers, fn)
+}
+
+// runExitHandlers executa os handlers registrados via OnExit.
+func (l *Logger) runExitHandlers() {
+	l = l.core()
 	l.mu.RLock()
-	transports := make([]Transport, len(l.transports))
-	copy(transports, l.transports)
+	handlers := make([]func(), len(l.exitHandlers))
+	copy(handlers, l.exitHandlers)
 	l.mu.RUnlock()
+	for _, fn := range handlers {
+		func() {
+			defer func() { recover() }()
+			fn()
+		}()
+	}
+}
+
+// Flusher é implementado por transportes que mantêm entries em buffer (ex:
+// SpoolTransport, AggregatingTransport) ou em cache do sistema (FileTransport).
+type Flusher interface {
+	Flush() error
+}
+
+// Flush força a escrita das entries pendentes em todos os transportes: aguarda
+// as filas assíncronas, entrega spools e resumos agregados e faz fsync dos
+// arquivos (transportes que implementam Flusher, atravessando decorators).
+// Útil antes de encerrar o processo ou em testes; o logger continua ativo.
+func (l *Logger) Flush() error {
+	var errs []error
+	for _, t := range l.snapshot().transports {
+		if err := flushTransport(t); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Sync é um alias de Flush, para quem vem de outras bibliotecas de log.
+func (l *Logger) Sync() error {
+	return l.Flush()
+}
+
+// flushTransport chama Flush no primeiro Flusher encontrado em t ou nos
+// transportes envolvidos por ele.
+func flushTransport(t Transport) error {
+	if f, ok := t.(Flusher); ok {
+		return f.Flush()
+	}
+	if inner, ok := unwrapTransport(t); ok {
+		return flushTransport(inner)
+	}
+	return nil
+}
 
-	var firstErr error
+// Close encerra o logger: descarrega os transportes (Flush) e
+// depois fecha os que implementam io.Closer (arquivo, syslog, lumberjack,
+// filas assíncronas, que drenam as entries pendentes...). Os erros de todos
+// os transportes são unidos com errors.Join. Chamadas seguintes não fazem
+// nada, de forma que `defer logger.Close()` é seguro mesmo se Fatal já tiver
+// fechado o logger.
+func (l *Logger) Close() error {
+	l = l.core()
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	transports := l.transports
+	if l.closing != nil {
+		close(l.closing)
+	}
+	l.mu.Unlock()
+	l.retiring.Wait()
+
+	var errs []error
 	for _, t := range transports {
-		if closer, ok := t.(io.Closer); ok {
-			if err := closer.Close(); err != nil && firstErr == nil {
-				firstErr = err
-			}
+		if err := flushTransport(t); err != nil {
+			errs = append(errs, err)
 		}
 	}
-	return firstErr
+	for _, t := range transports {
+		if err := closeTransport(t); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cowAppend retorna um novo slice com os elementos de s e v, sem escrever no
+// array de s (que pode estar em uso por um snapshot).
+func cowAppend[T any](s []T, v T) []T {
+	next := make([]T, len(s), len(s)+1)
+	copy(next, s)
+	return append(next, v)
 }
 
 // snapshot retorna cópias locais dos campos protegidos para uso seguro fora do lock.
@@ -114,9 +334,25 @@ type logSnapshot struct {
 	afterHooks  []Hook
 	errorHooks  []TransportErrorHook
 	stacktrace  StacktraceConfig
+	onBackpress BackpressureHandler
+	sanitize    bool
+	sampler     *sampler
+	caller      bool
+	callerSkip  int
+	function    bool
+	static      map[string]any // SetDefaultFields e EnableProcessInfo
+	bound       map[string]any // campos de um logger derivado (WithFields)
 }
 
 func (l *Logger) snapshot() logSnapshot {
+	if l == nil {
+		return logSnapshot{}
+	}
+	if l.parent != nil {
+		snap := l.parent.snapshot()
+		snap.bound = l.bound
+		return snap
+	}
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 	return logSnapshot{
@@ -125,14 +361,55 @@ func (l *Logger) snapshot() logSnapshot {
 		afterHooks:  l.afterHooks,
 		errorHooks:  l.errorHooks,
 		stacktrace:  l.stacktrace,
+		onBackpress: l.onBackpress,
+		sanitize:    l.sanitize,
+		sampler:     l.sampler,
+		caller:      l.caller,
+		callerSkip:  l.callerSkip,
+		function:    l.function,
+		static:      l.staticFields,
 	}
 }
 
 // dispatchEntry é a lógica centralizada de despacho de entry para transportes e hooks.
-func dispatchEntry(snap logSnapshot, entry *Entry, formatter Formatter) {
+// Retorna os erros de escrita de todos os transportes (combinados).
+func dispatchEntry(snap logSnapshot, entry *Entry, formatter Formatter) error {
+	if snap.sampler != nil && !snap.sampler.allow(entry.Level, entry.Message) {
+		return nil
+	}
+	if snap.caller && entry.Caller == nil || snap.function && entry.Function == "" {
+		if c := captureCaller(snap.callerSkip); c != nil {
+			if snap.caller && entry.Caller == nil {
+				entry.Caller = c
+			}
+			if snap.function && entry.Function == "" {
+				entry.Function = c.Function
+			}
+		}
+	}
+	var errs []error
+	if entry.typed != nil && (formatter != nil || len(snap.bound) > 0 || len(snap.static) > 0 ||
+		len(snap.beforeHooks) > 0 || snap.sanitize || hasFieldEncoders()) {
+		entry.materializeFields()
+	}
+	if len(snap.bound) > 0 {
+		entry.Fields = mergeBoundFields(snap.bound, entry.Fields)
+	}
+	if len(snap.static) > 0 {
+		entry.Fields = mergeBoundFields(snap.static, entry.Fields)
+	}
+	entry.Fields = resolveLazyFields(entry.Fields)
+	if len(snap.beforeHooks) > 0 {
+		// Hooks podem mutar os campos: garante que não alterem o mapa do chamador.
+		entry.Fields = cloneFields(entry.Fields)
+	}
 	for _, hook := range snap.beforeHooks {
 		hook(entry)
 	}
+	entry.Fields = encodeFields(entry.Fields)
+	if snap.sanitize {
+		sanitizeEntry(entry)
+	}
 	for _, t := range snap.transports {
 		if entry.Level >= t.MinLevel() {
 			var err error
@@ -146,18 +423,51 @@ func dispatchEntry(snap logSnapshot, entry *Entry, formatter Formatter) {
 					err = writeFormatted(t, formatted)
 				}
 			} else {
+				if entry.typed != nil && !writesTypedFields(t) {
+					entry.materializeFields()
+				}
 				err = t.WriteLog(entry)
 			}
 			if err != nil {
+				entry.materializeFields()
+				errs = append(errs, err)
+				if snap.onBackpress != nil && errors.Is(err, ErrQueueFull) {
+					snap.onBackpress(entry, t)
+				}
 				for _, eh := range snap.errorHooks {
 					eh(entry, t, err)
 				}
 			}
 		}
 	}
+	if len(snap.afterHooks) > 0 {
+		entry.materializeFields()
+	}
 	for _, hook := range snap.afterHooks {
 		hook(entry)
 	}
+	return errors.Join(errs...)
+}
+
+// typedFieldsWriter é implementado pelos transportes que só leem os campos
+// através de TextFormatter ou JSONFormatter e podem receber entries com campos
+// tipados (ver Entry.typed). Os demais recebem o mapa Fields.
+type typedFieldsWriter interface {
+	writesTypedFields() bool
+}
+
+func writesTypedFields(t Transport) bool {
+	tw, ok := t.(typedFieldsWriter)
+	return ok && tw.writesTypedFields()
+}
+
+// typedFormatter indica se f escreve os campos tipados diretamente.
+func typedFormatter(f Formatter) bool {
+	switch f.(type) {
+	case nil, *TextFormatter, *JSONFormatter:
+		return true
+	}
+	return false
 }
 
 // writeFormatted escreve bytes já formatados diretamente no writer do transporte.
@@ -187,6 +497,9 @@ func writeFormatted(t Transport, data []byte) error {
 
 // log envia a entry para todos os transportes cujo nível mínimo seja compatível.
 func (l *Logger) log(level Level, message string) {
+	if !l.enabledFor(level) {
+		return
+	}
 	snap := l.snapshot()
 	entry := Entry{
 		Level:     level,
@@ -202,6 +515,11 @@ func (l *Logger) log(level Level, message string) {
 	dispatchEntry(snap, &entry, nil)
 }
 
+// Trace registra uma mensagem no nível TRACE.
+func (l *Logger) Trace(message string) {
+	l.log(TRACE, message)
+}
+
 // Debug registra uma mensagem no nível DEBUG.
 func (l *Logger) Debug(message string) {
 	l.log(DEBUG, message)
@@ -222,36 +540,57 @@ func (l *Logger) Error(message string) {
 	l.log(ERROR, message)
 }
 
-// Fatal registra uma mensagem no nível ERROR, inclui stacktrace e encerra a aplicação.
+// Fatal registra uma mensagem no nível FATAL com stacktrace, executa os
+// handlers registrados via OnExit, fecha (e assim descarrega) todos os
+// transportes e encerra a aplicação com os.Exit(1).
 func (l *Logger) Fatal(message string, fields ...map[string]any) {
 	var flds map[string]any
 	if len(fields) > 0 {
 		flds = fields[0]
 	}
-	if flds == nil {
-		flds = make(map[string]any)
+	// withField copia o mapa: o do chamador não é alterado.
+	flds = withField(flds, "stacktrace", string(debug.Stack()))
+	if path := l.writeCrashDump("fatal", message); path != "" {
+		flds["crash_dump"] = path
 	}
-	flds["stacktrace"] = string(debug.Stack())
-	l.logWithFields(ERROR, message, flds)
+	l.logWithFields(FATAL, message, flds)
+	l.runExitHandlers()
+	l.Close()
 	os.Exit(1)
 }
 
-// Panic registra uma mensagem no nível ERROR, inclui stacktrace e faz panic.
+// Panic registra uma mensagem no nível PANIC, com a mensagem e a pilha
+// estruturadas no campo "panic" (panic.value, panic.stack), e faz panic. O
+// valor do panic é sempre a própria mensagem, por isso panic.type só é
+// preenchido para valores recuperados (LogPanic, RecoverAndLog).
 func (l *Logger) Panic(message string, fields ...map[string]any) {
 	var flds map[string]any
 	if len(fields) > 0 {
 		flds = fields[0]
 	}
-	if flds == nil {
-		flds = make(map[string]any)
+	pf := panicFields(message)
+	delete(pf, "type")
+	// withField copia o mapa: o do chamador não é alterado.
+	flds = withField(flds, "panic", pf)
+	if path := l.writeCrashDump("panic", message); path != "" {
+		flds["crash_dump"] = path
 	}
-	flds["stacktrace"] = string(debug.Stack())
-	l.logWithFields(ERROR, message, flds)
+	l.logWithFields(PANIC, message, flds)
 	panic(message)
 }
 
+// TryLog registra uma entry e retorna os erros de escrita dos transportes,
+// incluindo ErrQueueFull quando uma fila assíncrona está saturada. Útil para
+// quem precisa saber se o log foi descartado (use errors.Is).
+func (l *Logger) TryLog(level Level, message string, fields map[string]any) error {
+	return l.logWithFields(level, message, fields)
+}
+
 // logWithFields é usada internamente por EntryBuilder.
-func (l *Logger) logWithFields(level Level, message string, fields map[string]interface{}) {
+func (l *Logger) logWithFields(level Level, message string, fields map[string]interface{}) error {
+	if !l.enabledFor(level) {
+		return nil
+	}
 	snap := l.snapshot()
 	entry := Entry{
 		Level:     level,
@@ -260,46 +599,248 @@ func (l *Logger) logWithFields(level Level, message string, fields map[string]in
 		Fields:    fields,
 	}
 	if snap.stacktrace.Enabled && snap.stacktrace.Levels[level] {
-		if entry.Fields == nil {
-			entry.Fields = make(map[string]interface{})
+		entry.Fields = withField(entry.Fields, "stacktrace", string(debug.Stack()))
+	}
+	return dispatchEntry(snap, &entry, nil)
+}
+
+// EnableLevelCache passa a descartar as entries abaixo do nível de todos os
+// transportes com uma única comparação, contra um cache recalculado em
+// AddTransport, RemoveTransport, SetLevel e RefreshLevels. Sem ele, o nível
+// de cada transporte é consultado a cada entry.
+//
+// Com o cache, alterar diretamente o nível de um transporte já adicionado
+// (ex: console.Level = lazylog.DEBUG) só tem efeito após RefreshLevels.
+func (l *Logger) EnableLevelCache() {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.RefreshLevels()
+	l.levelCache.Store(true)
+}
+
+// DisableLevelCache volta a consultar o nível dos transportes a cada entry.
+func (l *Logger) DisableLevelCache() {
+	l.core().levelCache.Store(false)
+}
+
+// RefreshLevels recalcula o cache de nível mínimo dos transportes. Com
+// EnableLevelCache, é necessário se o nível de um transporte já adicionado
+// for alterado diretamente (ex: console.Level = lazylog.DEBUG).
+func (l *Logger) RefreshLevels() {
+	l = l.core()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refreshMinLevelLocked()
+}
+
+// refreshMinLevelLocked recalcula o cache de nível mínimo (com l.mu em escrita).
+func (l *Logger) refreshMinLevelLocked() {
+	l.minLevel.Store(l.computeMinLevelLocked())
+	l.minLevelValid.Store(true)
+}
+
+// computeMinLevelLocked retorna o menor nível aceito entre os transportes,
+// não abaixo do nível do logger (com l.mu em leitura ou escrita). Hooks
+// recebem entries de qualquer nível, então com hooks só SetLevel filtra.
+func (l *Logger) computeMinLevelLocked() int64 {
+	min := int64(math.MaxInt64)
+	if len(l.beforeHooks) > 0 || len(l.afterHooks) > 0 {
+		min = math.MinInt64
+	}
+	for _, t := range l.transports {
+		if lvl := int64(t.MinLevel()); lvl < min {
+			min = lvl
 		}
-		entry.Fields["stacktrace"] = string(debug.Stack())
 	}
-	dispatchEntry(snap, &entry, nil)
+	if l.hasLevel && int64(l.level) > min {
+		min = int64(l.level)
+	}
+	return min
+}
+
+// minLevelOf retorna o menor nível aceito pelo logger: o do cache com
+// EnableLevelCache, senão o nível atual dos transportes.
+func (l *Logger) minLevelOf() int64 {
+	if l.levelCache.Load() {
+		if !l.minLevelValid.Load() {
+			l.RefreshLevels()
+		}
+		return l.minLevel.Load()
+	}
+	l.mu.RLock()
+	min := l.computeMinLevelLocked()
+	l.mu.RUnlock()
+	return min
+}
+
+// SetLevel define o nível mínimo do logger inteiro: entries abaixo dele são
+// descartadas antes de qualquer construção ou hook, independentemente do
+// nível dos transportes. Pode ser chamado em runtime.
+func (l *Logger) SetLevel(level Level) {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level, l.hasLevel = level, true
+	l.refreshMinLevelLocked()
+}
+
+// GetLevel retorna o nível definido por SetLevel (TRACE se nunca definido).
+func (l *Logger) GetLevel() Level {
+	l = l.core()
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if !l.hasLevel {
+		return TRACE
+	}
+	return l.level
+}
+
+// enabledFor indica se algum transporte ou hook aceitaria uma entry do nível
+// informado.
+// Permite que caminhos caros (ex: EntryBuilder) desistam antes de alocar.
+func (l *Logger) enabledFor(level Level) bool {
+	if l == nil {
+		return false
+	}
+	name := l.name
+	l = l.core()
+	if l.nop {
+		return false
+	}
+	if int64(level) < l.minLevelOf() {
+		return false
+	}
+	if l.hasNameLevels.Load() && !l.nameAllows(name, level) {
+		return false
+	}
+	return !l.hasPkgLevels.Load() || l.packageAllows(level)
+}
+
+// IsLevelEnabled informa se uma entry do nível seria registrada, considerando
+// o nível dos transportes (hooks aceitam todos os níveis), SetLevel e os níveis
+// por nome e por pacote. Use-o para evitar preparar dados caros que seriam
+// descartados:
+//
+//	if logger.IsLevelEnabled(lazylog.DEBUG) {
+//	    logger.ComFields(map[string]any{"dump": expensiveDump()}).Debug("state")
+//	}
+func (l *Logger) IsLevelEnabled(level Level) bool {
+	return l.enabledFor(level)
 }
 
 // ComFields permite adicionar metadata/contexto extra ao log.
 func (l *Logger) ComFields(fields map[string]interface{}) *EntryBuilder {
+	if l.core().nop {
+		return nopBuilder
+	}
 	return &EntryBuilder{logger: l, fields: fields}
 }
 
-// EntryBuilder permite construir logs com metadata extra e formatter customizado.
+// EntryBuilder permite construir logs com metadata extra e formatter
+// customizado. Os métodos de configuração retornam um novo builder e podem
+// ser encadeados numa única entry:
+//
+//	logger.ComFields(fields).WithFormatter(&lazylog.JSONFormatter{}).WithError(err).Warn("retry")
 type EntryBuilder struct {
 	logger    *Logger
 	fields    map[string]interface{}
 	formatter Formatter
+	disabled  bool // se true, os métodos terminais não registram nada
+	// claim, se não nil, é consultado pelos métodos terminais depois do
+	// filtro de nível; retornando false a entry é descartada (Once).
+	claim func() bool
+}
+
+// allows indica se os métodos terminais devem registrar uma entry do nível.
+func (b *EntryBuilder) allows(level Level) bool {
+	if b.disabled || !b.logger.enabledFor(level) {
+		return false
+	}
+	return b.claim == nil || b.claim()
+}
+
+// WithField retorna um novo builder com o campo adicionado (o builder e o
+// mapa originais não são alterados).
+func (b *EntryBuilder) WithField(key string, value any) *EntryBuilder {
+	if b.disabled {
+		return b
+	}
+	nb := *b
+	nb.fields = withField(b.fields, key, value)
+	return &nb
 }
 
 // WithFormatter permite sobrescrever o formatter para este log.
 func (l *Logger) WithFormatter(formatter Formatter) *EntryBuilder {
+	if l.core().nop {
+		return nopBuilder
+	}
 	return &EntryBuilder{logger: l, formatter: formatter}
 }
 
+// ComFields retorna um novo builder com os campos adicionados (campos
+// repetidos substituem os anteriores; o builder e os mapas originais não são
+// alterados).
+func (b *EntryBuilder) ComFields(fields map[string]interface{}) *EntryBuilder {
+	if b.disabled {
+		return b
+	}
+	nb := *b
+	nb.fields = mergeBoundFields(b.fields, fields)
+	return &nb
+}
+
+// WithFormatter retorna um novo builder que usa o formatter informado.
+func (b *EntryBuilder) WithFormatter(formatter Formatter) *EntryBuilder {
+	if b.disabled {
+		return b
+	}
+	nb := *b
+	nb.formatter = formatter
+	return &nb
+}
+
+func (b *EntryBuilder) Trace(msg string) {
+	if !b.allows(TRACE) {
+		return
+	}
+	b.logger.logWithFieldsCustomFormatter(TRACE, msg, b.fields, b.formatter)
+}
 func (b *EntryBuilder) Debug(msg string) {
+	if !b.allows(DEBUG) {
+		return
+	}
 	b.logger.logWithFieldsCustomFormatter(DEBUG, msg, b.fields, b.formatter)
 }
 func (b *EntryBuilder) Info(msg string) {
+	if !b.allows(INFO) {
+		return
+	}
 	b.logger.logWithFieldsCustomFormatter(INFO, msg, b.fields, b.formatter)
 }
 func (b *EntryBuilder) Warn(msg string) {
+	if !b.allows(WARN) {
+		return
+	}
 	b.logger.logWithFieldsCustomFormatter(WARN, msg, b.fields, b.formatter)
 }
 func (b *EntryBuilder) Error(msg string) {
+	if !b.allows(ERROR) {
+		return
+	}
 	b.logger.logWithFieldsCustomFormatter(ERROR, msg, b.fields, b.formatter)
 }
 
 // logWithFieldsCustomFormatter permite sobrescrever o formatter por mensagem (thread-safe).
 func (l *Logger) logWithFieldsCustomFormatter(level Level, message string, fields map[string]interface{}, formatter Formatter) {
+	if !l.enabledFor(level) {
+		return
+	}
 	snap := l.snapshot()
 	entry := Entry{
 		Level:     level,
@@ -310,37 +851,96 @@ func (l *Logger) logWithFieldsCustomFormatter(level Level, message string, field
 	dispatchEntry(snap, &entry, formatter)
 }
 
-// LoggerConfig permite inicializar o logger de forma dinâmica.
+// LoggerConfig permite inicializar o logger de forma dinâmica. Profiles e
+// valores com ${VAR} são resolvidos por Resolve.
 type LoggerConfig struct {
-	Transports []TransportConfig
+	Transports    []TransportConfig        `yaml:"Transports"`
+	PackageLevels string                   `yaml:"PackageLevels"` // ex: "...=INFO,github.com/acme/app/db=DEBUG"
+	Profiles      map[string]ProfileConfig `yaml:"Profiles"`      // perfis nomeados (ver ProfileConfig)
+	Profile       string                   `yaml:"Profile"`       // perfil padrão; ConfigProfileEnv tem precedência
+
+	resolved bool // já passou por Resolve
 }
 
 type TransportConfig struct {
-	Type      string         // "console", "file", etc
-	Level     string         // "INFO", "DEBUG", ...
-	Formatter string         // "text", "json"
-	Options   map[string]any // opções específicas (ex: path para arquivo)
+	Name      string            `yaml:"Name"`      // identifica o transporte para os perfis (opcional)
+	Type      string            `yaml:"Type"`      // "console", "file", "http", etc
+	Level     string            `yaml:"Level"`     // "INFO", "DEBUG", ...
+	MaxLevel  string            `yaml:"MaxLevel"`  // nível máximo aceito (opcional)
+	Filter    string            `yaml:"Filter"`    // expressão de filtro (ver ParseFilterExpr)
+	Schedule  string            `yaml:"Schedule"`  // janelas de atividade (ver ParseSchedule)
+	Timezone  string            `yaml:"Timezone"`  // fuso de Schedule (ex: "America/Sao_Paulo")
+	Formatter string            `yaml:"Formatter"` // "text", "json", "logfmt"
+	Options   map[string]any    `yaml:"Options"`   // opções específicas (ex: path para arquivo)
+	TLS       *TLSConfigOptions `yaml:"TLS"`       // opções de TLS para transportes HTTP
+	Network   *NetworkOptions   `yaml:"Network"`   // proxy/dialer para transportes HTTP
+	Auth      *AuthConfig       `yaml:"Auth"`      // autenticação para transportes HTTP
+	CostGuard *CostGuardConfig  `yaml:"CostGuard"` // orçamento diário (ver CostGuardTransport)
 }
 
 // NewLoggerFromConfig cria um Logger a partir de uma configuração dinâmica.
 func NewLoggerFromConfig(cfg LoggerConfig) (*Logger, error) {
-	logger := &Logger{}
+	cfg, err := cfg.Resolve("")
+	if err != nil {
+		return nil, err
+	}
+	transports, err := buildTransports(cfg)
+	if err != nil {
+		return nil, err
+	}
+	logger := NewLogger(transports...)
+	if cfg.PackageLevels != "" {
+		if err := logger.SetPackageLevels(cfg.PackageLevels); err != nil {
+			return nil, err
+		}
+	}
+	return logger, nil
+}
+
+// buildTransports cria os transportes descritos na configuração. Em caso de
+// erro, os transportes já criados são fechados.
+func buildTransports(cfg LoggerConfig) (_ []Transport, err error) {
+	var transports []Transport
+	defer func() {
+		if err != nil {
+			for _, t := range transports {
+				closeTransport(t)
+			}
+		}
+	}()
 	for _, tcfg := range cfg.Transports {
 		var formatter Formatter
 		switch tcfg.Formatter {
 		case "json":
 			formatter = &JSONFormatter{}
+		case "logfmt":
+			formatter = &LogfmtFormatter{}
 		default:
-			formatter = &TextFormatter{}
+			tf := &TextFormatter{}
+			if locale, ok := tcfg.Options["locale"].(string); ok {
+				tf.Labels = LocaleLabels(locale)
+			}
+			formatter = tf
 		}
 		level := ParseLevel(tcfg.Level)
+		filter, err := transportConfigFilter(tcfg)
+		if err != nil {
+			return nil, err
+		}
+		var schedule *Schedule
+		if tcfg.Schedule != "" {
+			if schedule, err = ParseSchedule(tcfg.Schedule, tcfg.Timezone); err != nil {
+				return nil, err
+			}
+		}
+		idx := len(transports)
 		switch tcfg.Type {
 		case "console":
 			toStdErr := false
 			if v, ok := tcfg.Options["stderr"].(bool); ok {
 				toStdErr = v
 			}
-			logger.AddTransport(&ConsoleTransport{
+			transports = append(transports, &ConsoleTransport{
 				Level:     level,
 				Formatter: formatter,
 				ToStdErr:  toStdErr,
@@ -351,12 +951,86 @@ func NewLoggerFromConfig(cfg LoggerConfig) (*Logger, error) {
 			if err != nil {
 				return nil, err
 			}
-			logger.AddTransport(ft)
+			transports = append(transports, ft)
+		case "http":
+			url, _ := tcfg.Options["url"].(string)
+			ht, err := NewHTTPTransport(url, level, formatter, tcfg.TLS)
+			if err != nil {
+				return nil, err
+			}
+			if tcfg.Network != nil {
+				if ht.Client, err = NewHTTPClient(tcfg.TLS, tcfg.Network); err != nil {
+					return nil, err
+				}
+			}
+			ht.Compression, _ = tcfg.Options["compression"].(string)
+			switch n := tcfg.Options["max_in_flight"].(type) {
+			case int:
+				ht.MaxInFlight = n
+			case float64: // JSON
+				ht.MaxInFlight = int(n)
+			}
+			if tcfg.Auth != nil {
+				if ht.Auth, err = tcfg.Auth.Provider(); err != nil {
+					return nil, err
+				}
+			}
+			if headers, ok := tcfg.Options["headers"].(map[string]any); ok {
+				ht.Headers = make(map[string]string, len(headers))
+				for k, v := range headers {
+					ht.Headers[k] = fmt.Sprint(v)
+				}
+			}
+			transports = append(transports, ht)
 		default:
 			return nil, fmt.Errorf("lazylog: unknown transport type %q", tcfg.Type)
 		}
+		if tcfg.CostGuard != nil {
+			name := tcfg.Name
+			if name == "" {
+				name = tcfg.Type
+			}
+			guard, err := NewCostGuardTransport(transports[idx], name, *tcfg.CostGuard)
+			if err != nil {
+				return nil, err
+			}
+			transports[idx] = guard
+		}
+		if filter != nil {
+			transports[idx] = &TransportWithFilter{Transport: transports[idx], Filter: filter}
+		}
+		if schedule != nil {
+			transports[idx] = &ScheduledTransport{Transport: transports[idx], Schedule: schedule}
+		}
 	}
-	return logger, nil
+	return transports, nil
+}
+
+// transportConfigFilter combina MaxLevel e Filter de um TransportConfig num
+// único FilterFunc (nil quando nenhum dos dois foi informado).
+func transportConfigFilter(tcfg TransportConfig) (FilterFunc, error) {
+	var filters []FilterFunc
+	if tcfg.MaxLevel != "" {
+		limit, ok := lookupLevel(tcfg.MaxLevel)
+		if !ok {
+			return nil, fmt.Errorf("lazylog: unknown MaxLevel %q", tcfg.MaxLevel)
+		}
+		filters = append(filters, func(e *Entry) bool { return e.Level <= limit })
+	}
+	if tcfg.Filter != "" {
+		f, err := ParseFilterExpr(tcfg.Filter)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	switch len(filters) {
+	case 0:
+		return nil, nil
+	case 1:
+		return filters[0], nil
+	}
+	return func(e *Entry) bool { return filters[0](e) && filters[1](e) }, nil
 }
 
 // LoadLoggerConfigJSON carrega configuração do logger de um arquivo JSON.
@@ -383,20 +1057,24 @@ func LoadLoggerConfigYAML(path string) (LoggerConfig, error) {
 
 // logWithContext permite logar com context.Context, extraindo informações relevantes.
 func (l *Logger) logWithContext(ctx context.Context, level Level, message string, fields map[string]interface{}) {
+	if !l.enabledFor(level) {
+		return
+	}
 	snap := l.snapshot()
 	entry := Entry{
 		Level:     level,
 		Timestamp: time.Now(),
 		Message:   message,
 		Fields:    fields,
+		ctx:       ctx,
+	}
+	if cf := ctxFields(ctx); len(cf) > 0 {
+		entry.Fields = mergeBoundFields(cf, entry.Fields)
 	}
 	// Suporte a context key customizada e string
 	for _, key := range []any{CtxKey("trace_id"), "trace_id"} {
 		if v := ctx.Value(key); v != nil {
-			if entry.Fields == nil {
-				entry.Fields = make(map[string]interface{})
-			}
-			entry.Fields["trace_id"] = v
+			entry.Fields = withField(entry.Fields, "trace_id", v)
 			break
 		}
 	}
@@ -407,6 +1085,9 @@ func (l *Logger) logWithContext(ctx context.Context, level Level, message string
 func (l *Logger) InfoCtx(ctx context.Context, msg string, fields map[string]interface{}) {
 	l.logWithContext(ctx, INFO, msg, fields)
 }
+func (l *Logger) TraceCtx(ctx context.Context, msg string, fields map[string]interface{}) {
+	l.logWithContext(ctx, TRACE, msg, fields)
+}
 func (l *Logger) DebugCtx(ctx context.Context, msg string, fields map[string]interface{}) {
 	l.logWithContext(ctx, DEBUG, msg, fields)
 }
@@ -420,38 +1101,41 @@ func (l *Logger) ErrorCtx(ctx context.Context, msg string, fields map[string]int
 // CtxKey é o tipo exportado para chaves de contexto do lazylog.
 type CtxKey string
 
-// ChildLogger permite criar um logger derivado com campos fixos (contexto).
-type ChildLogger struct {
-	parent *Logger
-	fields map[string]any
+// WithFields retorna um logger derivado que inclui os campos informados em
+// toda entry (campos da própria entry têm precedência). O derivado compartilha
+// transportes, hooks e configuração com o pai: métodos como AddTransport ou
+// AddHook chamados nele afetam o pai. Derivar de um derivado acumula os campos.
+func (l *Logger) WithFields(fields map[string]any) *Logger {
+	if c := l.core(); c.nop {
+		return c
+	}
+	bound := make(map[string]any, len(l.bound)+len(fields))
+	for k, v := range l.bound {
+		bound[k] = v
+	}
+	for k, v := range fields {
+		bound[k] = v
+	}
+	return &Logger{parent: l.core(), bound: bound, name: l.name}
 }
 
-// WithFields retorna um logger derivado com contexto fixo.
-func (l *Logger) WithFields(fields map[string]any) *ChildLogger {
-	return &ChildLogger{parent: l, fields: fields}
+// WithField é como WithFields para um único campo, sem exigir um mapa literal.
+func (l *Logger) WithField(key string, value any) *Logger {
+	if c := l.core(); c.nop {
+		return c
+	}
+	return &Logger{parent: l.core(), bound: withField(l.bound, key, value), name: l.name}
 }
 
-func (c *ChildLogger) Debug(msg string, fields ...map[string]any) {
-	c.logWithMergedFields(DEBUG, msg, fields...)
-}
-func (c *ChildLogger) Info(msg string, fields ...map[string]any) {
-	c.logWithMergedFields(INFO, msg, fields...)
-}
-func (c *ChildLogger) Warn(msg string, fields ...map[string]any) {
-	c.logWithMergedFields(WARN, msg, fields...)
-}
-func (c *ChildLogger) Error(msg string, fields ...map[string]any) {
-	c.logWithMergedFields(ERROR, msg, fields...)
-}
-func (c *ChildLogger) logWithMergedFields(level Level, msg string, fields ...map[string]any) {
-	merged := make(map[string]any)
-	for k, v := range c.fields {
+// mergeBoundFields retorna um novo mapa com os campos vinculados e, por cima,
+// os campos da entry (o mapa do chamador não é alterado).
+func mergeBoundFields(bound, fields map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(bound)+len(fields))
+	for k, v := range bound {
 		merged[k] = v
 	}
-	if len(fields) > 0 {
-		for k, v := range fields[0] {
-			merged[k] = v
-		}
+	for k, v := range fields {
+		merged[k] = v
 	}
-	c.parent.logWithFields(level, msg, merged)
+	return merged
 }
@@ -41,6 +41,36 @@ func BenchmarkLogger_JSONFields(b *testing.B) {
 	}
 }
 
+func BenchmarkLogger_JSONTypedFields(b *testing.B) {
+	tr := &lazylog.WriterTransport{
+		Writer:    &bytes.Buffer{},
+		Level:     lazylog.INFO,
+		Formatter: &lazylog.JSONFormatter{},
+	}
+	logger := lazylog.NewLogger(tr)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.InfoFields("mensagem estruturada", lazylog.String("user", "cesar"), lazylog.Int("attempt", i))
+	}
+}
+
+// BenchmarkBatchTransport mede a vazão do pipeline com serialização direta
+// em lotes (entries/s), sem o custo de um destino real.
+func BenchmarkBatchTransport(b *testing.B) {
+	discard := lazylog.BatchWriterFunc(func([]byte, int) error { return nil })
+	logger := lazylog.NewLogger(lazylog.NewBatchTransport(discard, lazylog.INFO, 0, 0))
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			logger.InfoFields("evento", lazylog.String("user", "cesar"), lazylog.Int("seq", i))
+			i++
+		}
+	})
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "entries/s")
+}
+
 func BenchmarkLogger_Parallel(b *testing.B) {
 	tr := &lazylog.WriterTransport{
 		Writer:    io.Discard,
@@ -87,3 +117,136 @@ func BenchmarkLogger_WithFormatter(b *testing.B) {
 		logger.WithFormatter(formatter).Info("mensagem customizada")
 	}
 }
+
+// --- Suíte estruturada: formatters, decorators, campos e paralelismo ---
+//
+// Para comparar com benchstat:
+//
+//	go test -run='^$' -bench=Suite -benchmem -count=10 . > new.txt
+//	benchstat old.txt new.txt
+
+var benchFormatters = []struct {
+	name      string
+	formatter lazylog.Formatter
+}{
+	{"text", &lazylog.TextFormatter{}},
+	{"json", &lazylog.JSONFormatter{}},
+	{"emoji", &lazylog.EmojiFormatter{Base: &lazylog.TextFormatter{}}},
+}
+
+var benchFields = map[string]any{
+	"user":       "cesar",
+	"request_id": 12345,
+	"latency":    1.25,
+}
+
+func BenchmarkSuite_Formatters(b *testing.B) {
+	entry := &lazylog.Entry{Level: lazylog.INFO, Timestamp: time.Now(), Message: "mensagem", Fields: benchFields}
+	for _, bf := range benchFormatters {
+		b.Run(bf.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = bf.formatter.Format(entry)
+			}
+		})
+	}
+}
+
+func BenchmarkSuite_Logger(b *testing.B) {
+	for _, bf := range benchFormatters {
+		b.Run(bf.name+"/no_fields", func(b *testing.B) {
+			logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: io.Discard, Level: lazylog.INFO, Formatter: bf.formatter})
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				logger.Info("mensagem")
+			}
+		})
+		b.Run(bf.name+"/fields", func(b *testing.B) {
+			logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: io.Discard, Level: lazylog.INFO, Formatter: bf.formatter})
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				logger.ComFields(benchFields).Info("mensagem")
+			}
+		})
+		b.Run(bf.name+"/parallel", func(b *testing.B) {
+			logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: io.Discard, Level: lazylog.INFO, Formatter: bf.formatter})
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					logger.ComFields(benchFields).Info("mensagem")
+				}
+			})
+		})
+	}
+	b.Run("disabled_level", func(b *testing.B) {
+		logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: io.Discard, Level: lazylog.ERROR})
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			logger.Debug("mensagem descartada")
+		}
+	})
+}
+
+func BenchmarkSuite_Decorators(b *testing.B) {
+	base := func() lazylog.Transport {
+		return &lazylog.WriterTransport{Writer: io.Discard, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}}
+	}
+	decorators := []struct {
+		name string
+		wrap func(lazylog.Transport) lazylog.Transport
+	}{
+		{"plain", func(t lazylog.Transport) lazylog.Transport { return t }},
+		{"filter", func(t lazylog.Transport) lazylog.Transport {
+			return &lazylog.TransportWithFilter{Transport: t, Filter: func(*lazylog.Entry) bool { return true }}
+		}},
+		{"tracing_unsampled", func(t lazylog.Transport) lazylog.Transport {
+			return &lazylog.TracingTransport{Transport: t, SampleRate: 1e-9}
+		}},
+		{"async", func(t lazylog.Transport) lazylog.Transport {
+			return lazylog.NewAsyncTransport(t, 4096, lazylog.OverflowBlock)
+		}},
+	}
+	for _, d := range decorators {
+		b.Run(d.name, func(b *testing.B) {
+			logger := lazylog.NewLogger(d.wrap(base()))
+			defer logger.Close()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				logger.ComFields(benchFields).Info("mensagem")
+			}
+		})
+	}
+}
+
+// allocBudgets define o máximo de alocações por chamada nos caminhos quentes.
+// Se um destes testes falhar, a mudança introduziu alocações no hot path:
+// otimize ou ajuste o orçamento conscientemente.
+var allocBudgets = []struct {
+	name   string
+	budget float64
+	run    func(*lazylog.Logger)
+}{
+	{"info_text", 4, func(l *lazylog.Logger) { l.Info("mensagem") }},
+	{"fields_text", 5, func(l *lazylog.Logger) { l.ComFields(benchFields).Info("mensagem") }},
+	{"disabled_debug", 0, func(l *lazylog.Logger) { l.Debug("descartada") }},
+	{"disabled_builder", 0, func(l *lazylog.Logger) { l.ComFields(benchFields).Debug("descartada") }},
+	{"typed_fields_text", 3, func(l *lazylog.Logger) {
+		l.InfoFields("mensagem", lazylog.String("user", "cesar"), lazylog.Int("attempt", 2))
+	}},
+	{"disabled_typed_fields", 0, func(l *lazylog.Logger) {
+		l.DebugFields("descartada", lazylog.String("user", "cesar"), lazylog.Int("attempt", 2))
+	}},
+}
+
+func TestAllocBudgets(t *testing.T) {
+	if testing.Short() || raceEnabled {
+		t.Skip("alloc budgets skipped in -short mode and under the race detector")
+	}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: io.Discard, Level: lazylog.INFO, Formatter: &lazylog.TextFormatter{}})
+	for _, ab := range allocBudgets {
+		got := testing.AllocsPerRun(100, func() { ab.run(logger) })
+		if got > ab.budget {
+			t.Errorf("%s: %.0f allocs/op exceeds budget of %.0f", ab.name, got, ab.budget)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package lazylog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chmenegatti/lazylog"
+)
+
+func fuzzEntry(msg, key, val string, num float64) *lazylog.Entry {
+	return &lazylog.Entry{
+		Level:     lazylog.WARN,
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Message:   msg,
+		Fields: map[string]interface{}{
+			key:      val,
+			"num":    num,
+			"nested": map[string]interface{}{key: val},
+		},
+	}
+}
+
+func addFuzzSeeds(f *testing.F) {
+	f.Add("hello", "user", "cesar", 1.5)
+	f.Add("multi\nline\r\n", "level", "ERROR", math.Inf(1))
+	f.Add("\xff\xfe invalid utf8", "\x00key", "\x1b[31mred\x1b[0m", math.NaN())
+	f.Add("x", "", "", -0.0)
+	f.Add(`{"json":"in message"}`, "message", `"quoted"`, math.MaxFloat64)
+}
+
+// TestFormattersLargeInputs cobre entradas grandes fora do corpus dos fuzzers,
+// onde seeds de dezenas de KiB tornam cada iteração (e a minimização) lenta.
+func TestFormattersLargeInputs(t *testing.T) {
+	big := strings.Repeat("x", 1<<16)
+	entry := fuzzEntry(big, big, big, -0.0)
+	text, err := (&lazylog.TextFormatter{}).Format(entry)
+	if err != nil || !bytes.HasSuffix(text, []byte("\n")) || !bytes.Contains(text, []byte(big)) {
+		t.Fatalf("text formatter failed on large input (err=%v, %d bytes)", err, len(text))
+	}
+	out, err := (&lazylog.JSONFormatter{}).Format(entry)
+	if err != nil {
+		t.Fatalf("json formatter returned error: %v", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(out, &m); err != nil {
+		t.Fatalf("invalid json output for large input: %v", err)
+	}
+	if m["message"] != big || m[big] != big {
+		t.Errorf("large message or field not preserved")
+	}
+}
+
+func FuzzTextFormatter(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, msg, key, val string, num float64) {
+		out, err := (&lazylog.TextFormatter{}).Format(fuzzEntry(msg, key, val, num))
+		if err != nil {
+			t.Fatalf("text formatter returned error: %v", err)
+		}
+		if !bytes.HasPrefix(out, []byte("2024-01-02T03:04:05Z [WARN] ")) || !bytes.HasSuffix(out, []byte("\n")) {
+			t.Fatalf("malformed text output: %q", out)
+		}
+	})
+}
+
+func FuzzJSONFormatter(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, msg, key, val string, num float64) {
+		out, err := (&lazylog.JSONFormatter{}).Format(fuzzEntry(msg, key, val, num))
+		if err != nil {
+			t.Fatalf("json formatter returned error: %v", err)
+		}
+		if bytes.Count(out, []byte("\n")) != 1 || !bytes.HasSuffix(out, []byte("\n")) {
+			t.Fatalf("json output must be exactly one line: %q", out)
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(out, &m); err != nil {
+			t.Fatalf("invalid json output %q: %v", out, err)
+		}
+		if m["level"] != "WARN" {
+			t.Fatalf("user fields overrode the level: %v", m["level"])
+		}
+		if utf8.ValidString(msg) && m["message"] != msg {
+			t.Fatalf("message not preserved: %q != %q", m["message"], msg)
+		}
+		if (math.IsNaN(num) || math.IsInf(num, 0)) && m["_encode_error"] == nil {
+			t.Fatalf("unencodable float not flagged: %v", m)
+		}
+	})
+}
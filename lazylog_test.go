@@ -1,10 +1,32 @@
 package lazylog_test
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -64,6 +86,23 @@ func TestComFieldsAndHooks(t *testing.T) {
 	}
 }
 
+func TestHooksRunWithoutAcceptingTransport(t *testing.T) {
+	var levels []lazylog.Level
+	logger := lazylog.NewLogger()
+	logger.AddHook(func(e *lazylog.Entry) { levels = append(levels, e.Level) }, false)
+	logger.Debug("hook only")
+	if len(levels) != 1 || levels[0] != lazylog.DEBUG {
+		t.Fatalf("hook-only logger must fire hooks: %v", levels)
+	}
+
+	buf := &bytes.Buffer{}
+	logger.AddTransport(&lazylog.WriterTransport{Writer: buf, Level: lazylog.WARN, Formatter: &lazylog.TextFormatter{}})
+	logger.Info("below transport")
+	if len(levels) != 2 || buf.Len() != 0 {
+		t.Errorf("expected hook without transport write (hooks=%v): %s", levels, buf.String())
+	}
+}
+
 func TestCustomLevel(t *testing.T) {
 	lazylog.RegisterLevel("NOTICE", 10)
 	if lazylog.ParseLevel("NOTICE") != 10 {
@@ -148,3 +187,3473 @@ func TestNestedFieldsJSON(t *testing.T) {
 		t.Errorf("nested fields not serialized: %v", m)
 	}
 }
+
+func TestFieldEncoders(t *testing.T) {
+	t.Cleanup(lazylog.ResetFieldEncoders)
+	lazylog.RegisterFieldEncoder(lazylog.DurationAsMillis)
+	lazylog.RegisterFieldEncoder(lazylog.BytesAsHex)
+	lazylog.RegisterFieldEncoder(lazylog.ErrorAsString)
+
+	buf := &bytes.Buffer{}
+	tr := &lazylog.WriterTransport{
+		Writer:    buf,
+		Level:     lazylog.INFO,
+		Formatter: &lazylog.JSONFormatter{},
+	}
+	logger := lazylog.NewLogger(tr)
+	fields := map[string]interface{}{
+		"elapsed": 1500 * time.Millisecond,
+		"payload": []byte{0xca, 0xfe},
+		"err":     context.Canceled,
+		"nested":  map[string]interface{}{"took": 2 * time.Millisecond},
+	}
+	logger.ComFields(fields).Info("encoded")
+	var m map[string]interface{}
+	_ = json.Unmarshal(buf.Bytes(), &m)
+	if m["elapsed"] != float64(1500) || m["payload"] != "cafe" || m["err"] != "context canceled" {
+		t.Errorf("field encoders not applied: %v", m)
+	}
+	if nested, ok := m["nested"].(map[string]interface{}); !ok || nested["took"] != float64(2) {
+		t.Errorf("nested field encoder not applied: %v", m)
+	}
+	if _, ok := fields["elapsed"].(time.Duration); !ok {
+		t.Errorf("caller fields map was mutated: %v", fields)
+	}
+}
+
+func TestCanonicalMiddleware(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tr := &lazylog.WriterTransport{
+		Writer:    buf,
+		Level:     lazylog.INFO,
+		Formatter: &lazylog.JSONFormatter{},
+	}
+	logger := lazylog.NewLogger(tr)
+	h := logger.CanonicalMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ce := lazylog.CanonicalEntryFromContext(r.Context())
+		ce.Incr("db_calls", 1)
+		ce.Incr("db_calls", 2)
+		ce.Set("user", "cesar")
+		_, _ = w.Write([]byte("hello"))
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+	var m map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("expected a single canonical entry: %v (%s)", err, buf.String())
+	}
+	if m["db_calls"] != float64(3) || m["user"] != "cesar" || m["status"] != float64(200) ||
+		m["bytes_out"] != float64(5) || m["path"] != "/users" {
+		t.Errorf("canonical entry missing fields: %v", m)
+	}
+	if _, ok := m["duration_ms"]; !ok {
+		t.Errorf("canonical entry missing duration_ms: %v", m)
+	}
+}
+
+func TestCanonicalMiddlewarePanic(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tr := &lazylog.WriterTransport{
+		Writer:    buf,
+		Level:     lazylog.INFO,
+		Formatter: &lazylog.JSONFormatter{},
+	}
+	logger := lazylog.NewLogger(tr)
+	h := logger.CanonicalMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(errors.New("boom"))
+	}))
+	func() {
+		defer func() {
+			if v := recover(); v == nil {
+				t.Errorf("panic must be re-raised")
+			}
+		}()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/crash", nil))
+	}()
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("expected a single canonical entry: %v (%s)", err, buf.String())
+	}
+	p, _ := m["panic"].(map[string]any)
+	if m["status"] != float64(500) || m["level"] != "ERROR" || p["value"] != "boom" || p["type"] != "*errors.errorString" {
+		t.Errorf("unexpected canonical entry for panic: %v", m)
+	}
+}
+
+func TestCanonicalMiddlewareForwardsWriterInterfaces(t *testing.T) {
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: io.Discard, Level: lazylog.INFO})
+	var flushed, hijackOK atomic.Bool
+	srv := httptest.NewServer(logger.CanonicalMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ws" {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				hijackOK.Store(true)
+				conn.Close()
+			}
+			return
+		}
+		_, _ = w.Write([]byte("data"))
+		w.(http.Flusher).Flush()
+		flushed.Store(true)
+	})))
+	defer srv.Close()
+	if resp, err := http.Get(srv.URL); err == nil {
+		resp.Body.Close()
+	}
+	_, _ = http.Get(srv.URL + "/ws")
+	if !flushed.Load() || !hijackOK.Load() {
+		t.Errorf("expected Flusher and Hijacker to be forwarded: flushed=%v hijack=%v", flushed.Load(), hijackOK.Load())
+	}
+
+	// Sem suporte no writer original, Hijack retorna http.ErrNotSupported.
+	h := logger.CanonicalMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, err := w.(http.Hijacker).Hijack(); !errors.Is(err, http.ErrNotSupported) {
+			t.Errorf("expected ErrNotSupported, got %v", err)
+		}
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestCorrelationPropagation(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tr := &lazylog.WriterTransport{
+		Writer:    buf,
+		Level:     lazylog.INFO,
+		Formatter: &lazylog.JSONFormatter{},
+	}
+	logger := lazylog.NewLogger(tr)
+
+	// Serviço chamado: o middleware vincula os campos recebidos ao logger.
+	downstream := httptest.NewServer(logger.CorrelationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lazylog.LoggerFromContext(r.Context()).Info("downstream")
+	})))
+	defer downstream.Close()
+
+	reqLogger := logger.WithFields(map[string]any{"request_id": "abc 1,2", "tenant_id": "acme", "user_id": 42})
+	client := &http.Client{Transport: &lazylog.CorrelationTransport{}}
+	req, _ := http.NewRequestWithContext(lazylog.ContextWithLogger(context.Background(), reqLogger), http.MethodGet, downstream.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if req.Header.Get(lazylog.CorrelationHeader) != "" {
+		t.Errorf("the original request must not be modified")
+	}
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("expected a single entry: %v (%s)", err, buf.String())
+	}
+	if m["request_id"] != "abc 1,2" || m["tenant_id"] != "acme" {
+		t.Errorf("correlation fields not propagated: %v", m)
+	}
+	if _, ok := m["user_id"]; ok {
+		t.Errorf("non-correlation field must not be propagated: %v", m)
+	}
+
+	// Apenas as chaves configuradas são aceitas de headers externos.
+	logger.SetCorrelationKeys("trace_tag")
+	h := http.Header{}
+	h.Set(lazylog.CorrelationHeader, "request_id=forged,trace_tag=x1,broken")
+	if got := logger.ExtractCorrelation(h); len(got) != 1 || got["trace_tag"] != "x1" {
+		t.Errorf("unexpected extracted fields: %v", got)
+	}
+
+	buf.Reset()
+	ch := logger.CanonicalMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header = h
+	ch.ServeHTTP(httptest.NewRecorder(), r)
+	if !strings.Contains(buf.String(), `"trace_tag":"x1"`) {
+		t.Errorf("canonical entry missing correlation field: %s", buf.String())
+	}
+
+	var nilLogger *lazylog.Logger
+	nilLogger.InjectHeaders(h)
+	if nilLogger.CorrelationFields() != nil {
+		t.Errorf("nil logger must have no correlation fields")
+	}
+}
+
+func TestAppendCtxFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tr := &lazylog.WriterTransport{
+		Writer:    buf,
+		Level:     lazylog.INFO,
+		Formatter: &lazylog.JSONFormatter{},
+	}
+	logger := lazylog.NewLogger(tr).WithFields(map[string]any{"service": "api", "order_id": "bound"})
+
+	ctx := lazylog.AppendCtxFields(context.Background(), map[string]any{"order_id": "o-1", "step": "start"})
+	inner := lazylog.AppendCtxFields(ctx, map[string]any{"step": "charge"})
+	logger.InfoCtx(inner, "payment captured", map[string]any{"amount": 10})
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["service"] != "api" || m["order_id"] != "o-1" || m["step"] != "charge" || m["amount"] != float64(10) {
+		t.Errorf("unexpected merged fields: %v", m)
+	}
+	if lazylog.CtxFields(ctx)["step"] != "start" {
+		t.Errorf("parent context must not see fields appended later: %v", lazylog.CtxFields(ctx))
+	}
+
+	buf.Reset()
+	logger.InfoCtx(inner, "override", map[string]any{"step": "explicit"})
+	if !strings.Contains(buf.String(), `"step":"explicit"`) {
+		t.Errorf("explicit fields must win over context fields: %s", buf.String())
+	}
+
+	buf.Reset()
+	lazylog.LoggerFromContext(lazylog.ContextWithLogger(inner, logger)).Info("from context")
+	if !strings.Contains(buf.String(), `"step":"charge"`) || !strings.Contains(buf.String(), `"service":"api"`) {
+		t.Errorf("logger from context must include context fields: %s", buf.String())
+	}
+}
+
+func TestTraceContextFromHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.Add("tracestate", "congo=t61rcWkgMzE")
+	h.Add("tracestate", "rojo=00f067aa0ba902b7")
+	tc, ok := lazylog.TraceContextFromHeaders(h)
+	if !ok || tc.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || tc.SpanID != "00f067aa0ba902b7" ||
+		!tc.Sampled || tc.TraceState != "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7" {
+		t.Errorf("unexpected trace context: %+v (%v)", tc, ok)
+	}
+	for _, bad := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		if _, err := lazylog.ParseTraceparent(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+	if tc, err := lazylog.ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-future"); err != nil || tc.Sampled {
+		t.Errorf("future versions must be accepted: %+v (%v)", tc, err)
+	}
+
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}})
+	handler := logger.CanonicalMiddleware(logger.CorrelationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.InfoCtx(r.Context(), "handler", nil)
+	})))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header = h
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected handler and canonical entries, got:\n%s", buf.String())
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, `"span_id":"00f067aa0ba902b7"`) || !strings.Contains(line, `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"`) {
+			t.Errorf("entry missing trace fields: %s", line)
+		}
+	}
+}
+
+func TestFingerprintHook(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tr := &lazylog.WriterTransport{
+		Writer:    buf,
+		Level:     lazylog.INFO,
+		Formatter: &lazylog.JSONFormatter{},
+	}
+	logger := lazylog.NewLogger(tr)
+	logger.AddHook(lazylog.FingerprintHook(), true)
+	for i := 0; i < 2; i++ {
+		logger.Error("user " + strconv.Itoa(i) + " not found")
+	}
+	logger.Info("no fingerprint")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var first, second, info map[string]interface{}
+	_ = json.Unmarshal([]byte(lines[0]), &first)
+	_ = json.Unmarshal([]byte(lines[1]), &second)
+	_ = json.Unmarshal([]byte(lines[2]), &info)
+	if first["fingerprint"] == nil || first["fingerprint"] != second["fingerprint"] {
+		t.Errorf("expected equal fingerprints: %v / %v", first, second)
+	}
+	if _, ok := info["fingerprint"]; ok {
+		t.Errorf("INFO entry should not carry a fingerprint: %v", info)
+	}
+}
+
+func TestRecoverAndLogPanicFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tr := &lazylog.WriterTransport{
+		Writer:    buf,
+		Level:     lazylog.INFO,
+		Formatter: &lazylog.JSONFormatter{},
+	}
+	logger := lazylog.NewLogger(tr)
+	func() {
+		defer logger.RecoverAndLog()
+		panic(errors.New("boom"))
+	}()
+	var m map[string]interface{}
+	_ = json.Unmarshal(buf.Bytes(), &m)
+	p, ok := m["panic"].(map[string]interface{})
+	if !ok || p["value"] != "boom" || p["type"] != "*errors.errorString" || m["level"] != "PANIC" {
+		t.Fatalf("panic fields not structured: %v", m)
+	}
+	if stack, ok := p["stack"].([]interface{}); !ok || len(stack) == 0 {
+		t.Errorf("panic stack frames missing: %v", p)
+	}
+}
+
+func TestEntryCloneDoesNotAlias(t *testing.T) {
+	orig := &lazylog.Entry{
+		Level:   lazylog.INFO,
+		Message: "orig",
+		Fields:  map[string]interface{}{"a": 1, "nested": map[string]interface{}{"b": 2}},
+	}
+	c := orig.Clone()
+	c.Fields["a"] = 99
+	c.Fields["nested"].(map[string]interface{})["b"] = 99
+	if orig.Fields["a"] != 1 || orig.Fields["nested"].(map[string]interface{})["b"] != 2 {
+		t.Errorf("clone aliases original fields: %v", orig.Fields)
+	}
+
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO})
+	logger.AddHook(func(e *lazylog.Entry) { e.Fields["hooked"] = true }, true)
+	fields := map[string]interface{}{"user": "cesar"}
+	logger.ComFields(fields).Info("hooked")
+	if _, ok := fields["hooked"]; ok {
+		t.Errorf("before hook mutated caller fields: %v", fields)
+	}
+}
+
+type recordingSpan struct {
+	attrs map[string]any
+	ended bool
+}
+
+func (s *recordingSpan) SetAttribute(key string, value any) { s.attrs[key] = value }
+func (s *recordingSpan) RecordError(err error)              { s.attrs["error"] = err }
+func (s *recordingSpan) End()                               { s.ended = true }
+
+type recordingTracer struct{ spans []*recordingSpan }
+
+func (r *recordingTracer) Start(ctx context.Context, name string) (context.Context, lazylog.Span) {
+	s := &recordingSpan{attrs: map[string]any{"name": name}}
+	r.spans = append(r.spans, s)
+	return ctx, s
+}
+
+func TestTracingTransport(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tracer := &recordingTracer{}
+	tr := &lazylog.TracingTransport{
+		Transport:  &lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO},
+		Tracer:     tracer,
+		Name:       "buffer",
+		SampleRate: 1,
+	}
+	logger := lazylog.NewLogger(tr)
+	logger.Info("traced")
+	if len(tracer.spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(tracer.spans))
+	}
+	s := tracer.spans[0]
+	if !s.ended || s.attrs["lazylog.transport"] != "buffer" || s.attrs["lazylog.outcome"] != "ok" ||
+		s.attrs["lazylog.bytes"] != buf.Len() {
+		t.Errorf("unexpected span: %+v", s)
+	}
+}
+
+func TestHTTPTransportTLSFromConfig(t *testing.T) {
+	received := make(chan map[string]interface{}, 1)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var m map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&m)
+		received <- m
+	}))
+	defer srv.Close()
+
+	cfg := lazylog.LoggerConfig{Transports: []lazylog.TransportConfig{{
+		Type:      "http",
+		Level:     "INFO",
+		Formatter: "json",
+		Options:   map[string]any{"url": srv.URL},
+		TLS:       &lazylog.TLSConfigOptions{InsecureSkipVerify: true, MinVersion: "1.2"},
+	}}}
+	logger, err := lazylog.NewLoggerFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.Close()
+	logger.Info("over tls")
+	if m := <-received; m["message"] != "over tls" {
+		t.Errorf("unexpected payload: %v", m)
+	}
+
+	if _, err := (&lazylog.TLSConfigOptions{MinVersion: "9.9"}).TLSConfig(); err == nil {
+		t.Error("expected error for unknown TLS version")
+	}
+}
+
+func TestHTTPClientHostOverrides(t *testing.T) {
+	received := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Host
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	_, port, _ := net.SplitHostPort(u.Host)
+
+	client, err := lazylog.NewHTTPClient(nil, &lazylog.NetworkOptions{
+		HostOverrides: map[string]string{"logs.example.invalid": "127.0.0.1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ht := &lazylog.HTTPTransport{
+		URL:    "http://logs.example.invalid:" + port + "/ingest",
+		Level:  lazylog.INFO,
+		Client: client,
+	}
+	if err := ht.WriteLog(&lazylog.Entry{Level: lazylog.INFO, Message: "via override"}); err != nil {
+		t.Fatal(err)
+	}
+	if host := <-received; !strings.HasPrefix(host, "logs.example.invalid") {
+		t.Errorf("unexpected host header: %s", host)
+	}
+}
+
+func TestHTTPTransportGzip(t *testing.T) {
+	received := make(chan map[string]interface{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("missing content-encoding header")
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("body is not gzip: %v", err)
+			return
+		}
+		var m map[string]interface{}
+		_ = json.NewDecoder(zr).Decode(&m)
+		received <- m
+	}))
+	defer srv.Close()
+
+	ht := &lazylog.HTTPTransport{URL: srv.URL, Level: lazylog.INFO, Compression: "gzip"}
+	if err := ht.WriteLog(&lazylog.Entry{Level: lazylog.INFO, Message: "compressed"}); err != nil {
+		t.Fatal(err)
+	}
+	if m := <-received; m["message"] != "compressed" {
+		t.Errorf("unexpected payload: %v", m)
+	}
+}
+
+func TestHTTPTransportOAuth2(t *testing.T) {
+	var tokenCalls int
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenCalls++
+		if id, secret, ok := r.BasicAuth(); !ok || id != "client" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"tok-1","expires_in":3600}`))
+	}))
+	defer tokenSrv.Close()
+	auths := make(chan string, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auths <- r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+
+	ht := &lazylog.HTTPTransport{
+		URL:   srv.URL,
+		Level: lazylog.INFO,
+		Auth:  lazylog.OAuth2ClientCredentials(tokenSrv.URL, "client", "s3cret", nil, nil),
+	}
+	logger := lazylog.NewLogger(ht)
+	logger.Info("first")
+	logger.Info("second")
+	if a, b := <-auths, <-auths; a != "Bearer tok-1" || b != "Bearer tok-1" {
+		t.Errorf("unexpected authorization headers: %q %q", a, b)
+	}
+	if tokenCalls != 1 {
+		t.Errorf("token should be cached, got %d token calls", tokenCalls)
+	}
+}
+
+func TestHTTPTransportIgnoresEntryCancellation(t *testing.T) {
+	got := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- string(body)
+	}))
+	defer srv.Close()
+
+	ht := &lazylog.HTTPTransport{URL: srv.URL, Level: lazylog.INFO}
+	logger := lazylog.NewLogger(ht)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // ex: o cliente da requisição desconectou
+	logger.ErrorCtx(ctx, "client went away", nil)
+
+	select {
+	case body := <-got:
+		if !strings.Contains(body, "client went away") {
+			t.Errorf("unexpected body: %s", body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("entry logged with a canceled context was not delivered")
+	}
+	if stats := ht.Stats(); stats.Errors != 0 {
+		t.Errorf("unexpected delivery error: %v", stats.LastError)
+	}
+}
+
+// sigV4Example é a configuração do AWS Signature V4 test suite
+// (aws-sig-v4-test-suite).
+func sigV4Example(service string) *lazylog.SigV4Auth {
+	return &lazylog.SigV4Auth{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:          "us-east-1",
+		Service:         service,
+		Now:             func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) },
+	}
+}
+
+// sigV4Expected assina canonicalRequest seguindo a especificação, para
+// verificar a forma canônica montada por SigV4Auth.
+func sigV4Expected(service, canonicalRequest string) string {
+	hmacOf := func(key []byte, data string) []byte {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(data))
+		return h.Sum(nil)
+	}
+	sum := sha256.Sum256([]byte(canonicalRequest))
+	scope := "20150830/us-east-1/" + service + "/aws4_request"
+	key := hmacOf([]byte("AWS4wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"), "20150830")
+	for _, part := range []string{"us-east-1", service, "aws4_request"} {
+		key = hmacOf(key, part)
+	}
+	return hex.EncodeToString(hmacOf(key, "AWS4-HMAC-SHA256\n20150830T123600Z\n"+scope+"\n"+hex.EncodeToString(sum[:])))
+}
+
+func TestSigV4AuthTestSuite(t *testing.T) {
+	unreserved := "-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	cases := []struct {
+		name, method, path, signature string
+	}{
+		{"get-vanilla", "GET", "/", "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{"get-vanilla-empty-query-key", "GET", "/?Param1=value1", "a67d582fa61cc504c4bae71f336f98b97f1ea3c7a6bfe1b6e45aec72011b9aeb"},
+		{"get-vanilla-query-order-key-case", "GET", "/?Param2=value2&Param1=value1", "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500"},
+		{"get-vanilla-query-unreserved", "GET", "/?" + unreserved + "=" + unreserved, "9c3e54bfcdf0b19771a7f523ee5669cdf59bc7cc0884027167c21bb143a40197"},
+		{"get-unreserved", "GET", "/" + unreserved, "07ef7494c76fa4850883e2b006601f940f8a34d404d0cfa977f52a65bbf5f24f"},
+		{"post-vanilla", "POST", "/", "5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b"},
+		{"post-vanilla-query", "POST", "/?Param1=value1", "28038455d6de14eafc1f9222cf5aa6f1a96197d7deb8263271d420d138af7f11"},
+	}
+	auth := sigV4Example("service")
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, "https://example.amazonaws.com"+c.path, nil)
+		req.Host = "example.amazonaws.com"
+		if err := auth.Authenticate(req); err != nil {
+			t.Fatal(err)
+		}
+		want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=" + c.signature
+		if got := req.Header.Get("Authorization"); got != want {
+			t.Errorf("%s: got %s", c.name, got)
+		}
+		if req.Header.Get("X-Amz-Date") != "20150830T123600Z" {
+			t.Errorf("%s: unexpected X-Amz-Date %q", c.name, req.Header.Get("X-Amz-Date"))
+		}
+	}
+
+	// Fora do S3, cada segmento do caminho é codificado duas vezes; no S3,
+	// uma vez só.
+	emptyHash := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	for service, path := range map[string]string{"service": "/example%2520space/", "s3": "/example%20space/"} {
+		req := httptest.NewRequest("GET", "https://example.amazonaws.com/example%20space/", nil)
+		req.Host = "example.amazonaws.com"
+		if err := sigV4Example(service).Authenticate(req); err != nil {
+			t.Fatal(err)
+		}
+		canonical := "GET\n" + path + "\n\nhost:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\nhost;x-amz-date\n" + emptyHash
+		if got := req.Header.Get("Authorization"); !strings.HasSuffix(got, "Signature="+sigV4Expected(service, canonical)) {
+			t.Errorf("%s: canonical path must be %s, got %s", service, path, got)
+		}
+	}
+}
+
+type gatedTransport struct {
+	started chan struct{}
+	release chan struct{}
+	mu      sync.Mutex
+	msgs    []string
+}
+
+func (g *gatedTransport) WriteLog(e *lazylog.Entry) error {
+	g.started <- struct{}{}
+	<-g.release
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.msgs = append(g.msgs, e.Message)
+	return nil
+}
+
+func (g *gatedTransport) MinLevel() lazylog.Level { return lazylog.DEBUG }
+
+func TestAsyncTransportBackpressure(t *testing.T) {
+	gate := &gatedTransport{started: make(chan struct{}, 3), release: make(chan struct{})}
+	async := lazylog.NewAsyncTransport(gate, 1, lazylog.OverflowDrop)
+	logger := lazylog.NewLogger(async)
+	var shed int
+	logger.OnBackpressure(func(e *lazylog.Entry, tr lazylog.Transport) { shed++ })
+
+	logger.Info("in flight")
+	<-gate.started
+	if err := logger.TryLog(lazylog.INFO, "queued", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := logger.TryLog(lazylog.INFO, "dropped", nil); !errors.Is(err, lazylog.ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+	if shed != 1 {
+		t.Errorf("expected backpressure callback once, got %d", shed)
+	}
+	close(gate.release)
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(gate.msgs, ",") != "in flight,queued" {
+		t.Errorf("unexpected writes: %v", gate.msgs)
+	}
+}
+
+func TestEscalation(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tr := &lazylog.WriterTransport{
+		Writer:    buf,
+		Level:     lazylog.INFO,
+		Formatter: &lazylog.JSONFormatter{},
+	}
+	logger := lazylog.NewLogger(tr)
+	logger.EnableEscalation(lazylog.EscalationConfig{Threshold: 3, Window: time.Minute})
+	for i := 0; i < 4; i++ {
+		logger.Error("db timeout after " + strconv.Itoa(i) + "ms")
+	}
+	var escalated []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var m map[string]interface{}
+		_ = json.Unmarshal([]byte(line), &m)
+		if m["escalated"] == true {
+			escalated = append(escalated, m)
+		}
+	}
+	if len(escalated) != 1 || escalated[0]["occurrences"] != float64(3) {
+		t.Errorf("expected exactly one escalation entry, got %v", escalated)
+	}
+
+	// Sem Window, vale DefaultEscalationWindow (antes, nunca escalava).
+	buf.Reset()
+	logger = lazylog.NewLogger(tr)
+	logger.EnableEscalation(lazylog.EscalationConfig{Threshold: 2})
+	logger.Error("cache miss 1")
+	logger.Error("cache miss 2")
+	if !strings.Contains(buf.String(), `"escalated":true`) || !strings.Contains(buf.String(), `"window":"5m0s"`) {
+		t.Errorf("zero window must use the default: %s", buf.String())
+	}
+}
+
+func TestOnce(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO})
+	for i := 0; i < 3; i++ {
+		logger.Once("legacy-api").Warn("deprecated API used")
+		logger.OnceEvery("fallback", time.Hour).Info("using fallback config")
+	}
+	if n := strings.Count(buf.String(), "deprecated API used"); n != 1 {
+		t.Errorf("Once logged %d times", n)
+	}
+	if n := strings.Count(buf.String(), "using fallback config"); n != 1 {
+		t.Errorf("OnceEvery logged %d times", n)
+	}
+
+	// Um uso num nível desabilitado não consome a chave.
+	logger.Once("startup").Debug("filtered")
+	logger.OnceEvery("retry", time.Hour).Debug("filtered")
+	logger.Once("startup").Info("first visible")
+	logger.OnceEvery("retry", time.Hour).Info("first visible retry")
+	if !strings.Contains(buf.String(), "first visible") || !strings.Contains(buf.String(), "first visible retry") {
+		t.Errorf("disabled level consumed the once key: %s", buf.String())
+	}
+}
+
+func TestStep(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tr := &lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}}
+	logger := lazylog.NewLogger(tr)
+	logger.Step("migrate db").Done(nil)
+	step := logger.Step("seed")
+	step.Done(errors.New("constraint violated"))
+	step.Done(nil)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 entries, got %d: %s", len(lines), buf.String())
+	}
+	var done, failed map[string]interface{}
+	_ = json.Unmarshal([]byte(lines[1]), &done)
+	_ = json.Unmarshal([]byte(lines[3]), &failed)
+	if done["status"] != "success" || done["step"] != "migrate db" || done["elapsed_ms"] == nil {
+		t.Errorf("unexpected finish entry: %v", done)
+	}
+	if failed["status"] != "failure" || failed["level"] != "ERROR" || failed["error"] != "constraint violated" {
+		t.Errorf("unexpected failure entry: %v", failed)
+	}
+}
+
+func TestTimerElapsedWithDefer(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tr := &lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}}
+	logger := lazylog.NewLogger(tr)
+	func() {
+		timer := lazylog.Timer()
+		defer logger.InfoFields("handler done", timer.Elapsed(), lazylog.Field{Key: "route", Value: "/x"})
+		time.Sleep(5 * time.Millisecond)
+	}()
+	var m map[string]interface{}
+	_ = json.Unmarshal(buf.Bytes(), &m)
+	if ms, ok := m["elapsed_ms"].(float64); !ok || ms < 5 || m["route"] != "/x" {
+		t.Errorf("elapsed field not computed at dispatch time: %v", m)
+	}
+}
+
+func TestAggregatingTransport(t *testing.T) {
+	buf := &bytes.Buffer{}
+	target := &lazylog.WriterTransport{Writer: buf, Level: lazylog.DEBUG, Formatter: &lazylog.JSONFormatter{}}
+	agg := lazylog.NewAggregatingTransport(target, lazylog.DEBUG, time.Hour, "route")
+	logger := lazylog.NewLogger(agg)
+	for i := 0; i < 5; i++ {
+		logger.ComFields(map[string]interface{}{"route": "/a"}).Debug("cache hit " + strconv.Itoa(i))
+	}
+	logger.ComFields(map[string]interface{}{"route": "/b"}).Debug("cache hit 1")
+	if buf.Len() != 0 {
+		t.Fatalf("entries should not be written individually: %s", buf.String())
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]float64{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var m map[string]interface{}
+		_ = json.Unmarshal([]byte(line), &m)
+		counts[m["route"].(string)] = m["count"].(float64)
+	}
+	if counts["/a"] != 5 || counts["/b"] != 1 {
+		t.Errorf("unexpected summary counts: %v", counts)
+	}
+}
+
+func TestAggregatingTransportDefaultInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		agg := lazylog.NewAggregatingTransport(&lazylog.WriterTransport{Writer: io.Discard}, lazylog.DEBUG, interval, "")
+		if agg.Interval != lazylog.DefaultAggregateInterval {
+			t.Errorf("interval %v: expected default %v, got %v", interval, lazylog.DefaultAggregateInterval, agg.Interval)
+		}
+		if err := agg.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSanitization(t *testing.T) {
+	if got := lazylog.SanitizeString("ok\x1b[31m red\x1b[0m\nfake [ERROR] line\x00\xff"); got != `ok red\nfake [ERROR] line`+"�" {
+		t.Errorf("unexpected sanitized string: %q", got)
+	}
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO})
+	logger.EnableSanitization()
+	logger.ComFields(map[string]interface{}{"user": "x\ny"}).Info("login\n2024-01-01T00:00:00Z [ERROR] forged")
+	if strings.Count(buf.String(), "\n") != 1 || strings.Contains(buf.String(), "\x1b") {
+		t.Errorf("output not sanitized: %q", buf.String())
+	}
+}
+
+func TestJSONFormatterDegradesUnencodableFields(t *testing.T) {
+	cyclic := map[string]interface{}{}
+	cyclic["self"] = cyclic
+	out, err := (&lazylog.JSONFormatter{}).Format(&lazylog.Entry{
+		Level:   lazylog.INFO,
+		Message: "degraded",
+		Fields: map[string]interface{}{
+			"ok":     1,
+			"nan":    math.NaN(),
+			"inf":    math.Inf(-1),
+			"ch":     make(chan int),
+			"nested": map[string]interface{}{"cycle": cyclic, "fine": "yes"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(out, &m); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if m["message"] != "degraded" || m["ok"] != float64(1) || m["nan"] != "NaN" || m["inf"] != "-Inf" || m["ch"] != "<chan int>" {
+		t.Errorf("unexpected degraded output: %v", m)
+	}
+	errs, _ := m["_encode_error"].(map[string]interface{})
+	if errs["nan"] == nil || errs["ch"] == nil || errs["nested.cycle.self"] == nil {
+		t.Errorf("encode errors not reported: %v", m["_encode_error"])
+	}
+	if nested, _ := m["nested"].(map[string]interface{}); nested["fine"] != "yes" {
+		t.Errorf("healthy nested field lost: %v", m["nested"])
+	}
+}
+
+func TestMinLevelCache(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tr := &lazylog.WriterTransport{Writer: buf, Level: lazylog.WARN}
+	logger := lazylog.NewLogger(tr)
+	logger.Info("dropped")
+	debug := &lazylog.WriterTransport{Writer: buf, Level: lazylog.DEBUG}
+	logger.AddTransport(debug)
+	logger.Info("accepted after add")
+	logger.RemoveTransport(debug)
+	logger.Info("dropped after remove")
+
+	// Sem EnableLevelCache, alterar o nível de um transporte em uso vale
+	// imediatamente.
+	tr.Level = lazylog.INFO
+	logger.Info("accepted after live change")
+
+	logger.EnableLevelCache()
+	tr.Level = lazylog.DEBUG
+	logger.Debug("dropped by stale cache")
+	logger.RefreshLevels()
+	logger.Debug("accepted after refresh")
+	logger.SetLevel(lazylog.WARN)
+	logger.Info("dropped by SetLevel")
+
+	logger.DisableLevelCache()
+	logger.SetLevel(lazylog.DEBUG)
+	tr.Level = lazylog.ERROR
+	logger.Warn("dropped after disabling the cache")
+
+	out := buf.String()
+	for _, want := range []string{"accepted after add", "accepted after live change", "accepted after refresh"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "dropped") {
+		t.Errorf("min level not enforced: %s", out)
+	}
+}
+
+func TestTypedFieldsPreserveOrder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jsonTr := &lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}}
+	logger := lazylog.NewLogger(jsonTr)
+	logger.InfoFields("ordered",
+		lazylog.Field{Key: "zeta", Value: 1},
+		lazylog.Field{Key: "alpha", Value: "a"},
+		lazylog.Field{Key: "message", Value: "dup"},
+		lazylog.Field{Key: "mid", Value: true},
+	)
+	want := `"message":"ordered","zeta":1,"alpha":"a","fields.message":"dup","mid":true}`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("json fields out of order: %s", buf.String())
+	}
+
+	buf.Reset()
+	jsonTr.Formatter = &lazylog.TextFormatter{}
+	logger.InfoFields("ordered", lazylog.Field{Key: "b", Value: 2}, lazylog.Field{Key: "a", Value: 1})
+	if !strings.Contains(buf.String(), "b=2 a=1") {
+		t.Errorf("text fields out of order: %s", buf.String())
+	}
+}
+
+func TestTypedFieldsReachMapReaders(t *testing.T) {
+	buf := &bytes.Buffer{}
+	rec := &recordingTransport{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}}, rec)
+	var hooked lazylog.Entry
+	logger.AddHook(func(e *lazylog.Entry) { hooked = *e }, false)
+
+	logger.InfoFields("typed", lazylog.Int("b", 2), lazylog.String("a", "x"))
+	if !strings.Contains(buf.String(), `"message":"typed","b":2,"a":"x"}`) {
+		t.Errorf("unexpected json: %s", buf.String())
+	}
+	got := rec.entries[0]
+	if got.Fields["b"] != 2 || got.Fields["a"] != "x" || strings.Join(got.FieldOrder, ",") != "b,a" {
+		t.Errorf("custom transport saw %v %v", got.Fields, got.FieldOrder)
+	}
+	if hooked.Fields["a"] != "x" || len(hooked.FieldOrder) != 2 {
+		t.Errorf("after hook saw %v %v", hooked.Fields, hooked.FieldOrder)
+	}
+
+	buf.Reset()
+	logger.InfoFields("dup", lazylog.Int("k", 1), lazylog.Int("k", 2))
+	if !strings.HasSuffix(buf.String(), `"message":"dup","k":2}`+"\n") {
+		t.Errorf("duplicate key must keep the last value: %s", buf.String())
+	}
+}
+
+func TestAttachmentsSpillLargeFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tr := &lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}}
+	logger := lazylog.NewLogger(tr)
+	dir := t.TempDir()
+	logger.EnableAttachments(&lazylog.FileAttachmentStore{Dir: dir}, 16)
+	body := strings.Repeat("x", 100)
+	logger.ComFields(map[string]interface{}{"body": body, "small": "ok"}).Info("request")
+	var m map[string]interface{}
+	_ = json.Unmarshal(buf.Bytes(), &m)
+	ref, _ := m["body"].(map[string]interface{})
+	if m["small"] != "ok" || ref["size"] != float64(100) || ref["sha256"] == nil {
+		t.Fatalf("unexpected output: %v", m)
+	}
+	data, err := os.ReadFile(ref["attachment"].(string))
+	if err != nil || string(data) != body {
+		t.Errorf("attachment not stored: %v", err)
+	}
+}
+
+func TestFileAttachmentStoreConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	store := &lazylog.FileAttachmentStore{Dir: dir}
+	data := bytes.Repeat([]byte("payload "), 1<<19)
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make(chan error, 32)
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			path, err := store.Store(data, hash)
+			if err == nil {
+				var got []byte
+				if got, err = os.ReadFile(path); err == nil && !bytes.Equal(got, data) {
+					err = fmt.Errorf("partial attachment: %d bytes", len(got))
+				}
+			}
+			errs <- err
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || entries[0].Name() != hash+".bin" {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestSchemaValidation(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tr := &lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}}
+	logger := lazylog.NewLogger(tr)
+	var flagged []string
+	logger.EnableSchemaValidation(lazylog.LogSchema{
+		"order.created": {
+			Required: []string{"order_id"},
+			Fields:   map[string]lazylog.FieldKind{"order_id": lazylog.KindNumber, "customer": lazylog.KindString},
+		},
+	}, func(e *lazylog.Entry, violations []string) { flagged = append(flagged, violations...) })
+
+	logger.ComFields(map[string]interface{}{"event": "order.created", "order_id": 1, "customer": "acme"}).Info("ok")
+	logger.ComFields(map[string]interface{}{"customer": 42}).Info("order.created")
+	logger.Info("unknown event")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || strings.Contains(lines[0], "_schema_violations") || strings.Contains(lines[2], "_schema_violations") {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+	if !strings.Contains(lines[1], "_schema_violations") || len(flagged) != 2 {
+		t.Errorf("violations not flagged: %v / %s", flagged, lines[1])
+	}
+}
+
+func TestEventAPI(t *testing.T) {
+	all := &bytes.Buffer{}
+	signups := &bytes.Buffer{}
+	logger := lazylog.NewLogger(
+		&lazylog.WriterTransport{Writer: all, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}},
+		&lazylog.TransportWithFilter{
+			Transport: &lazylog.WriterTransport{Writer: signups, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}},
+			Filter:    lazylog.EventFilter("user.signup"),
+		},
+	)
+	logger.Event("user.signup").With(lazylog.Field{Key: "plan", Value: "pro"}).Emit()
+	logger.Event("user.login").AtLevel(lazylog.WARN).Message("login from new device").Emit()
+	logger.Event("cache.miss").AtLevel(lazylog.DEBUG).Emit()
+
+	if !strings.Contains(all.String(), `"message":"user.signup","event":"user.signup","plan":"pro"}`) ||
+		!strings.Contains(all.String(), `"level":"WARN","message":"login from new device","event":"user.login"}`) ||
+		strings.Contains(all.String(), "cache.miss") {
+		t.Errorf("unexpected events: %s", all.String())
+	}
+	if strings.Count(signups.String(), "\n") != 1 || !strings.Contains(signups.String(), "user.signup") {
+		t.Errorf("event routing failed: %s", signups.String())
+	}
+}
+
+func TestPackageLevelOverrides(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.DEBUG})
+	if err := logger.SetPackageLevels("...=WARN, github.com/chmenegatti/lazylog_test=DEBUG"); err != nil {
+		t.Fatal(err)
+	}
+	logger.Debug("debug from test package")
+	logger.SetPackageLevel("github.com/chmenegatti/lazylog_test", lazylog.ERROR)
+	logger.Warn("warn suppressed")
+	logger.ComFields(map[string]interface{}{"k": 1}).Info("builder suppressed")
+	logger.ClearPackageLevels()
+	logger.Info("info after clear")
+
+	out := buf.String()
+	if !strings.Contains(out, "debug from test package") || strings.Contains(out, "suppressed") || !strings.Contains(out, "info after clear") {
+		t.Errorf("package overrides not applied: %s", out)
+	}
+	if err := logger.SetPackageLevels("github.com/x=LOUD"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
+
+func TestWatchConfigConsul(t *testing.T) {
+	dir := t.TempDir()
+	configs := []string{
+		`{"Transports":[{"Type":"file","Level":"ERROR","Options":{"path":"` + dir + `/a.log"}}]}`,
+		"Transports:\n  - Type: file\n    Level: DEBUG\n    Options: {path: " + dir + "/b.log}\n",
+	}
+	applied := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idx, _ := strconv.Atoi(r.URL.Query().Get("index"))
+		if idx >= len(configs) {
+			close(applied)
+			<-r.Context().Done()
+			return
+		}
+		w.Header().Set("X-Consul-Index", strconv.Itoa(idx+1))
+		w.Write([]byte(configs[idx]))
+	}))
+	defer srv.Close()
+
+	logger := lazylog.NewLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- logger.WatchConfig(ctx, &lazylog.ConsulSource{Address: srv.URL, Key: "lazylog"}, func(err error) { t.Error(err) })
+	}()
+	<-applied
+	logger.Debug("after reload")
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected watch error: %v", err)
+	}
+	logger.Close()
+	if data, _ := os.ReadFile(dir + "/b.log"); !strings.Contains(string(data), "after reload") {
+		t.Errorf("new config not applied: %q", data)
+	}
+}
+
+func TestConsulSourceWithoutIndexBacksOff(t *testing.T) {
+	var requests atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte("same config")) // sem X-Consul-Index
+	}))
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	var updates int
+	err := (&lazylog.ConsulSource{Address: srv.URL, Key: "lazylog", Retry: 20 * time.Millisecond}).Watch(ctx, func([]byte) { updates++ })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected watch error: %v", err)
+	}
+	if updates != 1 || requests.Load() > 10 {
+		t.Errorf("expected one update and backed off polling, got %d updates in %d requests", updates, requests.Load())
+	}
+}
+
+func TestEtcdSource(t *testing.T) {
+	b64 := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/kv/range":
+			fmt.Fprintf(w, `{"header":{"revision":"7"},"kvs":[{"value":%q}]}`, b64("v1"))
+		case "/v3/watch":
+			fmt.Fprintf(w, `{"result":{"created":true}}`+"\n")
+			fmt.Fprintf(w, `{"result":{"events":[{"kv":{"value":%q}}]}}`+"\n", b64("v2"))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	got := make(chan string, 2)
+	go (&lazylog.EtcdSource{Endpoint: srv.URL, Key: "lazylog"}).Watch(ctx, func(data []byte) { got <- string(data) })
+	if a, b := <-got, <-got; a != "v1" || b != "v2" {
+		t.Errorf("unexpected values: %q %q", a, b)
+	}
+}
+
+type fakeFlags map[string]any
+
+func (f fakeFlags) Bool(_ context.Context, flag string, def bool, attrs map[string]any) bool {
+	if v, ok := f[flag+"@"+fmt.Sprint(attrs["tenant"])].(bool); ok {
+		return v
+	}
+	if v, ok := f[flag].(bool); ok {
+		return v
+	}
+	return def
+}
+
+func (f fakeFlags) Float(_ context.Context, flag string, def float64, attrs map[string]any) float64 {
+	if v, ok := f[flag].(float64); ok {
+		return v
+	}
+	return def
+}
+
+func TestFeatureFlagTransport(t *testing.T) {
+	buf := &bytes.Buffer{}
+	flags := fakeFlags{"debug@acme": true, "redact@acme": true, "sample": 0.0}
+	tr := &lazylog.FeatureFlagTransport{
+		Transport:    &lazylog.WriterTransport{Writer: buf, Level: lazylog.DEBUG, Formatter: &lazylog.JSONFormatter{}},
+		Flags:        flags,
+		TenantField:  "tenant",
+		DebugFlag:    "debug",
+		RedactFlag:   "redact",
+		RedactFields: []string{"email"},
+	}
+	logger := lazylog.NewLogger(tr)
+	fields := map[string]interface{}{"tenant": "acme", "email": "a@b.c"}
+	logger.ComFields(fields).Debug("acme debug")
+	logger.ComFields(map[string]interface{}{"tenant": "other", "email": "x@y.z"}).Debug("other debug")
+	if fields["email"] != "a@b.c" {
+		t.Error("redaction mutated caller fields")
+	}
+	tr.SampleRateFlag = "sample"
+	logger.Info("sampled out")
+	logger.Warn("warn kept")
+
+	out := buf.String()
+	if !strings.Contains(out, "acme debug") || !strings.Contains(out, `"email":"[REDACTED]"`) || strings.Contains(out, "a@b.c") {
+		t.Errorf("acme controls not applied: %s", out)
+	}
+	if strings.Contains(out, "other debug") || strings.Contains(out, "sampled out") || !strings.Contains(out, "warn kept") {
+		t.Errorf("flag gating not applied: %s", out)
+	}
+}
+
+func TestForwardedEntries(t *testing.T) {
+	buf := &bytes.Buffer{}
+	parent := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}})
+	pr, pw := io.Pipe()
+	served := make(chan error)
+	go func() { served <- parent.ServeForwarded(pr) }()
+
+	child := lazylog.NewLogger(lazylog.NewForwardTransport(pw, lazylog.DEBUG))
+	child.InfoFields("from worker", lazylog.Field{Key: "pid", Value: 42}, lazylog.Field{Key: "nan", Value: math.NaN()})
+	child.Debug("debug filtered by parent")
+	child.Close()
+	if err := <-served; err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `"message":"from worker","pid":42,"nan":"NaN"`) || strings.Contains(out, "debug filtered") {
+		t.Errorf("unexpected forwarded output: %s", out)
+	}
+}
+
+// lockedBuffer é um bytes.Buffer seguro para escritas concorrentes.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestCommandLogger(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	buf := &lockedBuffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}})
+	cmd := exec.Command(sh, "-c", `echo first; echo oops >&2; printf partial`)
+	out := lazylog.CommandLogger(cmd, logger, lazylog.INFO)
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	out.Flush()
+	pid := strconv.Itoa(cmd.Process.Pid)
+	for _, want := range []string{
+		`"message":"first","cmd":"sh","pid":` + pid + `,"stream":"stdout"`,
+		`"message":"oops","cmd":"sh","pid":` + pid + `,"stream":"stderr"`,
+		`"message":"partial"`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("missing %s in:\n%s", want, buf.String())
+		}
+	}
+}
+
+type flakyTransport struct {
+	msgs    []string
+	failAt  int
+	written int
+}
+
+func (f *flakyTransport) WriteLog(e *lazylog.Entry) error {
+	if f.written == f.failAt {
+		f.failAt = -1
+		return errors.New("target down")
+	}
+	f.written++
+	f.msgs = append(f.msgs, e.Message)
+	return nil
+}
+
+func (f *flakyTransport) MinLevel() lazylog.Level { return lazylog.DEBUG }
+
+func TestSpoolTransportRecovery(t *testing.T) {
+	dir := t.TempDir()
+	target := &flakyTransport{failAt: 1}
+	spool, err := lazylog.NewSpoolTransport(target, dir, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := lazylog.NewLogger(spool)
+	logger.Info("a")
+	logger.Info("b")
+	logger.Info("c") // lote cheio: entrega falha em "b"
+	logger.Info("d") // segmento novo, nunca entregue (simula crash)
+	if len(target.msgs) != 1 {
+		t.Fatalf("expected partial delivery, got %v", target.msgs)
+	}
+
+	restarted := &flakyTransport{failAt: -1}
+	if _, err := lazylog.NewSpoolTransport(restarted, dir, 3); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(restarted.msgs, ","); got != "b,c,d" {
+		t.Errorf("recovery replayed %q, want b,c,d", got)
+	}
+	if left, _ := filepath.Glob(filepath.Join(dir, "*")); len(left) != 0 {
+		t.Errorf("spool not cleaned up: %v", left)
+	}
+}
+
+type recordingTransport struct {
+	mu      sync.Mutex
+	entries []lazylog.Entry
+}
+
+func (r *recordingTransport) WriteLog(e *lazylog.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, *e)
+	return nil
+}
+
+func (r *recordingTransport) MinLevel() lazylog.Level { return lazylog.DEBUG }
+
+func TestReplayPreservesTimestampsThroughTransports(t *testing.T) {
+	base := time.Date(2020, 5, 17, 10, 0, 0, 0, time.UTC)
+	var entries []*lazylog.Entry
+	for i := 0; i < 5; i++ {
+		entries = append(entries, &lazylog.Entry{
+			Level:      lazylog.INFO,
+			Timestamp:  base.Add(time.Duration(i) * time.Second),
+			Message:    "legacy " + strconv.Itoa(i),
+			Fields:     map[string]interface{}{"b": i, "a": i},
+			FieldOrder: []string{"b", "a"},
+		})
+	}
+
+	direct := &recordingTransport{}
+	viaAsync := &recordingTransport{}
+	viaSpool := &recordingTransport{}
+	spool, err := lazylog.NewSpoolTransport(viaSpool, t.TempDir(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := lazylog.NewLogger(direct, lazylog.NewAsyncTransport(viaAsync, 8, lazylog.OverflowBlock), spool)
+	if err := logger.Replay(entries); err != nil {
+		t.Fatal(err)
+	}
+	logger.Close()
+
+	for name, rec := range map[string]*recordingTransport{"direct": direct, "async": viaAsync, "spool": viaSpool} {
+		if len(rec.entries) != len(entries) {
+			t.Fatalf("%s: got %d entries", name, len(rec.entries))
+		}
+		for i, e := range rec.entries {
+			if !e.Timestamp.Equal(entries[i].Timestamp) || e.Message != entries[i].Message || strings.Join(e.FieldOrder, ",") != "b,a" {
+				t.Errorf("%s: entry %d not preserved: %+v", name, i, e)
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	jsonLogger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}})
+	jsonLogger.LogAt(base, lazylog.WARN, "imported", nil)
+	if !strings.Contains(buf.String(), `"timestamp":"2020-05-17T10:00:00Z","level":"WARN"`) {
+		t.Errorf("formatter did not use supplied timestamp: %s", buf.String())
+	}
+}
+
+func TestUTCAndClockSkew(t *testing.T) {
+	// Servidor NTP falso cujo relógio está 2s à frente.
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	go func() {
+		buf := make([]byte, 48)
+		for {
+			_, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			now := time.Now().Add(2 * time.Second)
+			resp := make([]byte, 48)
+			resp[0] = 0x1C // VN=3, Mode=4
+			secs := uint32(now.Unix() + 2208988800)
+			frac := uint32((uint64(now.Nanosecond()) << 32) / uint64(time.Second))
+			for _, off := range []int{32, 40} {
+				binary.BigEndian.PutUint32(resp[off:], secs)
+				binary.BigEndian.PutUint32(resp[off+4:], frac)
+			}
+			pc.WriteTo(resp, addr)
+		}
+	}()
+	offset, err := lazylog.QueryNTPOffset(pc.LocalAddr().String(), time.Second)
+	if err != nil || offset < 1900*time.Millisecond || offset > 2100*time.Millisecond {
+		t.Fatalf("unexpected offset %v (%v)", offset, err)
+	}
+
+	monitor := lazylog.NewClockSkewMonitor(pc.LocalAddr().String(), time.Hour)
+	defer monitor.Close()
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if _, ok := monitor.Skew(); ok {
+			break
+		}
+	}
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}})
+	logger.EnableUTC()
+	logger.AddHook(monitor.Hook(), true)
+	logger.LogAt(time.Date(2024, 3, 1, 9, 0, 0, 0, time.FixedZone("BRT", -3*3600)), lazylog.INFO, "skewed", nil)
+	var m map[string]interface{}
+	json.Unmarshal(buf.Bytes(), &m)
+	if m["timestamp"] != "2024-03-01T12:00:00Z" || m["tz_offset"] != "-03:00" {
+		t.Errorf("UTC conversion failed: %v", m)
+	}
+	if skew, _ := m["clock_skew_ms"].(float64); skew < 1900 || skew > 2100 {
+		t.Errorf("clock skew not recorded: %v", m)
+	}
+}
+
+func TestClockSkewMonitorDefaultsAndDoubleClose(t *testing.T) {
+	monitor := lazylog.NewClockSkewMonitor("127.0.0.1:1", 0)
+	if monitor.Interval != lazylog.DefaultClockSkewInterval {
+		t.Errorf("expected default interval, got %v", monitor.Interval)
+	}
+	monitor.Close()
+	if err := monitor.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
+
+func TestLocalizedLevelLabels(t *testing.T) {
+	buf := &bytes.Buffer{}
+	text := &lazylog.WriterTransport{Writer: buf, Level: lazylog.DEBUG, Formatter: &lazylog.TextFormatter{Labels: lazylog.LocaleLabels("pt_BR")}}
+	jsonBuf := &bytes.Buffer{}
+	js := &lazylog.WriterTransport{Writer: jsonBuf, Level: lazylog.DEBUG, Formatter: &lazylog.JSONFormatter{}}
+	logger := lazylog.NewLogger(text, js)
+	logger.Warn("disco quase cheio")
+	logger.Error("falhou")
+	if !strings.Contains(buf.String(), "[AVISO] disco quase cheio") || !strings.Contains(buf.String(), "[ERRO] falhou") {
+		t.Errorf("labels not localized: %s", buf.String())
+	}
+	if !strings.Contains(jsonBuf.String(), `"level":"WARN"`) {
+		t.Errorf("json level must stay stable: %s", jsonBuf.String())
+	}
+	if lazylog.LocaleLabels("pt-PT")[lazylog.WARN] != "AVISO" || lazylog.LocaleLabels("xx") != nil {
+		t.Error("unexpected locale fallback")
+	}
+}
+
+func TestMonitorCrashes(t *testing.T) {
+	if os.Getenv("LAZYLOG_CRASH_HELPER") != "" {
+		path := os.Getenv("LAZYLOG_CRASH_HELPER")
+		err := lazylog.MonitorCrashes(func() *lazylog.Logger {
+			ft, err := lazylog.NewFileTransport(path, lazylog.DEBUG, &lazylog.JSONFormatter{})
+			if err != nil {
+				panic(err)
+			}
+			return lazylog.NewLogger(ft)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() { panic("boom from goroutine") }()
+		select {}
+	}
+	path := filepath.Join(t.TempDir(), "crash.log")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestMonitorCrashes$")
+	cmd.Env = append(os.Environ(), "LAZYLOG_CRASH_HELPER="+path)
+	if err := cmd.Run(); err == nil {
+		t.Fatal("helper process should crash")
+	}
+	var data []byte
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if data, _ = os.ReadFile(path); len(data) > 0 {
+			break
+		}
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("no crash entry written: %q", data)
+	}
+	p, _ := m["panic"].(map[string]interface{})
+	if m["message"] != "process crashed" || m["level"] != "PANIC" || p["value"] != "boom from goroutine" || !strings.Contains(m["crash_report"].(string), "goroutine") {
+		t.Fatalf("unexpected crash entry: %v", m)
+	}
+	stack, _ := p["stack"].([]interface{})
+	if len(stack) == 0 || !strings.Contains(fmt.Sprint(stack[0]), "TestMonitorCrashes") || !strings.Contains(fmt.Sprint(stack[0]), "lazylog_test.go:") {
+		t.Errorf("unexpected crash stack: %v", p["stack"])
+	}
+}
+
+func TestCrashDumpOnPanic(t *testing.T) {
+	dir := t.TempDir()
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}})
+	logger.EnableCrashDump(dir, 2)
+	logger.Info("first")
+	logger.Info("second")
+	logger.Info("third")
+	func() {
+		defer func() { recover() }()
+		logger.Panic("kaboom")
+	}()
+	files, _ := filepath.Glob(filepath.Join(dir, "crash-*.txt"))
+	if len(files) != 1 {
+		t.Fatalf("expected one crash dump, got %v", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	report := string(data)
+	for _, want := range []string{"panic: kaboom", "=== build info ===", `"message":"second"`, `"message":"third"`, "=== goroutines ===", "TestCrashDumpOnPanic"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("crash dump missing %q", want)
+		}
+	}
+	if strings.Contains(report, `"message":"first"`) {
+		t.Error("ring buffer should keep only the last 2 entries")
+	}
+	if !strings.Contains(buf.String(), `"crash_dump":"`+strings.ReplaceAll(files[0], `\`, `\\`)) {
+		t.Errorf("panic entry should reference the dump: %s", buf.String())
+	}
+}
+
+func TestHTTPTransportMaxInFlight(t *testing.T) {
+	var mu sync.Mutex
+	var current, peak int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		current++
+		if current > peak {
+			peak = current
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		current--
+		mu.Unlock()
+	}))
+	defer srv.Close()
+	client, err := lazylog.NewHTTPClient(nil, &lazylog.NetworkOptions{MaxConnsPerHost: 4, MaxIdleConnsPerHost: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := &lazylog.HTTPTransport{URL: srv.URL, Level: lazylog.INFO, Client: client, MaxInFlight: 2}
+	logger := lazylog.NewLogger(tr)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Info("burst")
+		}()
+	}
+	wg.Wait()
+	if peak > 2 || peak == 0 {
+		t.Errorf("expected at most 2 requests in flight, got %d", peak)
+	}
+}
+
+func TestHTTPTransportInFlightWaitIsBounded(t *testing.T) {
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+	}))
+	defer srv.Close()
+	tr := &lazylog.HTTPTransport{URL: srv.URL, Level: lazylog.INFO, MaxInFlight: 1, InFlightWait: 20 * time.Millisecond}
+	logger := lazylog.NewLogger(tr)
+	done := make(chan error, 1)
+	go func() { done <- logger.TryLog(lazylog.INFO, "holds the slot", nil) }()
+	<-started
+
+	if err := logger.TryLog(lazylog.INFO, "waits", nil); !errors.Is(err, lazylog.ErrInFlightLimit) {
+		t.Errorf("expected ErrInFlightLimit, got %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("first write failed: %v", err)
+	}
+	if tr.Stats().Errors != 1 {
+		t.Errorf("expected the timed out write in the stats, got %+v", tr.Stats())
+	}
+}
+
+func TestPriorityAsyncTransportKeepsErrors(t *testing.T) {
+	gate := &gatedTransport{started: make(chan struct{}, 8), release: make(chan struct{})}
+	async := lazylog.NewPriorityAsyncTransport(gate, 1, 4, lazylog.OverflowDrop)
+	logger := lazylog.NewLogger(async)
+
+	logger.Info("in flight")
+	<-gate.started
+	if err := logger.TryLog(lazylog.INFO, "queued", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := logger.TryLog(lazylog.INFO, "dropped", nil); !errors.Is(err, lazylog.ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+	for _, msg := range []string{"err1", "err2"} {
+		if err := logger.TryLog(lazylog.ERROR, msg, nil); err != nil {
+			t.Fatalf("error entry must not be dropped: %v", err)
+		}
+	}
+	close(gate.release)
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(gate.msgs, ","); got != "in flight,err1,err2,queued" {
+		t.Errorf("unexpected writes: %s", got)
+	}
+}
+
+func TestAsyncTransportQueueEvents(t *testing.T) {
+	gate := &gatedTransport{started: make(chan struct{}, 4), release: make(chan struct{})}
+	async := lazylog.NewAsyncTransport(gate, 2, lazylog.OverflowDrop)
+	var events []lazylog.QueueEvent
+	async.OnQueueEvent = func(ev lazylog.QueueEvent) { events = append(events, ev) }
+	logger := lazylog.NewLogger(async)
+
+	logger.Info("in flight")
+	<-gate.started
+	logger.Info("q1")
+	logger.Info("q2")
+	logger.Info("dropped")
+
+	var highWater, dropped int
+	for _, ev := range events {
+		switch ev.Kind {
+		case lazylog.QueueHighWater:
+			highWater = ev.HighWater
+		case lazylog.QueueDropped:
+			dropped++
+			if ev.Entry.Message != "dropped" || ev.Capacity != 2 {
+				t.Errorf("unexpected drop event: %+v", ev)
+			}
+		}
+	}
+	if highWater != 2 || async.HighWater() != 2 {
+		t.Errorf("expected high-water mark 2, got %d/%d", highWater, async.HighWater())
+	}
+	if dropped != 1 {
+		t.Errorf("expected one drop event, got %d", dropped)
+	}
+	close(gate.release)
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTransportConfigMaxLevelAndFilter(t *testing.T) {
+	dir := t.TempDir()
+	cfg := lazylog.LoggerConfig{Transports: []lazylog.TransportConfig{
+		{Type: "file", Level: "ERROR", Formatter: "text", Options: map[string]any{"path": filepath.Join(dir, "errors.log")}},
+		{
+			Type: "file", Level: "DEBUG", MaxLevel: "WARN", Formatter: "text",
+			Filter:  `service == billing || message =~ "^keep"`,
+			Options: map[string]any{"path": filepath.Join(dir, "app.log")},
+		},
+	}}
+	logger, err := lazylog.NewLoggerFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.ComFields(map[string]interface{}{"service": "billing"}).Info("billed")
+	logger.Info("keep me")
+	logger.ComFields(map[string]interface{}{"service": "auth"}).Info("skipped")
+	logger.ComFields(map[string]interface{}{"service": "billing"}).Error("failed")
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	app, _ := os.ReadFile(filepath.Join(dir, "app.log"))
+	errs, _ := os.ReadFile(filepath.Join(dir, "errors.log"))
+	if !strings.Contains(string(app), "billed") || !strings.Contains(string(app), "keep me") ||
+		strings.Contains(string(app), "skipped") || strings.Contains(string(app), "failed") {
+		t.Errorf("unexpected app.log:\n%s", app)
+	}
+	if !strings.Contains(string(errs), "failed") || strings.Contains(string(errs), "billed") {
+		t.Errorf("unexpected errors.log:\n%s", errs)
+	}
+
+	for _, bad := range []lazylog.TransportConfig{
+		{Type: "console", MaxLevel: "LOUD"},
+		{Type: "console", Filter: "level >= WARN"},
+		{Type: "console", Filter: `message =~ "("`},
+	} {
+		if _, err := lazylog.NewLoggerFromConfig(lazylog.LoggerConfig{Transports: []lazylog.TransportConfig{bad}}); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	bodies := make(chan map[string]any, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var m map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&m)
+		bodies <- m
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger, err := lazylog.Build().
+		File(path, lazylog.DEBUG, lazylog.Text(), lazylog.MaxLevel(lazylog.WARN)).
+		Loki(srv.URL, lazylog.ERROR, lazylog.Labels(map[string]string{"app": "api"})).
+		Fields(map[string]interface{}{"service": "billing"}).
+		Logger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Debug("starting")
+	logger.Error("boom")
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "[DEBUG] starting service=billing") || strings.Contains(string(data), "boom") {
+		t.Errorf("unexpected file output:\n%s", data)
+	}
+	push := <-bodies
+	stream := push["streams"].([]any)[0].(map[string]any)
+	labels := stream["stream"].(map[string]any)
+	line := stream["values"].([]any)[0].([]any)[1].(string)
+	if labels["app"] != "api" || labels["level"] != "ERROR" || !strings.Contains(line, `"message":"boom"`) {
+		t.Errorf("unexpected loki push: %v", push)
+	}
+
+	if _, err := lazylog.Build().File(filepath.Join(t.TempDir(), "missing", "x.log"), lazylog.INFO).Logger(); err == nil {
+		t.Error("expected error for unwritable file")
+	}
+}
+
+func TestColorFormatter(t *testing.T) {
+	out, _ := (&lazylog.ColorFormatter{}).Format(&lazylog.Entry{Level: lazylog.ERROR, Message: "red"})
+	if !strings.HasPrefix(string(out), "\x1b[31m") || !strings.HasSuffix(string(out), "red\x1b[0m\n") {
+		t.Errorf("unexpected colored output: %q", out)
+	}
+}
+
+func TestFatalFlushesAndRunsExitHandlers(t *testing.T) {
+	if dir := os.Getenv("LAZYLOG_FATAL_HELPER"); dir != "" {
+		ft, err := lazylog.NewFileTransport(filepath.Join(dir, "app.log"), lazylog.INFO, &lazylog.JSONFormatter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		logger := lazylog.NewLogger(lazylog.NewAsyncTransport(ft, 16, lazylog.OverflowBlock))
+		logger.OnExit(func() { panic("ignored") })
+		logger.OnExit(func() { os.WriteFile(filepath.Join(dir, "cleanup"), []byte("ok"), 0o644) })
+		fields := map[string]any{"job": 7}
+		logger.OnExit(func() { os.WriteFile(filepath.Join(dir, "fields"), []byte(fmt.Sprint(len(fields))), 0o644) })
+		logger.Fatal("cannot continue", fields)
+		return
+	}
+	dir := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFatalFlushesAndRunsExitHandlers$")
+	cmd.Env = append(os.Environ(), "LAZYLOG_FATAL_HELPER="+dir)
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("expected exit status 1, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cleanup")); err != nil {
+		t.Error("exit handler did not run")
+	}
+	if n, _ := os.ReadFile(filepath.Join(dir, "fields")); string(n) != "1" {
+		t.Errorf("Fatal must not modify the caller's map, got %q keys", n)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "app.log"))
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil || m["level"] != "FATAL" || m["message"] != "cannot continue" || m["job"] != float64(7) {
+		t.Errorf("fatal entry not flushed: %q", data)
+	}
+	if lazylog.ParseLevel("fatal") != lazylog.FATAL {
+		t.Error("FATAL not parseable")
+	}
+}
+
+func TestLazyTransportConnectsOnDemand(t *testing.T) {
+	probe, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := probe.Addr().String()
+	probe.Close()
+
+	lazy := lazylog.NewLazyTransport(lazylog.INFO, func(ctx context.Context) (lazylog.Transport, error) {
+		return lazylog.DialForwardTransport("tcp", addr, lazylog.INFO)
+	})
+	logger := lazylog.NewLogger(lazy)
+	defer logger.Close()
+
+	if err := logger.TryLog(lazylog.INFO, "too early", nil); !errors.Is(err, lazylog.ErrTransportNotReady) {
+		t.Fatalf("expected ErrTransportNotReady, got %v", err)
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("address reused: %v", err)
+	}
+	defer ln.Close()
+	if err := logger.Ready(context.Background()); err != nil {
+		t.Fatalf("expected ready after listener is up: %v", err)
+	}
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	logger.Info("delivered")
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || !strings.Contains(line, `"delivered"`) {
+		t.Errorf("unexpected forwarded line %q: %v", line, err)
+	}
+}
+
+func TestPanicLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.ERROR, Formatter: &lazylog.JSONFormatter{}})
+	fields := map[string]any{"job": 7}
+	var recovered any
+	func() {
+		defer func() { recovered = recover() }()
+		logger.Panic("unrecoverable state", fields)
+	}()
+	if recovered != "unrecoverable state" {
+		t.Errorf("expected panic with the message, got %v", recovered)
+	}
+	if len(fields) != 1 {
+		t.Errorf("Panic must not modify the caller's map: %v", fields)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	p, _ := m["panic"].(map[string]interface{})
+	if m["level"] != "PANIC" || p == nil || p["stack"] == nil || p["value"] != "unrecoverable state" {
+		t.Errorf("unexpected panic entry: %v", m)
+	}
+	if _, ok := p["type"]; ok {
+		t.Errorf("panic.type is always string for Logger.Panic and must be omitted: %v", p)
+	}
+	if m["job"] != float64(7) {
+		t.Errorf("caller fields missing: %v", m)
+	}
+
+	logger.EnableStacktrace(lazylog.ERROR)
+	if err := logger.TryLog(lazylog.ERROR, "with stack", fields); err != nil || len(fields) != 1 {
+		t.Errorf("stacktrace must not be added to the caller's map: %v (%v)", fields, err)
+	}
+	if lazylog.ParseLevel("panic") != lazylog.PANIC || !(lazylog.ERROR < lazylog.PANIC && lazylog.PANIC < lazylog.FATAL) {
+		t.Error("PANIC must sit between ERROR and FATAL")
+	}
+}
+
+func TestLoggerValidate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	good, _ := lazylog.NewHTTPTransport(srv.URL, lazylog.INFO, nil, nil)
+	good.Headers = map[string]string{"Authorization": "Bearer ok"}
+	anonymous, _ := lazylog.NewHTTPTransport(srv.URL, lazylog.INFO, nil, nil)
+	closed, err := lazylog.NewFileTransport(filepath.Join(t.TempDir(), "app.log"), lazylog.INFO, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed.Close()
+
+	logger := lazylog.NewLogger(
+		&lazylog.ConsoleTransport{Level: lazylog.INFO},
+		lazylog.NewAsyncTransport(good, 4, lazylog.OverflowBlock),
+		&lazylog.TransportWithFilter{Transport: anonymous},
+		closed,
+	)
+	err = logger.Validate(context.Background())
+	var failed []int
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var verr *lazylog.TransportValidationError
+		if errors.As(e, &verr) {
+			failed = append(failed, verr.Index)
+		}
+	}
+	if fmt.Sprint(failed) != "[2 3]" {
+		t.Errorf("expected transports 2 and 3 to fail, got %v (%v)", failed, err)
+	}
+}
+
+func TestTraceLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	debugOnly := &lazylog.WriterTransport{Writer: io.Discard, Level: lazylog.DEBUG, Formatter: &lazylog.TextFormatter{}}
+	logger := lazylog.NewLogger(debugOnly)
+	logger.Trace("invisible")
+
+	logger.AddTransport(&lazylog.WriterTransport{Writer: buf, Level: lazylog.ParseLevel("trace"), Formatter: &lazylog.JSONFormatter{}})
+	logger.Trace("hot loop")
+	logger.TraceCtx(context.Background(), "with ctx", map[string]interface{}{"i": 1})
+	if n := strings.Count(buf.String(), `"level":"TRACE"`); n != 2 {
+		t.Errorf("expected 2 TRACE entries, got %d: %s", n, buf.String())
+	}
+	if out, _ := (&lazylog.TextFormatter{}).Format(&lazylog.Entry{Level: lazylog.TRACE, Message: "m"}); !strings.Contains(string(out), "[TRACE] m") {
+		t.Errorf("unexpected text output: %q", out)
+	}
+	if lazylog.TRACE >= lazylog.DEBUG {
+		t.Error("TRACE must be below DEBUG")
+	}
+}
+
+func TestNewDualLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger, err := lazylog.Build().Dual(lazylog.WARN, path, lazylog.DEBUG).Logger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.ComFields(map[string]interface{}{"order_id": 7}).Debug("machine only")
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil || m["message"] != "machine only" || m["order_id"] != float64(7) {
+		t.Errorf("expected full JSON in file, got %q", data)
+	}
+
+	dual := lazylog.NewDualLogger(lazylog.INFO, &lazylog.WriterTransport{Writer: io.Discard, Level: lazylog.DEBUG})
+	if err := dual.Validate(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGoroutineInfo(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}})
+	logger.EnableGoroutineInfo(true)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info("from worker")
+	}()
+	<-done
+	var m map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := m["goroutine_id"].(float64); !ok || id <= 0 {
+		t.Errorf("missing goroutine_id: %v", m)
+	}
+	site, _ := m["goroutine_created_by"].(string)
+	if !strings.Contains(site, "TestGoroutineInfo") || !strings.Contains(site, "lazylog_test.go:") {
+		t.Errorf("unexpected creation site: %q", site)
+	}
+}
+
+func TestTextFormatterValueCoercion(t *testing.T) {
+	type user struct {
+		Name string
+		Age  int
+	}
+	entry := &lazylog.Entry{Level: lazylog.INFO, Message: "m", Fields: map[string]interface{}{
+		"tags": []string{"a", "b"},
+		"meta": map[string]int{"x": 1},
+		"user": user{Name: "ana", Age: 3},
+		"raw":  []byte("hi"),
+		"err":  errors.New("boom"),
+	}}
+	cases := []struct {
+		f    *lazylog.TextFormatter
+		want []string
+	}{
+		{&lazylog.TextFormatter{}, []string{`tags=["a","b"]`, `meta={"x":1}`, `user={Name:ana Age:3}`, `raw=6869`, `err=boom`}},
+		{
+			&lazylog.TextFormatter{Composite: lazylog.TextCompositeGo, Struct: lazylog.TextStructJSON, Bytes: lazylog.TextBytesBase64},
+			[]string{`tags=[a b]`, `meta=map[x:1]`, `user={"Name":"ana","Age":3}`, `raw=aGk=`},
+		},
+	}
+	for _, c := range cases {
+		out, err := c.f.Format(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range c.want {
+			if !strings.Contains(string(out), want+" ") {
+				t.Errorf("missing %s in %q", want, out)
+			}
+		}
+	}
+}
+
+func TestWithFieldsReturnsDerivedLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	parent := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}})
+	child := parent.WithFields(map[string]any{"service": "auth", "env": "prod"})
+	grandchild := child.WithFields(map[string]any{"request_id": "r1"})
+
+	var hooked int
+	child.AddHook(func(e *lazylog.Entry) { hooked++ }, true) // compartilhado com o pai
+
+	own := map[string]interface{}{"env": "staging"}
+	grandchild.ComFields(own).Info("nested")
+	parent.Info("plain")
+	if len(own) != 1 {
+		t.Errorf("caller map mutated: %v", own)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %s", len(lines), buf.String())
+	}
+	var first, second map[string]interface{}
+	_ = json.Unmarshal([]byte(lines[0]), &first)
+	_ = json.Unmarshal([]byte(lines[1]), &second)
+	if first["service"] != "auth" || first["request_id"] != "r1" || first["env"] != "staging" {
+		t.Errorf("bound fields not merged: %v", first)
+	}
+	if _, ok := second["service"]; ok {
+		t.Errorf("parent must not carry child fields: %v", second)
+	}
+	if hooked != 2 {
+		t.Errorf("hook added via child should run for parent and child, ran %d", hooked)
+	}
+}
+
+func TestEntryJSONRoundTrip(t *testing.T) {
+	lazylog.RegisterLevel("AUDIT", 42)
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.FixedZone("BRT", -3*3600))
+	orig := lazylog.Entry{
+		Level: 42, Timestamp: ts, Message: "exported",
+		Fields:     map[string]interface{}{"b": "2", "a": float64(1)},
+		FieldOrder: []string{"b", "a"},
+	}
+	data, err := json.Marshal(orig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"v":1,"level":"AUDIT","level_num":42`) {
+		t.Errorf("unexpected wire format: %s", data)
+	}
+	var got lazylog.Entry
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Level != 42 || !got.Timestamp.Equal(ts) || got.Timestamp.Format(time.RFC3339Nano) != ts.Format(time.RFC3339Nano) ||
+		got.Message != "exported" || got.Fields["a"] != float64(1) || strings.Join(got.FieldOrder, ",") != "b,a" {
+		t.Errorf("round trip mismatch: %+v", got)
+	}
+
+	var unknown lazylog.Entry
+	if err := json.Unmarshal([]byte(`{"v":1,"level":"NOT_REGISTERED","level_num":77,"message":"x"}`), &unknown); err != nil || unknown.Level != 77 {
+		t.Errorf("numeric level fallback failed: %v %v", unknown.Level, err)
+	}
+	if err := json.Unmarshal([]byte(`{"v":99,"level":"INFO"}`), &unknown); err == nil {
+		t.Error("expected error for future wire version")
+	}
+}
+
+func TestWithField(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}})
+	base := logger.ComFields(map[string]interface{}{"a": 1})
+	base.WithField("b", 2).Info("builder")
+	base.Info("untouched")
+	logger.WithField("svc", "api").WithField("zone", "us").Info("derived")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 entries, got %s", buf.String())
+	}
+	if !strings.Contains(lines[0], `"a":1,"b":2`) {
+		t.Errorf("builder field missing: %s", lines[0])
+	}
+	if strings.Contains(lines[1], `"b"`) {
+		t.Errorf("WithField mutated the original builder: %s", lines[1])
+	}
+	if !strings.Contains(lines[2], `"svc":"api","zone":"us"`) {
+		t.Errorf("bound fields missing: %s", lines[2])
+	}
+}
+
+func TestLogcatTransportUnavailableOffAndroid(t *testing.T) {
+	if _, err := lazylog.NewLogcatTransport("app", lazylog.INFO, nil); !errors.Is(err, lazylog.ErrLogcatUnavailable) {
+		t.Errorf("expected ErrLogcatUnavailable, got %v", err)
+	}
+}
+
+type stackErr struct{ msg string }
+
+func (e stackErr) Error() string        { return e.msg }
+func (e stackErr) StackTrace() []string { return []string{"main.f\n\tmain.go:10"} }
+
+func TestWithError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}})
+	root := stackErr{msg: "disk full"}
+	wrapped := fmt.Errorf("save order: %w", root)
+	logger.WithError(wrapped).Error("failed")
+	logger.ComFields(map[string]interface{}{"id": 1}).WithError(nil).Info("no error")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &m); err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := m["error_chain"].([]interface{})
+	stack, _ := m["error_stack"].([]interface{})
+	if m["error"] != "save order: disk full" || len(chain) != 1 || chain[0] != "disk full" || len(stack) != 1 || stack[0] != "main.f main.go:10" {
+		t.Errorf("unexpected error fields: %v", m)
+	}
+	if strings.Contains(lines[1], `"error`) {
+		t.Errorf("nil error must not add fields: %s", lines[1])
+	}
+}
+
+func TestNamedLoggers(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.DEBUG, Formatter: &lazylog.JSONFormatter{}})
+	server := logger.Named("http").Named("server")
+	pool := logger.Named("db").WithField("shard", 1).Named("pool")
+	if server.Name() != "http.server" || pool.Name() != "db.pool" {
+		t.Fatalf("unexpected names %q %q", server.Name(), pool.Name())
+	}
+	if err := logger.SetNameLevels("...=INFO, db=DEBUG"); err != nil {
+		t.Fatal(err)
+	}
+	server.Debug("dropped")
+	pool.Debug("kept")
+	logger.Named("dbx").Debug("dropped")
+	logger.Debug("dropped")
+	server.Info("served")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %s", buf.String())
+	}
+	if !strings.Contains(lines[0], `"logger":"db.pool"`) || !strings.Contains(lines[0], `"shard":1`) {
+		t.Errorf("unexpected db entry: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"logger":"http.server"`) {
+		t.Errorf("unexpected http entry: %s", lines[1])
+	}
+
+	logger.ClearNameLevels()
+	buf.Reset()
+	server.Debug("visible")
+	if !strings.Contains(buf.String(), "visible") {
+		t.Error("ClearNameLevels did not remove overrides")
+	}
+	if err := logger.SetNameLevels("db=LOUD"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
+
+func TestNopLogger(t *testing.T) {
+	nop := lazylog.Nop()
+	nop.AddTransport(&lazylog.WriterTransport{Writer: &bytes.Buffer{}, Level: lazylog.DEBUG})
+	fields := map[string]interface{}{"k": "v"}
+	allocs := testing.AllocsPerRun(100, func() {
+		nop.Info("x")
+		nop.ComFields(fields).WithField("a", 1).Error("x")
+		nop.InfoFields("x", lazylog.Field{Key: "k", Value: "v"})
+		nop.WithFields(fields).Named("db").WithError(io.EOF).Warn("x")
+		nop.Event("e").With(lazylog.Field{Key: "n", Value: 1}).Emit()
+		nop.Step("s").Done(nil)
+		nop.NewCanonicalEntry().Emit(lazylog.INFO, "x")
+	})
+	if allocs != 0 {
+		t.Errorf("expected zero allocations, got %v", allocs)
+	}
+}
+
+func TestNilLoggerIsSafe(t *testing.T) {
+	var logger *lazylog.Logger
+	logger.Info("x")
+	logger.ErrorCtx(context.Background(), "x", nil)
+	logger.ComFields(map[string]interface{}{"k": 1}).Warn("x")
+	logger.Once("k").Info("x")
+	logger.WithField("k", 1).Named("n").Debug("x")
+	logger.LogPanic("boom")
+	logger.AddHook(func(*lazylog.Entry) {}, true)
+	logger.EnableStacktrace(lazylog.ERROR)
+	if err := logger.TryLog(lazylog.ERROR, "x", nil); err != nil {
+		t.Errorf("TryLog on nil logger: %v", err)
+	}
+	if err := logger.SetPackageLevels("...=INFO"); err != nil {
+		t.Errorf("SetPackageLevels on nil logger: %v", err)
+	}
+	if err := logger.Validate(context.Background()); err != nil {
+		t.Errorf("Validate on nil logger: %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Errorf("Close on nil logger: %v", err)
+	}
+	if logger.Name() != "" {
+		t.Error("nil logger should have no name")
+	}
+}
+
+type mockLog struct {
+	msgs   *[]string
+	fields map[string]any
+}
+
+func (m mockLog) record(msg string)                          { *m.msgs = append(*m.msgs, fmt.Sprint(msg, m.fields)) }
+func (m mockLog) Debug(msg string)                           { m.record(msg) }
+func (m mockLog) Info(msg string)                            { m.record(msg) }
+func (m mockLog) Warn(msg string)                            { m.record(msg) }
+func (m mockLog) Error(msg string)                           { m.record(msg) }
+func (m mockLog) DebugFields(msg string, _ ...lazylog.Field) { m.record(msg) }
+func (m mockLog) InfoFields(msg string, _ ...lazylog.Field)  { m.record(msg) }
+func (m mockLog) WarnFields(msg string, _ ...lazylog.Field)  { m.record(msg) }
+func (m mockLog) ErrorFields(msg string, _ ...lazylog.Field) { m.record(msg) }
+func (m mockLog) With(fields map[string]any) lazylog.Log {
+	return mockLog{msgs: m.msgs, fields: fields}
+}
+
+func TestLogInterface(t *testing.T) {
+	process := func(log lazylog.Log) {
+		log.With(map[string]any{"job": 7}).InfoFields("processed", lazylog.Field{Key: "items", Value: 3})
+	}
+
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}})
+	process(logger)
+	process(logger.ComFields(map[string]interface{}{"svc": "api"}))
+	process(lazylog.Nop())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %s", buf.String())
+	}
+	if !strings.Contains(lines[0], `"job":7`) || !strings.Contains(lines[0], `"items":3`) {
+		t.Errorf("logger entry missing fields: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"svc":"api"`) || !strings.Contains(lines[1], `"job":7`) {
+		t.Errorf("builder entry missing fields: %s", lines[1])
+	}
+
+	var msgs []string
+	process(mockLog{msgs: &msgs})
+	if len(msgs) != 1 || msgs[0] != "processedmap[job:7]" {
+		t.Errorf("unexpected mock calls: %v", msgs)
+	}
+}
+
+func TestLoggerSetLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.DEBUG, Formatter: &lazylog.JSONFormatter{}})
+	if logger.GetLevel() != lazylog.TRACE {
+		t.Errorf("expected default TRACE, got %v", logger.GetLevel())
+	}
+	hooked := 0
+	logger.AddHook(func(*lazylog.Entry) { hooked++ }, true)
+
+	logger.SetLevel(lazylog.WARN)
+	logger.WithField("k", 1).Info("dropped")
+	logger.Warn("kept")
+	if hooked != 1 || strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("expected only the WARN entry (hooks=%d): %s", hooked, buf.String())
+	}
+	if logger.GetLevel() != lazylog.WARN {
+		t.Errorf("expected WARN, got %v", logger.GetLevel())
+	}
+
+	logger.SetLevel(lazylog.DEBUG)
+	logger.Debug("visible")
+	if !strings.Contains(buf.String(), "visible") {
+		t.Error("lowering the logger level should re-enable DEBUG")
+	}
+}
+
+type failingWriter struct{ fail bool }
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.fail {
+		return 0, errors.New("disk full")
+	}
+	return len(p), nil
+}
+
+func TestTransportStatsAndHealth(t *testing.T) {
+	fw := &failingWriter{}
+	good := &lazylog.WriterTransport{Writer: io.Discard, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}}
+	flaky := &lazylog.WriterTransport{Writer: fw, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}}
+	logger := lazylog.NewLogger(good, &lazylog.TransportWithFilter{Transport: flaky})
+
+	logger.Info("one")
+	fw.fail = true
+	logger.Info("two")
+
+	reports := logger.Stats()
+	if len(reports) != 2 {
+		t.Fatalf("expected 2 reports, got %d", len(reports))
+	}
+	if s := reports[0].Stats; s.Writes != 2 || s.Errors != 0 || s.Bytes == 0 {
+		t.Errorf("unexpected stats for good transport: %+v", s)
+	}
+	s := reports[1].Stats
+	if s.Writes != 1 || s.Errors != 1 || s.LastError == nil || s.LastErrorAt.IsZero() {
+		t.Errorf("unexpected stats for flaky transport: %+v", s)
+	}
+	if err := logger.Health(); err == nil || !strings.Contains(err.Error(), "transport #1") {
+		t.Errorf("expected transport #1 to be unhealthy, got %v", err)
+	}
+
+	fw.fail = false
+	logger.Info("three")
+	if err := logger.Health(); err != nil {
+		t.Errorf("expected healthy after a successful write, got %v", err)
+	}
+}
+
+func TestTransportRecoveryDiagnostics(t *testing.T) {
+	diag := &bytes.Buffer{}
+	lazylog.SetDiagnostics(lazylog.NewLogger(&lazylog.WriterTransport{Writer: diag, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}}))
+	defer lazylog.SetDiagnostics(nil)
+
+	fw := &failingWriter{fail: true}
+	remote := &lazylog.WriterTransport{Writer: fw, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}}
+	logger := lazylog.NewLogger(lazylog.NewAsyncTransport(remote, 10, lazylog.OverflowBlock))
+	logger.Info("lost 1")
+	logger.Info("lost 2")
+	logger.Close()
+
+	fw.fail = false
+	remote.WriteLog(&lazylog.Entry{Level: lazylog.INFO, Message: "back"})
+	remote.WriteLog(&lazylog.Entry{Level: lazylog.INFO, Message: "still fine"})
+
+	lines := strings.Split(strings.TrimSpace(diag.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected a single recovery entry, got %q", diag.String())
+	}
+	for _, want := range []string{`transport recovered"`, `"dropped":2`, `"downtime_ms"`, `"last_error":"disk full"`, `"lazylog_internal":true`} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("recovery entry missing %s: %s", want, lines[0])
+		}
+	}
+	if s := remote.Stats(); s.Recoveries != 1 || s.LastDowntime <= 0 {
+		t.Errorf("unexpected recovery stats: %+v", s)
+	}
+}
+
+func TestConcurrentTransportAndHookMutation(t *testing.T) {
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: io.Discard, Level: lazylog.INFO})
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					logger.WithField("k", 1).Info("concurrent")
+				}
+			}
+		}()
+	}
+	for i := 0; i < 200; i++ {
+		a := &lazylog.WriterTransport{Writer: io.Discard, Level: lazylog.INFO}
+		b := &lazylog.WriterTransport{Writer: io.Discard, Level: lazylog.INFO}
+		logger.AddTransport(a)
+		logger.AddTransport(b)
+		logger.AddHook(func(e *lazylog.Entry) { e.Fields["hooked"] = true }, true)
+		logger.AddErrorHook(func(*lazylog.Entry, lazylog.Transport, error) {})
+		logger.RemoveTransport(a) // desloca b sobre a posição de a
+		logger.RemoveTransport(b)
+	}
+	close(stop)
+	wg.Wait()
+	if s := logger.Stats(); len(s) != 1 {
+		t.Errorf("expected only the original transport, got %d", len(s))
+	}
+}
+
+func TestChainFormatter(t *testing.T) {
+	f := lazylog.NewChainFormatter(&lazylog.JSONFormatter{},
+		lazylog.RedactFields("password"),
+		lazylog.AddFields(map[string]any{"service": "api", "user": "ignored"}),
+		lazylog.FlattenFields("."),
+	)
+	entry := &lazylog.Entry{
+		Level:      lazylog.INFO,
+		Message:    "login",
+		Fields:     map[string]interface{}{"user": "ana", "password": "s3cr3t", "http": map[string]interface{}{"status": 200, "method": "POST"}},
+		FieldOrder: []string{"user", "http", "password"},
+	}
+	out, err := f.Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `"user":"ana","http.method":"POST","http.status":200,"password":"[REDACTED]","service":"api"`
+	if !strings.Contains(string(out), want) {
+		t.Errorf("unexpected output:\n got %s\nwant %s", out, want)
+	}
+	if entry.Fields["password"] != "s3cr3t" || entry.Fields["service"] != nil {
+		t.Error("ChainFormatter mutated the original entry")
+	}
+}
+
+type closingTransport struct {
+	lazylog.WriterTransport
+	events   *[]string
+	name     string
+	closeErr error
+}
+
+func (c *closingTransport) Flush() error {
+	*c.events = append(*c.events, "flush "+c.name)
+	return nil
+}
+
+func (c *closingTransport) Close() error {
+	*c.events = append(*c.events, "close "+c.name)
+	return c.closeErr
+}
+
+func TestLoggerCloseFlushesAndJoinsErrors(t *testing.T) {
+	var events []string
+	a := &closingTransport{WriterTransport: lazylog.WriterTransport{Writer: io.Discard}, events: &events, name: "a", closeErr: errors.New("a failed")}
+	b := &closingTransport{WriterTransport: lazylog.WriterTransport{Writer: io.Discard}, events: &events, name: "b", closeErr: errors.New("b failed")}
+	logger := lazylog.NewLogger(a, &lazylog.TransportWithFilter{Transport: b})
+
+	err := logger.Close()
+	if err == nil || !strings.Contains(err.Error(), "a failed") || !strings.Contains(err.Error(), "b failed") {
+		t.Errorf("expected both close errors, got %v", err)
+	}
+	if got := strings.Join(events, ","); got != "flush a,flush b,close a,close b" {
+		t.Errorf("unexpected shutdown sequence: %s", got)
+	}
+	if err := logger.Close(); err != nil || len(events) != 4 {
+		t.Errorf("second Close should be a no-op, got %v (%v)", err, events)
+	}
+}
+
+type signalCloseTransport struct {
+	lazylog.WriterTransport
+	closed chan struct{}
+}
+
+func (s *signalCloseTransport) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func TestApplyConfigRetiresTransportsAfterGrace(t *testing.T) {
+	cfg := lazylog.LoggerConfig{Transports: []lazylog.TransportConfig{{Type: "console", Level: "INFO"}}}
+
+	// Durante a carência, o transporte antigo é descarregado mas continua
+	// aberto para logs em andamento; Close o fecha sem esperar a carência.
+	var events []string
+	old := &closingTransport{WriterTransport: lazylog.WriterTransport{Writer: io.Discard, Level: lazylog.INFO}, events: &events, name: "old"}
+	logger := lazylog.NewLogger(old)
+	logger.SetReloadGrace(time.Hour)
+	if err := logger.ApplyConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(events, ","); got != "flush old" {
+		t.Errorf("old transport must only be flushed during the grace period: %s", got)
+	}
+	if err := old.WriteLog(&lazylog.Entry{Level: lazylog.INFO, Message: "in flight"}); err != nil {
+		t.Errorf("in-flight write to the retired transport failed: %v", err)
+	}
+	start := time.Now()
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(events, ","); got != "flush old,close old" || time.Since(start) > time.Second {
+		t.Errorf("Close must close retired transports right away: %s (%v)", got, time.Since(start))
+	}
+
+	// Ao fim da carência, o transporte é fechado em segundo plano.
+	retired := &signalCloseTransport{closed: make(chan struct{})}
+	logger = lazylog.NewLogger(retired)
+	logger.SetReloadGrace(10 * time.Millisecond)
+	if err := logger.ApplyConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-retired.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("retired transport not closed after the grace period")
+	}
+	logger.Close()
+
+	// Sem carência, o fechamento é imediato.
+	events = nil
+	logger = lazylog.NewLogger(old)
+	logger.SetReloadGrace(0)
+	if err := logger.ApplyConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(events, ","); got != "flush old,close old" {
+		t.Errorf("grace 0 must close immediately: %s", got)
+	}
+	logger.Close()
+
+	// Depois do Close, a nova configuração é rejeitada.
+	if err := logger.ApplyConfig(cfg); !errors.Is(err, lazylog.ErrLoggerClosed) {
+		t.Errorf("expected ErrLoggerClosed after Close, got %v", err)
+	}
+}
+
+func TestPrometheusHookExemplars(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}})
+	metrics := lazylog.NewPrometheusHook("")
+	logger.AddHook(metrics.Hook, true)
+
+	logger.Info("no trace")
+	ctx := context.WithValue(context.Background(), lazylog.CtxKey("trace_id"), "4bf92f3577b34da6")
+	logger.ErrorCtx(ctx, "payment failed", nil)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if strings.Contains(lines[0], "metric_exemplar") || !strings.Contains(lines[1], `"metric_exemplar":true`) {
+		t.Errorf("metric_exemplar should only mark traced entries:\n%s", buf.String())
+	}
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE lazylog_entries counter\n",
+		"lazylog_entries_total{level=\"info\"} 1\n",
+		"lazylog_entries_total{level=\"error\"} 1 # {trace_id=\"4bf92f3577b34da6\"} 1 ",
+		"# EOF\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/openmetrics-text") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+}
+
+type slowWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(2 * time.Millisecond)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *slowWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestLoggerFlushDrainsAsyncQueues(t *testing.T) {
+	w := &slowWriter{}
+	async := lazylog.NewAsyncTransport(&lazylog.WriterTransport{Writer: w, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}}, 100, lazylog.OverflowBlock)
+	logger := lazylog.NewLogger(&lazylog.TransportWithFilter{Transport: async})
+	defer logger.Close()
+
+	for i := 0; i < 20; i++ {
+		logger.Info("queued")
+	}
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(w.String(), "queued"); n != 20 {
+		t.Errorf("expected 20 entries after Flush, got %d", n)
+	}
+	logger.Info("still open")
+	if err := logger.Flush(); err != nil || !strings.Contains(w.String(), "still open") {
+		t.Errorf("logger should keep working after Flush (err=%v)", err)
+	}
+}
+
+func TestErrorBudget(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}})
+	var alerts []lazylog.ErrorBudgetStatus
+	budget := logger.EnableErrorBudget(lazylog.ErrorBudgetConfig{
+		Window:     time.Minute,
+		Threshold:  0.2,
+		MinEntries: 5,
+		OnExceeded: func(s lazylog.ErrorBudgetStatus) { alerts = append(alerts, s) },
+	})
+	defer budget.Close()
+
+	for i := 0; i < 4; i++ {
+		logger.Info("ok")
+	}
+	logger.Error("failed")
+	if len(alerts) != 0 {
+		t.Fatalf("20%% errors should be within budget, got %+v", alerts)
+	}
+	logger.Error("failed again")
+	logger.Error("and again")
+	if len(alerts) != 1 {
+		t.Fatalf("expected a single alert after crossing the threshold, got %d", len(alerts))
+	}
+	s := budget.Status()
+	if s.Total != 7 || s.Errors != 3 || s.ByLevel[lazylog.INFO] != 4 || !s.Exceeded {
+		t.Errorf("unexpected status: %+v", s)
+	}
+
+	w := &slowWriter{}
+	summarized := lazylog.NewLogger(&lazylog.WriterTransport{Writer: w, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}})
+	periodic := summarized.EnableErrorBudget(lazylog.ErrorBudgetConfig{Threshold: 0.5, SummaryEvery: 5 * time.Millisecond})
+	summarized.Error("boom")
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(w.String(), "error budget summary") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	periodic.Close()
+	if out := w.String(); !strings.Contains(out, `"level":"WARN","message":"error budget summary"`) || !strings.Contains(out, `"by_level":{"ERROR":1}`) {
+		t.Errorf("expected a WARN summary with the histogram, got %s", out)
+	}
+}
+
+func TestEntryBuilderChaining(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.TextFormatter{}})
+	base := logger.ComFields(map[string]interface{}{"user": "cesar"})
+	base.WithFormatter(&lazylog.JSONFormatter{}).
+		ComFields(map[string]interface{}{"attempt": 2}).
+		WithError(errors.New("timeout")).
+		Warn("retrying")
+	out := buf.String()
+	for _, want := range []string{`"level":"WARN"`, `"user":"cesar"`, `"attempt":2`, `"error":"timeout"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("chained entry missing %s: %s", want, out)
+		}
+	}
+
+	buf.Reset()
+	base.Info("plain")
+	if out := buf.String(); strings.HasPrefix(out, "{") || strings.Contains(out, "attempt") {
+		t.Errorf("chaining must not mutate the original builder: %s", out)
+	}
+
+	buf.Reset()
+	logger.WithFormatter(&lazylog.JSONFormatter{}).ComFields(map[string]interface{}{"k": "v"}).Info("reverse order")
+	if out := buf.String(); !strings.Contains(out, `"k":"v"`) || !strings.HasPrefix(out, "{") {
+		t.Errorf("expected JSON entry with fields: %s", out)
+	}
+}
+
+func TestSampling(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.DEBUG, Formatter: &lazylog.TextFormatter{}})
+	logger.EnableSampling(2, 3, map[lazylog.Level]lazylog.SampleConfig{lazylog.ERROR: {}})
+	for i := 0; i < 10; i++ {
+		logger.WithField("i", i).Info("storm")
+		logger.Error("db down")
+	}
+	logger.Info("other")
+	out := buf.String()
+	// 1ª, 2ª, 5ª e 8ª ocorrências de "storm"; erros nunca são amostrados.
+	if n := strings.Count(out, "storm"); n != 4 {
+		t.Errorf("expected 4 sampled entries, got %d:\n%s", n, out)
+	}
+	for _, want := range []string{"i=0", "i=1", "i=4", "i=7"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected occurrence %s to be kept:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "db down"); n != 10 || !strings.Contains(out, "other") {
+		t.Errorf("errors and distinct messages must not be sampled:\n%s", out)
+	}
+	if got := logger.SampledOut(); got != 6 {
+		t.Errorf("expected 6 sampled out entries, got %d", got)
+	}
+
+	logger.DisableSampling()
+	buf.Reset()
+	for i := 0; i < 5; i++ {
+		logger.Info("storm")
+	}
+	if n := strings.Count(buf.String(), "storm"); n != 5 {
+		t.Errorf("expected sampling to be disabled, got %d entries", n)
+	}
+}
+
+func TestLogfmtFormatter(t *testing.T) {
+	entry := &lazylog.Entry{
+		Level:      lazylog.WARN,
+		Timestamp:  time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Message:    "slow query",
+		Fields:     map[string]interface{}{"table": "users", "elapsed_ms": 812, "msg": "forged", "tags": []interface{}{"a", "b"}, "sql": `select "x"`},
+		FieldOrder: []string{"table", "elapsed_ms"},
+	}
+	out, err := (&lazylog.LogfmtFormatter{}).Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `time=2025-03-01T12:00:00Z level=warn msg="slow query" table=users elapsed_ms=812 fields.msg=forged sql="select \"x\"" tags="[\"a\",\"b\"]"` + "\n"
+	if string(out) != want {
+		t.Errorf("unexpected logfmt:\n got %s\nwant %s", out, want)
+	}
+}
+
+func TestRingBufferDebugHandler(t *testing.T) {
+	ring := lazylog.NewRingBufferTransport(3, lazylog.DEBUG)
+	logger := lazylog.NewLogger(ring)
+	logger.Debug("boot")
+	logger.ComFields(map[string]interface{}{"user_id": 42}).Info("login")
+	logger.Warn("slow query")
+	logger.Error("db down")
+
+	records := ring.Records(0)
+	if len(records) != 3 || records[0].Entry.Message != "login" || records[2].Seq != 4 {
+		t.Fatalf("expected the last 3 entries in order, got %+v", records)
+	}
+
+	srv := httptest.NewServer(http.StripPrefix("/debug/logs", lazylog.DebugHandler(ring)))
+	defer srv.Close()
+	get := func(path string) (int, string) {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+	if code, body := get("/debug/logs/"); code != http.StatusOK || !strings.Contains(body, "<title>lazylog</title>") {
+		t.Errorf("expected the HTML UI, got %d", code)
+	}
+	_, body := get("/debug/logs/entries?level=WARN")
+	if !strings.Contains(body, "slow query") || !strings.Contains(body, "db down") || strings.Contains(body, "login") {
+		t.Errorf("unexpected level-filtered entries: %s", body)
+	}
+	_, body = get(`/debug/logs/entries?q=user_id%3D42`)
+	if !strings.Contains(body, `"seq":2`) || strings.Contains(body, "db down") {
+		t.Errorf("unexpected field search result: %s", body)
+	}
+	if code, _ := get("/debug/logs/entries?where=%3D%3D"); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid filter, got %d", code)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/debug/logs/tail?after=3&level=ERROR", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	logger.Info("ignored by the tail filter")
+	logger.Error("disk full")
+	sc := bufio.NewScanner(resp.Body)
+	var events []string
+	for sc.Scan() && len(events) < 2 {
+		if data, ok := strings.CutPrefix(sc.Text(), "data: "); ok {
+			events = append(events, data)
+		}
+	}
+	if len(events) != 2 || !strings.Contains(events[0], "db down") || !strings.Contains(events[1], "disk full") {
+		t.Errorf("unexpected tail events: %v", events)
+	}
+}
+
+func TestQueryHandler(t *testing.T) {
+	ring := lazylog.NewRingBufferTransport(100, lazylog.TRACE)
+	logger := lazylog.NewLogger(ring)
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	logger.LogAt(base, lazylog.TRACE, "tick", nil)
+	logger.LogAt(base.Add(time.Minute), lazylog.WARN, "slow query", map[string]interface{}{"user_id": 42})
+	logger.LogAt(base.Add(2*time.Minute), lazylog.ERROR, "query timeout", map[string]interface{}{"user_id": 42})
+	logger.LogAt(base.Add(3*time.Minute), lazylog.ERROR, "query timeout", map[string]interface{}{"user_id": 7})
+
+	entries, err := ring.Query(context.Background(), lazylog.Query{
+		MinLevel:    lazylog.WARN,
+		FieldEquals: map[string]string{"user_id": "42"},
+		TextSearch:  "QUERY",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Message != "query timeout" || entries[1].Message != "slow query" {
+		t.Errorf("expected newest first matches, got %+v", entries)
+	}
+	if entries, _ := ring.Query(context.Background(), lazylog.Query{From: base.Add(90 * time.Second), Limit: 1}); len(entries) != 1 || entries[0].Fields["user_id"] != 7 {
+		t.Errorf("expected the latest entry within the range, got %+v", entries)
+	}
+
+	srv := httptest.NewServer(lazylog.QueryHandler(ring))
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "?field=user_id:42&to=" + url.QueryEscape(base.Add(time.Minute).Format(time.RFC3339)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body struct {
+		Entries []lazylog.Entry `json:"entries"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+	resp.Body.Close()
+	if len(body.Entries) != 1 || body.Entries[0].Message != "slow query" || body.Entries[0].Level != lazylog.WARN {
+		t.Errorf("unexpected HTTP query result: %+v", body.Entries)
+	}
+	resp, err = http.Get(srv.URL + "?level=LOUD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown level, got %d", resp.StatusCode)
+	}
+}
+
+func logViaHelper(logger *lazylog.Logger, msg string) {
+	logger.Warn(msg)
+}
+
+func TestEnableCaller(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.DEBUG, Formatter: &lazylog.JSONFormatter{}})
+	logger.EnableCaller(0)
+	var calls []int
+	line := func() int {
+		_, _, l, _ := runtime.Caller(1)
+		return l
+	}
+
+	logger.Info("direct")
+	calls = append(calls, line()-1)
+	logger.ComFields(map[string]interface{}{"caller": "user value"}).WithField("k", 1).Warn("builder")
+	calls = append(calls, line()-1)
+	logger.InfoCtx(context.Background(), "ctx", nil)
+	calls = append(calls, line()-1)
+	logger.WithFields(map[string]interface{}{"a": 1}).Error("derived")
+	calls = append(calls, line()-1)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(calls) {
+		t.Fatalf("expected %d entries, got:\n%s", len(calls), buf.String())
+	}
+	for i, l := range lines {
+		want := fmt.Sprintf(`/lazylog_test.go:%d"`, calls[i])
+		if !strings.Contains(l, want) {
+			t.Errorf("entry %d: expected %s in %s", i, want, l)
+		}
+	}
+	if !strings.Contains(lines[1], `"fields.caller":"user value"`) {
+		t.Errorf("user caller field must not clash with the captured caller: %s", lines[1])
+	}
+
+	// skip=1 atribui a entry a quem chamou o helper.
+	buf.Reset()
+	logger.EnableCaller(1)
+	logViaHelper(logger, "via helper")
+	want := line() - 1
+	if !strings.Contains(buf.String(), fmt.Sprintf(`lazylog_test.go:%d"`, want)) {
+		t.Errorf("expected caller at line %d: %s", want, buf.String())
+	}
+
+	ring := lazylog.NewRingBufferTransport(1, lazylog.DEBUG)
+	logger.AddTransport(ring)
+	logger.EnableCaller(0)
+	logger.Info("captured")
+	c := ring.Records(0)[0].Entry.Caller
+	if c == nil || c.Function != "github.com/chmenegatti/lazylog_test.TestEnableCaller" || c.Package != "github.com/chmenegatti/lazylog_test" {
+		t.Errorf("unexpected caller: %+v", c)
+	}
+
+	buf.Reset()
+	logger.DisableCaller()
+	logger.Info("no caller")
+	if strings.Contains(buf.String(), `"caller"`) {
+		t.Errorf("caller must not be captured after DisableCaller: %s", buf.String())
+	}
+}
+
+func TestEnableFunction(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.DEBUG, Formatter: &lazylog.JSONFormatter{}})
+	logger.EnableFunction()
+	logger.WithFields(map[string]interface{}{"function": "user value"}).Info("json")
+	var m map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["function"] != "github.com/chmenegatti/lazylog_test.TestEnableFunction" || m["fields.function"] != "user value" {
+		t.Errorf("unexpected function fields: %v", m)
+	}
+	if _, ok := m["caller"]; ok {
+		t.Errorf("caller must not be written without EnableCaller: %v", m)
+	}
+
+	buf.Reset()
+	logger.EnableCaller(1)
+	logViaHelper(logger, "via helper")
+	if !strings.Contains(buf.String(), `"function":"github.com/chmenegatti/lazylog_test.TestEnableFunction"`) {
+		t.Errorf("function must honour the caller skip: %s", buf.String())
+	}
+
+	for _, f := range []lazylog.Formatter{&lazylog.TextFormatter{}, &lazylog.LogfmtFormatter{}} {
+		out, err := f.Format(&lazylog.Entry{Level: lazylog.INFO, Message: "m", Function: "main.run"})
+		if err != nil || !strings.Contains(string(out), "function=main.run") {
+			t.Errorf("%T: expected function=main.run, got %q (%v)", f, out, err)
+		}
+	}
+
+	e := lazylog.Entry{Level: lazylog.INFO, Message: "m", Function: "main.run"}
+	data, _ := json.Marshal(e)
+	var back lazylog.Entry
+	if err := json.Unmarshal(data, &back); err != nil || back.Function != "main.run" {
+		t.Errorf("function must survive the wire format: %+v (%v)", back, err)
+	}
+
+	buf.Reset()
+	logger.DisableCaller()
+	logger.DisableFunction()
+	logger.Info("plain")
+	if strings.Contains(buf.String(), `"function"`) {
+		t.Errorf("function must not be captured after DisableFunction: %s", buf.String())
+	}
+}
+
+func TestEnableProcessInfo(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}})
+	logger.SetAppInfo("billing", "1.4.2")
+	logger.EnableProcessInfo()
+	logger.WithField("app", "override").ComFields(map[string]interface{}{"pid": "explicit"}).Info("started")
+	var m map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	host, _ := os.Hostname()
+	if m["hostname"] != host || m["binary"] != filepath.Base(os.Args[0]) || m["app_version"] != "1.4.2" {
+		t.Errorf("missing process fields: %v", m)
+	}
+	if m["app"] != "override" || m["pid"] != "explicit" {
+		t.Errorf("bound and explicit fields must win over process fields: %v", m)
+	}
+
+	buf.Reset()
+	logger.Info("pid")
+	if !strings.Contains(buf.String(), fmt.Sprintf(`"pid":%d`, os.Getpid())) {
+		t.Errorf("expected pid field: %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.DisableProcessInfo()
+	logger.Info("plain")
+	if strings.Contains(buf.String(), `"hostname"`) {
+		t.Errorf("process fields must not be added after DisableProcessInfo: %s", buf.String())
+	}
+}
+
+func TestConfigProfiles(t *testing.T) {
+	cfg, err := lazylog.ParseLoggerConfig([]byte(`
+Transports:
+  - {Name: main, Type: console, Level: DEBUG, Options: {stderr: true, locale: pt-BR}}
+PackageLevels: "...=DEBUG"
+Profiles:
+  staging:
+    Transports:
+      - {Name: main, Level: INFO}
+  prod:
+    Extends: staging
+    PackageLevels: "...=WARN"
+    Transports:
+      - {Name: main, Formatter: json, Options: {stderr: false}}
+      - {Type: file, Level: ERROR, Options: {path: "${LAZYLOG_TEST_DIR:-/tmp}/errors.log"}}
+  loop:
+    Extends: loop
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(lazylog.ConfigProfileEnv, "prod")
+	t.Setenv("LAZYLOG_TEST_DIR", "/var/log/app")
+	prod, err := cfg.Resolve("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prod.Transports) != 2 || prod.PackageLevels != "...=WARN" {
+		t.Fatalf("unexpected prod config: %+v", prod)
+	}
+	console := prod.Transports[0]
+	if console.Type != "console" || console.Level != "INFO" || console.Formatter != "json" ||
+		console.Options["stderr"] != false || console.Options["locale"] != "pt-BR" {
+		t.Errorf("inherited transport not merged: %+v", console)
+	}
+	if prod.Transports[1].Options["path"] != "/var/log/app/errors.log" {
+		t.Errorf("env var not interpolated: %v", prod.Transports[1].Options)
+	}
+	if cfg.Transports[0].Level != "DEBUG" || cfg.Transports[0].Options["stderr"] != true {
+		t.Errorf("base config must not be modified: %+v", cfg.Transports[0])
+	}
+
+	if staging, err := cfg.Resolve("staging"); err != nil || len(staging.Transports) != 1 || staging.Transports[0].Level != "INFO" {
+		t.Errorf("explicit profile must win over the env var: %+v (%v)", staging, err)
+	}
+	if _, err := cfg.Resolve("loop"); err == nil {
+		t.Error("expected error for a profile that extends itself")
+	}
+	if _, err := cfg.Resolve("qa"); err == nil {
+		t.Error("expected error for an unknown profile")
+	}
+
+	bad := lazylog.LoggerConfig{Transports: []lazylog.TransportConfig{{Type: "console", Level: "${LAZYLOG_TEST_UNSET}"}}}
+	t.Setenv(lazylog.ConfigProfileEnv, "")
+	if _, err := bad.Resolve(""); err == nil || !strings.Contains(err.Error(), "LAZYLOG_TEST_UNSET") {
+		t.Errorf("expected error for unset env var, got %v", err)
+	}
+	if _, err := lazylog.NewLoggerFromConfig(bad); err == nil {
+		t.Error("NewLoggerFromConfig must resolve the config")
+	}
+}
+
+func TestSetDefaultFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}})
+	defaults := map[string]any{"env": "prod", "region": "us-east-1", "service": "billing", "hostname": "static"}
+	logger.SetDefaultFields(defaults)
+	defaults["env"] = "mutated"
+	logger.EnableProcessInfo()
+	var seen map[string]any
+	logger.AddHook(func(e *lazylog.Entry) { seen = e.Fields }, true)
+
+	logger.WithField("service", "bound").Info("started")
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["env"] != "prod" || m["region"] != "us-east-1" || m["service"] != "bound" || m["hostname"] != "static" {
+		t.Errorf("unexpected default fields: %v", m)
+	}
+	if _, ok := m["pid"]; !ok {
+		t.Errorf("process fields must still be present: %v", m)
+	}
+	if seen["env"] != "prod" {
+		t.Errorf("default fields must be visible to before-hooks: %v", seen)
+	}
+
+	buf.Reset()
+	logger.SetDefaultFields(nil)
+	logger.Info("cleared")
+	if strings.Contains(buf.String(), `"env"`) {
+		t.Errorf("default fields must be removed: %s", buf.String())
+	}
+}
+
+func TestScheduledTransport(t *testing.T) {
+	s, err := lazylog.ParseSchedule("mon-fri 08:00-20:00; sat 22:00-02:00; 2025-03-09T10:00/2025-03-09T11:00", "America/Sao_Paulo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc, _ := time.LoadLocation("America/Sao_Paulo")
+	cases := []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2025, 3, 5, 8, 0, 0, 0, loc), true},         // quarta, início
+		{time.Date(2025, 3, 5, 20, 0, 0, 0, loc), false},       // quarta, fim (exclusivo)
+		{time.Date(2025, 3, 5, 12, 0, 0, 0, time.UTC), true},   // 09:00 em São Paulo
+		{time.Date(2025, 3, 5, 23, 30, 0, 0, time.UTC), false}, // 20:30 em São Paulo
+		{time.Date(2025, 3, 8, 12, 0, 0, 0, loc), false},       // sábado
+		{time.Date(2025, 3, 8, 23, 0, 0, 0, loc), true},        // sábado à noite
+		{time.Date(2025, 3, 9, 1, 0, 0, 0, loc), true},         // madrugada de domingo (janela de sábado)
+		{time.Date(2025, 3, 10, 1, 0, 0, 0, loc), false},       // madrugada de segunda
+		{time.Date(2025, 3, 9, 10, 30, 0, 0, loc), true},       // janela absoluta
+		{time.Date(2025, 3, 9, 11, 0, 0, 0, loc), false},       // fim da janela absoluta
+	}
+	for _, c := range cases {
+		if got := s.Active(c.at); got != c.want {
+			t.Errorf("Active(%v) = %v, want %v", c.at, got, c.want)
+		}
+	}
+	for _, bad := range []string{"", "mon-xyz 08:00-20:00", "08:00", "25:00-26:00", "2025-03-09T11:00/2025-03-09T10:00"} {
+		if _, err := lazylog.ParseSchedule(bad, ""); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+	if _, err := lazylog.ParseSchedule("08:00-20:00", "Mars/Olympus"); err == nil {
+		t.Error("expected error for unknown timezone")
+	}
+
+	buf := &bytes.Buffer{}
+	tr := &lazylog.ScheduledTransport{
+		Transport: &lazylog.WriterTransport{Writer: buf, Level: lazylog.INFO, Formatter: &lazylog.TextFormatter{}},
+		Schedule:  s,
+	}
+	tr.WriteLog(&lazylog.Entry{Level: lazylog.ERROR, Timestamp: time.Date(2025, 3, 8, 12, 0, 0, 0, loc), Message: "weekend"})
+	tr.WriteLog(&lazylog.Entry{Level: lazylog.ERROR, Timestamp: time.Date(2025, 3, 7, 12, 0, 0, 0, loc), Message: "friday"})
+	if strings.Contains(buf.String(), "weekend") || !strings.Contains(buf.String(), "friday") {
+		t.Errorf("unexpected scheduled output: %q", buf.String())
+	}
+
+	cfg := lazylog.LoggerConfig{Transports: []lazylog.TransportConfig{{Type: "console", Level: "INFO", Schedule: "mon-fri 08:00-20:00", Timezone: "America/Sao_Paulo"}}}
+	if _, err := lazylog.NewLoggerFromConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Transports[0].Schedule = "weekdays"
+	if _, err := lazylog.NewLoggerFromConfig(cfg); err == nil {
+		t.Error("expected error for an invalid Schedule in the config")
+	}
+}
+
+func TestCostGuardTransport(t *testing.T) {
+	diag := &bytes.Buffer{}
+	lazylog.SetDiagnostics(lazylog.NewLogger(&lazylog.WriterTransport{Writer: diag, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}}))
+	defer lazylog.SetDiagnostics(nil)
+
+	buf := &bytes.Buffer{}
+	guard, err := lazylog.NewCostGuardTransport(
+		&lazylog.WriterTransport{Writer: buf, Level: lazylog.DEBUG, Formatter: &lazylog.TextFormatter{}},
+		"vendor", lazylog.CostGuardConfig{MaxEntriesPerDay: 10, SampleRate: 1e-9},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := day
+	guard.Now = func() time.Time { return now }
+	write := func(level lazylog.Level, msg string, at time.Time) {
+		if err := guard.WriteLog(&lazylog.Entry{Level: level, Timestamp: at, Message: msg}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 8; i++ {
+		write(lazylog.INFO, "normal", day)
+	}
+	if guard.Stage() != lazylog.CostSampling {
+		t.Fatalf("expected sampling at 80%% of the budget, got %v", guard.Stage())
+	}
+	write(lazylog.INFO, "sampled out", day)
+	write(lazylog.ERROR, "error 1", day)
+	write(lazylog.ERROR, "error 2", day)
+	if guard.Stage() != lazylog.CostErrorsOnly {
+		t.Fatalf("expected errors-only after the budget, got %v", guard.Stage())
+	}
+	write(lazylog.WARN, "dropped", day)
+	write(lazylog.ERROR, "error 3", day)
+	if strings.Contains(buf.String(), "sampled out") || strings.Contains(buf.String(), "dropped") || !strings.Contains(buf.String(), "error 3") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+	if _, entries, rejected := guard.Usage(); entries != 11 || rejected != 2 {
+		t.Errorf("unexpected usage: entries=%d rejected=%d", entries, rejected)
+	}
+	for _, stage := range []string{`"stage":"sampling"`, `"stage":"errors-only"`} {
+		if !strings.Contains(diag.String(), stage) || !strings.Contains(diag.String(), `"transport":"vendor"`) {
+			t.Errorf("missing diagnostic for %s: %s", stage, diag.String())
+		}
+	}
+
+	// Entries retroativas ou futuras não mudam o dia do orçamento.
+	write(lazylog.WARN, "backfilled", day.Add(-48*time.Hour))
+	write(lazylog.WARN, "future", day.Add(48*time.Hour))
+	if guard.Stage() != lazylog.CostErrorsOnly || strings.Contains(buf.String(), "backfilled") || strings.Contains(buf.String(), "future") {
+		t.Errorf("entry timestamps must not reset the budget (stage %v):\n%s", guard.Stage(), buf.String())
+	}
+
+	now = day.Add(24 * time.Hour)
+	write(lazylog.INFO, "next day", day.Add(-time.Hour))
+	if guard.Stage() != lazylog.CostNormal || !strings.Contains(buf.String(), "next day") {
+		t.Errorf("budget must reset on the next day (stage %v)", guard.Stage())
+	}
+	if !strings.Contains(diag.String(), "cost budget reset") {
+		t.Errorf("missing reset diagnostic: %s", diag.String())
+	}
+
+	if _, err := lazylog.NewCostGuardTransport(guard, "x", lazylog.CostGuardConfig{}); err == nil {
+		t.Error("expected error for a budget without limits")
+	}
+	cfg := lazylog.LoggerConfig{Transports: []lazylog.TransportConfig{{Type: "console", Level: "INFO", CostGuard: &lazylog.CostGuardConfig{MaxBytesPerDay: 1 << 30, Timezone: "Mars/Olympus"}}}}
+	if _, err := lazylog.NewLoggerFromConfig(cfg); err == nil {
+		t.Error("expected error for an invalid CostGuard timezone")
+	}
+}
+
+func TestNewLogrusCompatible(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lazylog.New()
+	logger.Out = buf
+	logger.Formatter = &lazylog.JSONFormatter{}
+	logger.Debug("hidden")
+	logger.WithField("user", "cesar").Info("hello")
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("expected a single JSON entry (DEBUG filtered by default): %v (%s)", err, buf.String())
+	}
+	if m["message"] != "hello" || m["user"] != "cesar" {
+		t.Errorf("unexpected entry: %v", m)
+	}
+
+	other := &bytes.Buffer{}
+	logger.SetOutput(other)
+	logger.SetFormatter(&lazylog.TextFormatter{})
+	logger.Warn("switched")
+	if !strings.Contains(other.String(), "[WARN] switched") || strings.Contains(buf.String(), "switched") {
+		t.Errorf("output not switched: %q / %q", other.String(), buf.String())
+	}
+	if stats := logger.Stats(); len(stats) != 1 || stats[0].Stats.Writes != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// Em loggers de NewLogger, SetOutput instala a saída em Out uma única vez,
+	// ao lado dos transportes existentes.
+	existing := &bytes.Buffer{}
+	plain := lazylog.NewLogger(&lazylog.WriterTransport{Writer: existing, Level: lazylog.INFO})
+	plain.SetFormatter(&lazylog.JSONFormatter{})
+	first, second := &bytes.Buffer{}, &bytes.Buffer{}
+	plain.SetOutput(first)
+	plain.SetOutput(second)
+	plain.Info("both")
+	if !strings.Contains(second.String(), `"message":"both"`) || first.Len() != 0 || !strings.Contains(existing.String(), "both") {
+		t.Errorf("SetOutput must install a single Out transport: %q / %q / %q", first.String(), second.String(), existing.String())
+	}
+	if stats := plain.Stats(); len(stats) != 2 {
+		t.Errorf("expected the existing transport plus Out, got %+v", stats)
+	}
+}
+
+func TestVolumeTracking(t *testing.T) {
+	volume := lazylog.NewVolumeTracker(3)
+	buf := &bytes.Buffer{}
+	inner := &lazylog.WriterTransport{Writer: buf, Level: lazylog.DEBUG, Formatter: &lazylog.TextFormatter{}}
+	logger := lazylog.NewLogger(&lazylog.VolumeTransport{Transport: inner, Tracker: volume})
+
+	for i := 0; i < 5; i++ {
+		logger.Info(fmt.Sprintf("user %d logged in", i))
+	}
+	logger.Warn("cache miss for key " + strings.Repeat("x", 500))
+	logger.Info("a")
+	logger.Info("b")
+	logger.Info("c")
+
+	top := volume.TopK(0)
+	if len(top) != 4 {
+		t.Fatalf("expected 3 templates plus %q, got %+v", lazylog.OtherTemplates, top)
+	}
+	if !strings.HasPrefix(top[0].Template, "cache miss") || top[0].Entries != 1 {
+		t.Errorf("largest template must come first: %+v", top)
+	}
+	var users, other lazylog.TemplateVolume
+	for _, tv := range top {
+		switch tv.Template {
+		case "user <n> logged in":
+			users = tv
+		case lazylog.OtherTemplates:
+			other = tv
+		}
+	}
+	if users.Entries != 5 || other.Entries != 2 {
+		t.Errorf("unexpected template counts: %+v", top)
+	}
+	var sum uint64
+	for _, tv := range top {
+		sum += tv.Bytes
+	}
+	stats := logger.Stats()
+	if len(stats) != 1 || stats[0].Stats.Bytes != sum || stats[0].Stats.Bytes != uint64(buf.Len()) {
+		t.Errorf("template bytes (%d) must match the transport bytes: %+v", sum, stats)
+	}
+	if largest := stats[0].Stats.MaxEntry; largest < 500 || largest > uint64(buf.Len()) {
+		t.Errorf("unexpected MaxEntry %d", largest)
+	}
+	if got := volume.TopK(1); len(got) != 1 || got[0] != top[0] {
+		t.Errorf("TopK(1) = %+v", got)
+	}
+	if text := volume.Text(2); !strings.Contains(text, `lazylog_template_entries_total{template="user <n> logged in"} 5`) {
+		t.Errorf("unexpected volume metrics:\n%s", text)
+	}
+
+	rec := httptest.NewRecorder()
+	logger.StatsHandler("app").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if want := fmt.Sprintf(`app_transport_bytes_total{index="0",transport="*lazylog.VolumeTransport"} %d`, buf.Len()); !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("missing %q in:\n%s", want, rec.Body.String())
+	}
+
+	volume.Reset()
+	if len(volume.TopK(0)) != 0 {
+		t.Error("Reset must clear the templates")
+	}
+}
+
+func TestIsLevelEnabled(t *testing.T) {
+	logger := lazylog.NewLogger()
+	logger.AddTransport(&lazylog.WriterTransport{Writer: io.Discard, Level: lazylog.WARN, Formatter: &lazylog.JSONFormatter{}})
+
+	if logger.IsLevelEnabled(lazylog.INFO) || !logger.IsLevelEnabled(lazylog.WARN) {
+		t.Error("IsLevelEnabled must follow the transports' level")
+	}
+	logger.SetLevel(lazylog.ERROR)
+	if logger.IsLevelEnabled(lazylog.WARN) || !logger.IsLevelEnabled(lazylog.ERROR) {
+		t.Error("IsLevelEnabled must follow SetLevel")
+	}
+	if child := logger.Named("db"); child.IsLevelEnabled(lazylog.WARN) || !child.IsLevelEnabled(lazylog.FATAL) {
+		t.Error("derived loggers must share the level checks")
+	}
+
+	var nilLogger *lazylog.Logger
+	if nilLogger.IsLevelEnabled(lazylog.FATAL) {
+		t.Error("a nil logger must report every level as disabled")
+	}
+}
+
+func TestNoisyTracker(t *testing.T) {
+	noisy := lazylog.NewNoisyTracker(2)
+	logger := lazylog.NewLogger()
+	logger.AddTransport(&lazylog.WriterTransport{Writer: io.Discard, Level: lazylog.DEBUG, Formatter: &lazylog.JSONFormatter{}})
+	logger.EnableCaller(0)
+	logger.AddHook(noisy.Hook(), false)
+
+	for i := 0; i < 3; i++ {
+		logger.Debug(fmt.Sprintf("retrying request %d", i))
+	}
+	logger.Info("started")
+	logger.Warn("overflow")
+
+	if noisy.Total() != 5 {
+		t.Errorf("expected 5 entries, got %d", noisy.Total())
+	}
+	top := noisy.Top(0)
+	if len(top) != 3 {
+		t.Fatalf("expected 2 sites plus %s, got %+v", lazylog.OtherTemplates, top)
+	}
+	if top[0].Template != "retrying request <n>" || top[0].Entries != 3 || !strings.Contains(top[0].Caller, "lazylog_test.go:") {
+		t.Errorf("unexpected noisiest site: %+v", top[0])
+	}
+	if other := top[1]; other.Template != lazylog.OtherTemplates || other.Level != lazylog.WARN || top[2].Template != "started" {
+		t.Errorf("sites beyond MaxSites must be grouped: %+v", top)
+	}
+
+	var report strings.Builder
+	if err := noisy.Report(&report, 1); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(report.String(), `60.0% DEBUG `) || strings.Count(report.String(), "\n") != 1 {
+		t.Errorf("unexpected report: %q", report.String())
+	}
+
+	noisy.Reset()
+	if noisy.Total() != 0 || len(noisy.Top(0)) != 0 {
+		t.Error("Reset must clear the counters")
+	}
+}
+
+func TestTypedFieldConstructors(t *testing.T) {
+	var buf bytes.Buffer
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: &buf, Level: lazylog.DEBUG, Formatter: &lazylog.JSONFormatter{}})
+
+	logger.ErrorFields("save failed",
+		lazylog.String("user", "cesar"),
+		lazylog.Int("attempt", 2),
+		lazylog.Int64("size", 1<<40),
+		lazylog.Bool("retry", true),
+		lazylog.Float64("ratio", 0.5),
+		lazylog.Err(errors.New("disk full")),
+		lazylog.Err(nil),
+		lazylog.String("quoted", `a "b" <c> é`),
+		lazylog.Any("tags", []string{"x"}),
+	)
+	want := `"message":"save failed","user":"cesar","attempt":2,"size":1099511627776,"retry":true,"ratio":0.5,"error":"disk full","quoted":"a \"b\" \u003cc\u003e é","tags":["x"]}`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("unexpected output:\n%s\nwant suffix %s", buf.String(), want)
+	}
+
+	buf.Reset()
+	logger.ComFields(map[string]any{"job": 7}).InfoFields("done", lazylog.Err(nil), lazylog.Duration("took", 0))
+	if strings.Contains(buf.String(), `"":`) || !strings.Contains(buf.String(), `"job":7`) || !strings.Contains(buf.String(), `"took":0`) {
+		t.Errorf("Err(nil) must be skipped: %s", buf.String())
+	}
+}
+
+type recordingBatchWriter struct {
+	batches [][]byte
+	counts  []int
+	fail    error
+	closed  bool
+}
+
+func (w *recordingBatchWriter) WriteBatch(batch []byte, entries int) error {
+	if w.fail != nil {
+		return w.fail
+	}
+	w.batches = append(w.batches, bytes.Clone(batch))
+	w.counts = append(w.counts, entries)
+	return nil
+}
+
+func (w *recordingBatchWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestBatchTransport(t *testing.T) {
+	w := &recordingBatchWriter{}
+	batch := lazylog.NewBatchTransport(w, lazylog.INFO, 300, 0)
+	logger := lazylog.NewLogger(batch)
+
+	for i := 0; i < 5; i++ {
+		logger.InfoFields("request served", lazylog.Int("i", i), lazylog.String("route", "/api/orders"))
+	}
+	if len(w.batches) != 1 || w.counts[0] != 3 || strings.Count(string(w.batches[0]), "\n") != 3 {
+		t.Fatalf("expected one full batch of 3 entries, got %d batches %v", len(w.batches), w.counts)
+	}
+	if err := logger.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.batches) != 2 || w.counts[1] != 2 || !strings.Contains(string(w.batches[1]), `"i":4,"route":"/api/orders"}`+"\n") {
+		t.Fatalf("Flush must deliver the pending entries: %v\n%s", w.counts, w.batches)
+	}
+	if err := batch.Flush(); err != nil || len(w.batches) != 2 {
+		t.Errorf("an empty Flush must not deliver a batch: %v", err)
+	}
+
+	// O buffer entregue é reutilizado; o conteúdo do lote seguinte não pode
+	// misturar entries do anterior.
+	logger.Info("third")
+	logger.Flush()
+	if got := string(w.batches[2]); strings.Count(got, "\n") != 1 || !strings.Contains(got, `"message":"third"`) {
+		t.Errorf("unexpected reused batch: %s", got)
+	}
+	if stats := batch.Stats(); stats.Writes != 3 {
+		t.Errorf("Stats must count batches, got %d", stats.Writes)
+	}
+
+	w.fail = errors.New("broker down")
+	logger.Info("lost")
+	if err := batch.Flush(); err == nil || err.Error() != "broker down" {
+		t.Errorf("expected the WriteBatch error, got %v", err)
+	}
+	if err := logger.Close(); err != nil || !w.closed {
+		t.Errorf("Close must flush and close the writer: %v", err)
+	}
+	delivered := len(w.batches)
+	if err := batch.WriteLog(&lazylog.Entry{Level: lazylog.INFO, Message: "late"}); err == nil {
+		t.Error("WriteLog after Close must return an error")
+	}
+	if err := batch.Flush(); err != nil || len(w.batches) != delivered {
+		t.Errorf("entries written after Close must not be delivered: %v", err)
+	}
+}
+
+func TestSugaredKeyValues(t *testing.T) {
+	var buf bytes.Buffer
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: &buf, Level: lazylog.INFO, Formatter: &lazylog.JSONFormatter{}})
+
+	logger.Infow("order created", "order_id", 42, "customer", "acme", lazylog.Bool("vip", true))
+	if want := `"message":"order created","order_id":42,"customer":"acme","vip":true}`; !strings.Contains(buf.String(), want) {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.Named("db").Errorw("query failed", 7, "x", "dangling")
+	if want := `"7":"x","!BADKEY":"dangling"`; !strings.Contains(buf.String(), want) {
+		t.Errorf("odd arguments must be kept under %s: %s", lazylog.BadKey, buf.String())
+	}
+
+	buf.Reset()
+	logger.Debugw("hidden", "k", 1)
+	if buf.Len() != 0 {
+		t.Errorf("disabled level must not log: %s", buf.String())
+	}
+	if allocs := testing.AllocsPerRun(100, func() { logger.Debugw("hidden", "k", "v") }); allocs != 0 {
+		t.Errorf("disabled Debugw allocated %v times", allocs)
+	}
+}
+
+func TestPrintCompatibility(t *testing.T) {
+	var buf bytes.Buffer
+	logger := lazylog.NewLogger(&lazylog.WriterTransport{Writer: &buf, Level: lazylog.DEBUG, Formatter: &lazylog.JSONFormatter{}})
+
+	var std lazylog.StdLogger = logger
+	std.Print("a", 1, 2, "b")
+	std.Println("a", 1)
+	std.Printf("retry %d of %d", 2, 3)
+	for _, want := range []string{`"level":"INFO","message":"a1 2b"`, `"level":"INFO","message":"a 1"`, `"level":"INFO","message":"retry 2 of 3"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("missing %s in:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	logger.SetPrintLevel(lazylog.DEBUG)
+	logger.WithFields(map[string]any{"lib": "gorm"}).Printf("slow query: %s", "SELECT 1")
+	if want := `"level":"DEBUG","message":"slow query: SELECT 1","lib":"gorm"`; !strings.Contains(buf.String(), want) {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.SetLevel(lazylog.INFO)
+	logger.Println("hidden")
+	if buf.Len() != 0 {
+		t.Errorf("Print must respect the logger level: %s", buf.String())
+	}
+
+	var nilLogger *lazylog.Logger
+	nilLogger.Printf("x %d", 1)
+}
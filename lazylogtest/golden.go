@@ -0,0 +1,91 @@
+// Package lazylogtest oferece utilitários de teste: um fake de lazylog.Log
+// (MockLog) e a verificação de Formatters (inclusive implementações próprias)
+// contra arquivos golden.
+package lazylogtest
+
+import (
+	"bytes"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chmenegatti/lazylog"
+)
+
+// update regrava os arquivos golden: go test ./... -lazylog.update
+var update = flag.Bool("lazylog.update", false, "regrava os arquivos golden do lazylogtest")
+
+// Clock é o instante fixo usado em todas as entries de GoldenEntries.
+var Clock = time.Date(2024, 1, 2, 3, 4, 5, 600000000, time.UTC)
+
+// GoldenEntries retorna um conjunto determinístico de entries que cobre todos
+// os níveis, mensagens vazias/multilinha/unicode, campos aninhados e valores
+// especiais. Os campos têm ordem fixa (Entry.FieldOrder).
+func GoldenEntries() []*lazylog.Entry {
+	entry := func(level lazylog.Level, msg string, fields ...lazylog.Field) *lazylog.Entry {
+		e := &lazylog.Entry{Level: level, Timestamp: Clock, Message: msg}
+		if len(fields) > 0 {
+			e.Fields = make(map[string]interface{}, len(fields))
+			for _, f := range fields {
+				e.Fields[f.Key] = f.Value
+				e.FieldOrder = append(e.FieldOrder, f.Key)
+			}
+		}
+		return e
+	}
+	return []*lazylog.Entry{
+		entry(lazylog.DEBUG, "debug message"),
+		entry(lazylog.INFO, "user logged in", lazylog.Field{Key: "user_id", Value: 42}, lazylog.Field{Key: "admin", Value: false}),
+		entry(lazylog.WARN, "disk almost full", lazylog.Field{Key: "usage", Value: 0.93}),
+		entry(lazylog.ERROR, "request failed", lazylog.Field{Key: "error", Value: "connection refused"},
+			lazylog.Field{Key: "request", Value: map[string]interface{}{"method": "GET", "path": "/api"}}),
+		entry(lazylog.INFO, ""),
+		entry(lazylog.INFO, "multi\nline\tmessage"),
+		entry(lazylog.INFO, "unicode: çãõ 日本語 🚀", lazylog.Field{Key: "emoji", Value: "✅"}),
+		entry(lazylog.WARN, "special values", lazylog.Field{Key: "nil", Value: nil},
+			lazylog.Field{Key: "nan", Value: math.NaN()}, lazylog.Field{Key: "big", Value: int64(math.MaxInt64)}),
+	}
+}
+
+// Render formata GoldenEntries com f e concatena as saídas.
+func Render(f lazylog.Formatter) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, e := range GoldenEntries() {
+		out, err := f.Format(e)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(out)
+	}
+	return buf.Bytes(), nil
+}
+
+// AssertGolden compara a saída de Render(f) com o arquivo golden em path
+// (ex: "testdata/myformatter.golden"). Com -lazylog.update, o arquivo é
+// (re)gravado em vez de comparado.
+func AssertGolden(t testing.TB, f lazylog.Formatter, path string) {
+	t.Helper()
+	got, err := Render(f)
+	if err != nil {
+		t.Fatalf("lazylogtest: format: %v", err)
+	}
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("lazylogtest: %v (rode com -lazylog.update para criar)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("lazylogtest: output differs from %s (rode com -lazylog.update para atualizar)\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,13 @@
+package lazylogtest_test
+
+import (
+	"testing"
+
+	"github.com/chmenegatti/lazylog"
+	"github.com/chmenegatti/lazylog/lazylogtest"
+)
+
+func TestBuiltinFormattersGolden(t *testing.T) {
+	lazylogtest.AssertGolden(t, &lazylog.TextFormatter{}, "testdata/text.golden")
+	lazylogtest.AssertGolden(t, &lazylog.JSONFormatter{}, "testdata/json.golden")
+}
@@ -0,0 +1,215 @@
+package lazylogtest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/chmenegatti/lazylog"
+)
+
+// Call é uma chamada registrada por MockLog.
+type Call struct {
+	Level   lazylog.Level
+	Message string
+	Fields  map[string]any // campos de With mais os campos da chamada
+}
+
+// MockLog é um fake de lazylog.Log que registra as chamadas e verifica
+// expectativas, para testar código que recebe um lazylog.Log:
+//
+//	log := lazylogtest.NewMockLog()
+//	log.ExpectWarn("slow query").WithField("table", "users")
+//	repo := NewRepo(log)
+//	repo.Find(ctx, 42)
+//	log.AssertExpectations(t)
+//
+// Loggers derivados via With compartilham as chamadas e expectativas com o
+// MockLog original. É seguro para uso concorrente.
+type MockLog struct {
+	state  *mockState
+	fields map[string]any
+}
+
+type mockState struct {
+	mu           sync.Mutex
+	calls        []Call
+	expectations []*Expectation
+}
+
+var _ lazylog.Log = (*MockLog)(nil)
+
+// NewMockLog cria um MockLog sem chamadas nem expectativas.
+func NewMockLog() *MockLog {
+	return &MockLog{state: &mockState{}}
+}
+
+func (m *MockLog) record(level lazylog.Level, msg string, fields []lazylog.Field) {
+	flds := make(map[string]any, len(m.fields)+len(fields))
+	for k, v := range m.fields {
+		flds[k] = v
+	}
+	for _, f := range fields {
+		if f.Key != "" {
+			flds[f.Key] = f.Value
+		}
+	}
+	m.state.mu.Lock()
+	defer m.state.mu.Unlock()
+	m.state.calls = append(m.state.calls, Call{Level: level, Message: msg, Fields: flds})
+}
+
+func (m *MockLog) Debug(msg string) { m.record(lazylog.DEBUG, msg, nil) }
+func (m *MockLog) Info(msg string)  { m.record(lazylog.INFO, msg, nil) }
+func (m *MockLog) Warn(msg string)  { m.record(lazylog.WARN, msg, nil) }
+func (m *MockLog) Error(msg string) { m.record(lazylog.ERROR, msg, nil) }
+
+func (m *MockLog) DebugFields(msg string, fields ...lazylog.Field) {
+	m.record(lazylog.DEBUG, msg, fields)
+}
+func (m *MockLog) InfoFields(msg string, fields ...lazylog.Field) {
+	m.record(lazylog.INFO, msg, fields)
+}
+func (m *MockLog) WarnFields(msg string, fields ...lazylog.Field) {
+	m.record(lazylog.WARN, msg, fields)
+}
+func (m *MockLog) ErrorFields(msg string, fields ...lazylog.Field) {
+	m.record(lazylog.ERROR, msg, fields)
+}
+
+// With retorna um MockLog derivado que inclui os campos em toda chamada.
+func (m *MockLog) With(fields map[string]any) lazylog.Log {
+	merged := make(map[string]any, len(m.fields)+len(fields))
+	for k, v := range m.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &MockLog{state: m.state, fields: merged}
+}
+
+// Calls retorna uma cópia das chamadas registradas, em ordem.
+func (m *MockLog) Calls() []Call {
+	m.state.mu.Lock()
+	defer m.state.mu.Unlock()
+	return append([]Call(nil), m.state.calls...)
+}
+
+// Reset descarta as chamadas e expectativas registradas.
+func (m *MockLog) Reset() {
+	m.state.mu.Lock()
+	defer m.state.mu.Unlock()
+	m.state.calls = nil
+	m.state.expectations = nil
+}
+
+// Expectation é uma chamada esperada, criada por MockLog.Expect*.
+type Expectation struct {
+	level   lazylog.Level
+	message string
+	fields  map[string]any
+	times   int // 0: pelo menos uma vez
+}
+
+// WithField exige que a chamada tenha o campo com o valor informado
+// (comparado com reflect.DeepEqual).
+func (e *Expectation) WithField(key string, value any) *Expectation {
+	if e.fields == nil {
+		e.fields = make(map[string]any)
+	}
+	e.fields[key] = value
+	return e
+}
+
+// Times exige exatamente n chamadas correspondentes (o padrão é pelo menos uma).
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+func (e *Expectation) matches(c Call) bool {
+	if c.Level != e.level || c.Message != e.message {
+		return false
+	}
+	for k, v := range e.fields {
+		got, ok := c.Fields[k]
+		if !ok || !reflect.DeepEqual(got, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *Expectation) String() string {
+	s := fmt.Sprintf("%s %q", e.level, e.message)
+	if len(e.fields) > 0 {
+		s += fmt.Sprintf(" %v", e.fields)
+	}
+	return s
+}
+
+// Expect registra uma chamada esperada com o nível e a mensagem informados.
+func (m *MockLog) Expect(level lazylog.Level, msg string) *Expectation {
+	e := &Expectation{level: level, message: msg}
+	m.state.mu.Lock()
+	defer m.state.mu.Unlock()
+	m.state.expectations = append(m.state.expectations, e)
+	return e
+}
+
+func (m *MockLog) ExpectDebug(msg string) *Expectation { return m.Expect(lazylog.DEBUG, msg) }
+func (m *MockLog) ExpectInfo(msg string) *Expectation  { return m.Expect(lazylog.INFO, msg) }
+func (m *MockLog) ExpectWarn(msg string) *Expectation  { return m.Expect(lazylog.WARN, msg) }
+func (m *MockLog) ExpectError(msg string) *Expectation { return m.Expect(lazylog.ERROR, msg) }
+
+// AssertExpectations falha o teste para cada expectativa não atendida,
+// listando as chamadas registradas.
+func (m *MockLog) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.state.mu.Lock()
+	calls := append([]Call(nil), m.state.calls...)
+	expectations := append([]*Expectation(nil), m.state.expectations...)
+	m.state.mu.Unlock()
+
+	for _, e := range expectations {
+		n := 0
+		for _, c := range calls {
+			if e.matches(c) {
+				n++
+			}
+		}
+		if (e.times == 0 && n == 0) || (e.times > 0 && n != e.times) {
+			want := "at least once"
+			if e.times > 0 {
+				want = fmt.Sprintf("%d time(s)", e.times)
+			}
+			t.Errorf("lazylogtest: expected %s %s, got %d\n%s", e, want, n, formatCalls(calls))
+		}
+	}
+}
+
+// AssertNoLevel falha o teste se houver chamadas no nível informado ou acima
+// (ex: AssertNoLevel(t, lazylog.ERROR)).
+func (m *MockLog) AssertNoLevel(t testing.TB, level lazylog.Level) {
+	t.Helper()
+	for _, c := range m.Calls() {
+		if c.Level >= level {
+			t.Errorf("lazylogtest: unexpected %s %q %v", c.Level, c.Message, c.Fields)
+		}
+	}
+}
+
+func formatCalls(calls []Call) string {
+	if len(calls) == 0 {
+		return "no calls recorded"
+	}
+	var b strings.Builder
+	b.WriteString("recorded calls:")
+	for _, c := range calls {
+		fmt.Fprintf(&b, "\n  %s %q %v", c.Level, c.Message, c.Fields)
+	}
+	return b.String()
+}
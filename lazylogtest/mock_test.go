@@ -0,0 +1,33 @@
+package lazylogtest_test
+
+import (
+	"testing"
+
+	"github.com/chmenegatti/lazylog"
+	"github.com/chmenegatti/lazylog/lazylogtest"
+)
+
+func TestMockLog(t *testing.T) {
+	log := lazylogtest.NewMockLog()
+	log.ExpectWarn("slow query").WithField("table", "users").WithField("ms", 120)
+	log.ExpectInfo("done").Times(2)
+
+	repo := log.With(map[string]any{"table": "users"})
+	repo.WarnFields("slow query", lazylog.Field{Key: "ms", Value: 120})
+	log.Info("done")
+	log.Info("done")
+
+	log.AssertExpectations(t)
+	log.AssertNoLevel(t, lazylog.ERROR)
+	if calls := log.Calls(); len(calls) != 3 || calls[0].Fields["table"] != "users" {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+
+	// Expectativas não atendidas devem falhar o teste.
+	inner := &testing.T{}
+	log.ExpectError("boom")
+	log.AssertExpectations(inner)
+	if !inner.Failed() {
+		t.Error("expected unmet expectation to fail")
+	}
+}
@@ -1,41 +1,93 @@
 package lazylog
 
 import (
+	"encoding/json"
 	"strings"
 	"sync"
 )
 
 type Level int
 
+// TRACE fica abaixo de DEBUG (valor negativo), para que transportes com o
+// nível zero (DEBUG) não recebam instrumentação muito verbosa por padrão.
+const TRACE Level = -1
+
 const (
 	DEBUG Level = iota
 	INFO
 	WARN
 	ERROR
+	PANIC // usado por Logger.Panic
+	FATAL // usado por Logger.Fatal
 )
 
 var (
 	levelMu    sync.RWMutex
 	levelNames = map[Level]string{
+		TRACE: "TRACE",
 		DEBUG: "DEBUG",
 		INFO:  "INFO",
 		WARN:  "WARN",
 		ERROR: "ERROR",
+		PANIC: "PANIC",
+		FATAL: "FATAL",
 	}
 	levelValues = map[string]Level{
+		"TRACE": TRACE,
 		"DEBUG": DEBUG,
 		"INFO":  INFO,
 		"WARN":  WARN,
 		"ERROR": ERROR,
+		"PANIC": PANIC,
+		"FATAL": FATAL,
 	}
+	// Representações pré-computadas usadas pelos formatters, evitando fmt e
+	// montagem de strings a cada entry.
+	levelTextTags  = map[Level]string{} // "[INFO]"
+	levelJSONPairs = map[Level]string{} // `"level":"INFO",`
 )
 
+func init() {
+	for lvl, name := range levelNames {
+		precomputeLevel(lvl, name)
+	}
+}
+
+// precomputeLevel atualiza as representações pré-computadas de um nível.
+// Deve ser chamado com levelMu em escrita (ou durante init).
+func precomputeLevel(l Level, name string) {
+	levelTextTags[l] = "[" + name + "]"
+	quoted, _ := json.Marshal(name)
+	levelJSONPairs[l] = `"level":` + string(quoted) + ","
+}
+
+// textTag retorna "[NOME]" para uso no TextFormatter.
+func (l Level) textTag() string {
+	levelMu.RLock()
+	defer levelMu.RUnlock()
+	if tag, ok := levelTextTags[l]; ok {
+		return tag
+	}
+	return "[UNKNOWN]"
+}
+
+// jsonPair retorna `"level":"NOME",` para uso no JSONFormatter.
+func (l Level) jsonPair() string {
+	levelMu.RLock()
+	defer levelMu.RUnlock()
+	if pair, ok := levelJSONPairs[l]; ok {
+		return pair
+	}
+	return `"level":"UNKNOWN",`
+}
+
 // RegisterLevel permite registrar um novo nível de log customizado.
 func RegisterLevel(name string, value Level) {
 	levelMu.Lock()
 	defer levelMu.Unlock()
 	levelNames[value] = name
 	levelValues[name] = value
+	precomputeLevel(value, name)
 }
 
 func (l Level) String() string {
@@ -48,10 +100,16 @@ func (l Level) String() string {
 }
 
 func ParseLevel(lvl string) Level {
-	levelMu.RLock()
-	defer levelMu.RUnlock()
-	if v, ok := levelValues[strings.ToUpper(lvl)]; ok {
+	if v, ok := lookupLevel(lvl); ok {
 		return v
 	}
 	return INFO // Default to INFO if the level is unknown
 }
+
+// lookupLevel busca um nível pelo nome, indicando se ele existe.
+func lookupLevel(lvl string) (Level, bool) {
+	levelMu.RLock()
+	defer levelMu.RUnlock()
+	v, ok := levelValues[strings.ToUpper(lvl)]
+	return v, ok
+}
@@ -0,0 +1,49 @@
+package lazylog
+
+import (
+	"strings"
+	"sync"
+)
+
+// LevelLabels mapeia níveis para rótulos de exibição (ex: WARN -> "AVISO").
+// Níveis ausentes usam o nome padrão (Level.String()).
+type LevelLabels map[Level]string
+
+var (
+	localeMu sync.RWMutex
+	locales  = map[string]LevelLabels{
+		"pt-BR": {TRACE: "RASTREIO", DEBUG: "DEPURAÇÃO", INFO: "INFO", WARN: "AVISO", ERROR: "ERRO", PANIC: "PANIC", FATAL: "FATAL"},
+		"pt":    {TRACE: "RASTREIO", DEBUG: "DEPURAÇÃO", INFO: "INFO", WARN: "AVISO", ERROR: "ERRO", PANIC: "PANIC", FATAL: "FATAL"},
+		"es":    {TRACE: "RASTREO", DEBUG: "DEPURACIÓN", INFO: "INFO", WARN: "AVISO", ERROR: "ERROR", PANIC: "PANIC", FATAL: "FATAL"},
+		"en":    {},
+	}
+)
+
+// RegisterLocale registra (ou substitui) a tabela de rótulos de um locale.
+func RegisterLocale(locale string, labels LevelLabels) {
+	localeMu.Lock()
+	defer localeMu.Unlock()
+	locales[locale] = labels
+}
+
+// LocaleLabels retorna os rótulos do locale (ex: "pt-BR" ou "pt_BR"), caindo
+// para o idioma ("pt") se não houver tabela específica. Retorna nil (rótulos
+// padrão) se nenhum dos dois estiver registrado.
+func LocaleLabels(locale string) LevelLabels {
+	locale = strings.ReplaceAll(locale, "_", "-")
+	localeMu.RLock()
+	defer localeMu.RUnlock()
+	if labels, ok := locales[locale]; ok {
+		return labels
+	}
+	lang, _, _ := strings.Cut(locale, "-")
+	return locales[strings.ToLower(lang)]
+}
+
+// label retorna o rótulo do nível, ou o nome padrão.
+func (ll LevelLabels) label(level Level) string {
+	if s, ok := ll[level]; ok {
+		return s
+	}
+	return level.String()
+}
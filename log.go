@@ -0,0 +1,71 @@
+package lazylog
+
+// Log é a interface mínima de logging para injeção de dependência: código
+// que depende de Log em vez de *Logger pode receber um logger real, um
+// EntryBuilder (ComFields), o Nop ou um mock nos testes.
+type Log interface {
+	Debug(msg string)
+	Info(msg string)
+	Warn(msg string)
+	Error(msg string)
+
+	DebugFields(msg string, fields ...Field)
+	InfoFields(msg string, fields ...Field)
+	WarnFields(msg string, fields ...Field)
+	ErrorFields(msg string, fields ...Field)
+
+	// With retorna um Log derivado que inclui os campos em toda entry.
+	With(fields map[string]any) Log
+}
+
+var (
+	_ Log = (*Logger)(nil)
+	_ Log = (*EntryBuilder)(nil)
+)
+
+// With é o equivalente de WithFields que satisfaz a interface Log.
+func (l *Logger) With(fields map[string]any) Log {
+	return l.WithFields(fields)
+}
+
+// With retorna um novo builder com os campos adicionados (o builder e o mapa
+// originais não são alterados).
+func (b *EntryBuilder) With(fields map[string]any) Log {
+	if b.disabled {
+		return b
+	}
+	nb := *b
+	nb.fields = mergeBoundFields(b.fields, fields)
+	return &nb
+}
+
+func (b *EntryBuilder) DebugFields(msg string, fields ...Field) {
+	b.logFields(DEBUG, msg, fields)
+}
+func (b *EntryBuilder) InfoFields(msg string, fields ...Field) {
+	b.logFields(INFO, msg, fields)
+}
+func (b *EntryBuilder) WarnFields(msg string, fields ...Field) {
+	b.logFields(WARN, msg, fields)
+}
+func (b *EntryBuilder) ErrorFields(msg string, fields ...Field) {
+	b.logFields(ERROR, msg, fields)
+}
+
+// logFields registra com os campos do builder mais os campos tipados (que
+// têm precedência).
+func (b *EntryBuilder) logFields(level Level, msg string, fields []Field) {
+	if !b.allows(level) {
+		return
+	}
+	merged := make(map[string]interface{}, len(b.fields)+len(fields))
+	for k, v := range b.fields {
+		merged[k] = v
+	}
+	for _, f := range fields {
+		if f.Key != "" {
+			merged[f.Key] = f.Value
+		}
+	}
+	b.logger.logWithFieldsCustomFormatter(level, msg, merged, b.formatter)
+}
@@ -0,0 +1,101 @@
+package lazylog
+
+import (
+	"bytes"
+	"errors"
+)
+
+// ErrLogcatUnavailable é retornado por NewLogcatTransport fora do Android (ou
+// quando o binário foi compilado sem cgo).
+var ErrLogcatUnavailable = errors.New("lazylog: logcat is only available on android with cgo")
+
+// Prioridades do logcat (android/log.h).
+const (
+	logcatVerbose = 2
+	logcatDebug   = 3
+	logcatInfo    = 4
+	logcatWarn    = 5
+	logcatError   = 6
+	logcatFatal   = 7
+)
+
+// logcatMaxLine é o tamanho máximo de cada escrita; o logcat trunca linhas
+// maiores (~4 KiB), então mensagens longas são divididas.
+const logcatMaxLine = 4000
+
+// LogcatTransport escreve no logcat do Android via __android_log_write, para
+// que bibliotecas Go mobile (gomobile) apareçam nas ferramentas da plataforma
+// (adb logcat, Android Studio). Nível e horário já são exibidos pelo logcat,
+// por isso o formatter padrão escreve apenas a mensagem e os campos.
+type LogcatTransport struct {
+	Tag       string
+	Level     Level
+	Formatter Formatter // opcional; nil = "mensagem k=v ..."
+}
+
+// NewLogcatTransport cria um LogcatTransport com a tag informada (ex: o nome
+// do app). Retorna ErrLogcatUnavailable fora do Android.
+func NewLogcatTransport(tag string, level Level, formatter Formatter) (*LogcatTransport, error) {
+	if !logcatAvailable {
+		return nil, ErrLogcatUnavailable
+	}
+	return &LogcatTransport{Tag: tag, Level: level, Formatter: formatter}, nil
+}
+
+func (t *LogcatTransport) WriteLog(entry *Entry) error {
+	var line []byte
+	if t.Formatter != nil {
+		out, err := t.Formatter.Format(entry)
+		if err != nil {
+			return err
+		}
+		line = bytes.TrimSuffix(out, []byte("\n"))
+	} else {
+		line = logcatLine(entry)
+	}
+	prio := logcatPriority(entry.Level)
+	for len(line) > logcatMaxLine {
+		if err := logcatWrite(prio, t.Tag, string(line[:logcatMaxLine])); err != nil {
+			return err
+		}
+		line = line[logcatMaxLine:]
+	}
+	return logcatWrite(prio, t.Tag, string(line))
+}
+
+func (t *LogcatTransport) MinLevel() Level {
+	return t.Level
+}
+
+// logcatPriority mapeia os níveis do lazylog para as prioridades do logcat.
+func logcatPriority(level Level) int {
+	switch {
+	case level <= TRACE:
+		return logcatVerbose
+	case level == DEBUG:
+		return logcatDebug
+	case level == INFO:
+		return logcatInfo
+	case level == WARN:
+		return logcatWarn
+	case level == ERROR:
+		return logcatError
+	case level >= PANIC && level <= FATAL:
+		return logcatFatal
+	}
+	return logcatInfo // níveis customizados
+}
+
+// logcatLine formata "mensagem k=v ..." sem timestamp nem nível.
+func logcatLine(entry *Entry) []byte {
+	var b bytes.Buffer
+	b.WriteString(entry.Message)
+	if len(entry.Fields) > 0 {
+		b.WriteByte(' ')
+		f := &TextFormatter{}
+		for _, k := range entry.orderedKeys() {
+			f.writeField(&b, k, entry.Fields[k])
+		}
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte(" "))
+}
@@ -0,0 +1,25 @@
+//go:build android && cgo
+
+package lazylog
+
+/*
+#cgo LDFLAGS: -llog
+#include <stdlib.h>
+#include <android/log.h>
+*/
+import "C"
+
+import "unsafe"
+
+const logcatAvailable = true
+
+// logcatWrite usa __android_log_write: __android_log_print é variádica e não
+// pode ser chamada diretamente via cgo.
+func logcatWrite(prio int, tag, msg string) error {
+	ctag := C.CString(tag)
+	defer C.free(unsafe.Pointer(ctag))
+	cmsg := C.CString(msg)
+	defer C.free(unsafe.Pointer(cmsg))
+	C.__android_log_write(C.int(prio), ctag, cmsg)
+	return nil
+}
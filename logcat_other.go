@@ -0,0 +1,9 @@
+//go:build !android || !cgo
+
+package lazylog
+
+const logcatAvailable = false
+
+func logcatWrite(prio int, tag, msg string) error {
+	return ErrLogcatUnavailable
+}
@@ -0,0 +1,107 @@
+package lazylog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogfmtFormatter formata logs no formato logfmt (chave=valor separados por
+// espaço), aceito por Loki, Heroku e pela maioria das ferramentas de busca:
+//
+//	time=2025-03-01T12:00:00Z level=warn msg="slow query" table=users elapsed_ms=812
+//
+// Os campos seguem FieldOrder (ou ordem alfabética); mapas e slices são
+// serializados como JSON e valores com espaços, aspas ou "=" vão entre aspas.
+// Campos chamados time, level ou msg (e caller/function, com Entry.Caller e
+// Entry.Function) recebem o prefixo "fields.".
+type LogfmtFormatter struct {
+	TimestampFormat string // padrão: time.RFC3339
+}
+
+var logfmtReservedKeys = []string{"time", "level", "msg"}
+
+func (f *LogfmtFormatter) Format(entry *Entry) ([]byte, error) {
+	timestampFormat := f.TimestampFormat
+	if timestampFormat == "" {
+		timestampFormat = time.RFC3339
+	}
+	var b bytes.Buffer
+	b.Grow(64 + len(entry.Message) + 16*len(entry.Fields))
+	b.WriteString("time=")
+	writeLogfmtValue(&b, entry.Timestamp.Format(timestampFormat))
+	b.WriteString(" level=")
+	b.WriteString(strings.ToLower(entry.Level.String()))
+	b.WriteString(" msg=")
+	writeLogfmtValue(&b, entry.Message)
+	if entry.Caller != nil {
+		b.WriteString(" caller=")
+		writeLogfmtValue(&b, entry.Caller.String())
+	}
+	if entry.Function != "" {
+		b.WriteString(" function=")
+		writeLogfmtValue(&b, entry.Function)
+	}
+	for _, k := range entry.orderedKeys() {
+		v := entry.Fields[k]
+		for _, reserved := range logfmtReservedKeys {
+			if k == reserved {
+				k = "fields." + k
+				break
+			}
+		}
+		if entry.shadowsMeta(k) {
+			k = "fields." + k
+		}
+		b.WriteByte(' ')
+		b.WriteString(logfmtKey(k))
+		b.WriteByte('=')
+		writeLogfmtValue(&b, logfmtString(v))
+	}
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
+
+// logfmtString converte o valor de um campo para texto.
+func logfmtString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case error:
+		return val.Error()
+	case fmt.Stringer:
+		return val.String()
+	case map[string]interface{}, []interface{}:
+		if data, err := json.Marshal(val); err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprint(v)
+}
+
+// logfmtKey remove da chave os caracteres que quebrariam o par chave=valor.
+func logfmtKey(k string) string {
+	if !strings.ContainsAny(k, " =\"\t\n") {
+		return k
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '=', '"', '\t', '\n':
+			return '_'
+		}
+		return r
+	}, k)
+}
+
+func writeLogfmtValue(b *bytes.Buffer, s string) {
+	if s != "" && !strings.ContainsAny(s, " =\"\t\n\r\\") {
+		b.WriteString(s)
+		return
+	}
+	b.WriteString(strconv.Quote(s))
+}
@@ -0,0 +1,47 @@
+package lazylog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// LokiFormatter formata cada entry como um corpo da API de push do Grafana
+// Loki (/loki/api/v1/push): um stream com os rótulos fixos mais "level" e uma
+// única linha formatada por Line.
+type LokiFormatter struct {
+	Line   Formatter         // formatter da linha (usa JSONFormatter se nil)
+	Labels map[string]string // rótulos fixos do stream (ex: app, env)
+}
+
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func (f *LokiFormatter) Format(entry *Entry) ([]byte, error) {
+	line := f.Line
+	if line == nil {
+		line = &JSONFormatter{}
+	}
+	out, err := line.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[string]string, len(f.Labels)+1)
+	for k, v := range f.Labels {
+		labels[k] = v
+	}
+	labels["level"] = entry.Level.String()
+	return json.Marshal(lokiPush{Streams: []lokiStream{{
+		Stream: labels,
+		Values: [][2]string{{
+			strconv.FormatInt(entry.Timestamp.UnixNano(), 10),
+			string(bytes.TrimSuffix(out, []byte("\n"))),
+		}},
+	}}})
+}
@@ -9,6 +9,8 @@ type LumberjackTransport struct {
 	Logger    *lumberjack.Logger
 	Level     Level
 	Formatter Formatter
+
+	stats transportStats
 }
 
 func NewLumberjackTransport(filename string, level Level, formatter Formatter, maxSize, maxBackups, maxAge int, compress bool) *LumberjackTransport {
@@ -26,17 +28,26 @@ func NewLumberjackTransport(filename string, level Level, formatter Formatter, m
 }
 
 func (l *LumberjackTransport) WriteLog(entry *Entry) error {
+	_, err := l.writeLogN(entry)
+	return err
+}
+
+// writeLogN escreve a entry e retorna a quantidade de bytes gravados.
+func (l *LumberjackTransport) writeLogN(entry *Entry) (n int, err error) {
+	defer func() { l.stats.record(l, n, err) }()
 	formatter := l.Formatter
 	if formatter == nil {
 		formatter = &TextFormatter{}
 	}
 	bytes, err := formatter.Format(entry)
 	if err != nil {
-		_, err2 := l.Logger.Write([]byte(entry.Timestamp.Format("2006-01-02T15:04:05Z07:00") + " [" + entry.Level.String() + "] " + entry.Message + "\n"))
-		return err2
+		return l.Logger.Write([]byte(entry.Timestamp.Format("2006-01-02T15:04:05Z07:00") + " [" + entry.Level.String() + "] " + entry.Message + "\n"))
 	}
-	_, err = l.Logger.Write(bytes)
-	return err
+	return l.Logger.Write(bytes)
+}
+
+func (l *LumberjackTransport) writesTypedFields() bool {
+	return typedFormatter(l.Formatter)
 }
 
 func (l *LumberjackTransport) MinLevel() Level {
@@ -0,0 +1,148 @@
+package lazylog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LoggerNameKey é o campo em que Named registra o nome do logger.
+const LoggerNameKey = "logger"
+
+// Named retorna um logger derivado com o nome informado, encadeado com "." ao
+// nome do logger atual (ex: logger.Named("http").Named("server") resulta em
+// "http.server"). O nome vai no campo "logger" de toda entry e é usado pelos
+// overrides de SetNameLevel. Como em WithFields, o derivado compartilha
+// transportes, hooks e configuração com o pai.
+func (l *Logger) Named(name string) *Logger {
+	if c := l.core(); c.nop {
+		return c
+	}
+	full := name
+	if l.name != "" && name != "" {
+		full = l.name + "." + name
+	} else if name == "" {
+		full = l.name
+	}
+	return &Logger{parent: l.core(), bound: withField(l.bound, LoggerNameKey, full), name: full}
+}
+
+// Name retorna o nome do logger (vazio para loggers sem Named).
+func (l *Logger) Name() string {
+	if l == nil {
+		return ""
+	}
+	return l.name
+}
+
+// nameLevel é um override de nível para um prefixo de nome de logger.
+type nameLevel struct {
+	prefix string // nome com pontos, ou "..." para todos os loggers
+	level  Level
+}
+
+// matches indica se o nome casa com o prefixo: o próprio nome ou um
+// descendente (ex: "db" casa com "db" e "db.pool", mas não com "dbx").
+func (n nameLevel) matches(name string) bool {
+	if n.prefix == "..." {
+		return true
+	}
+	return name == n.prefix || strings.HasPrefix(name, n.prefix+".")
+}
+
+// specificity ordena os overrides: "..." é o menos específico, mesmo sendo
+// mais longo que prefixos curtos como "db".
+func (n nameLevel) specificity() int {
+	if n.prefix == "..." {
+		return 0
+	}
+	return len(n.prefix) + 1
+}
+
+// SetNameLevel define o nível mínimo das entries dos loggers cujo nome (Named)
+// é prefix ou começa com prefix+"." (ex: "db" vale para "db.pool"). O prefixo
+// "..." vale para todos os loggers, inclusive os sem nome. Vale o prefixo mais
+// específico (mais longo). Pode ser chamado em runtime.
+//
+// Assim como SetPackageLevel, os overrides apenas restringem: para "db" em
+// DEBUG e o restante em INFO, os transportes precisam aceitar DEBUG e o nível
+// geral deve ser definido com SetNameLevel("...", INFO).
+func (l *Logger) SetNameLevel(prefix string, level Level) {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, n := range l.nameLevels {
+		if n.prefix == prefix {
+			l.nameLevels[i].level = level
+			return
+		}
+	}
+	l.nameLevels = append(l.nameLevels, nameLevel{prefix: prefix, level: level})
+	l.hasNameLevels.Store(true)
+}
+
+// SetNameLevels aplica overrides no formato "prefixo=NIVEL" separados por
+// vírgula (ex: "...=INFO,db=DEBUG"), substituindo os anteriores.
+func (l *Logger) SetNameLevels(spec string) error {
+	l = l.core()
+	if l.nop {
+		return nil
+	}
+	levels, err := parseNameLevels(spec)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.nameLevels = levels
+	l.hasNameLevels.Store(len(levels) > 0)
+	return nil
+}
+
+// parseNameLevels interpreta o formato aceito por SetNameLevels.
+func parseNameLevels(spec string) ([]nameLevel, error) {
+	var levels []nameLevel
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		prefix, name, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(prefix) == "" {
+			return nil, fmt.Errorf("lazylog: invalid name level %q", item)
+		}
+		level, ok := lookupLevel(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("lazylog: unknown level %q", name)
+		}
+		levels = append(levels, nameLevel{prefix: strings.TrimSpace(prefix), level: level})
+	}
+	return levels, nil
+}
+
+// ClearNameLevels remove todos os overrides de nível por nome.
+func (l *Logger) ClearNameLevels() {
+	l = l.core()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.nameLevels = nil
+	l.hasNameLevels.Store(false)
+}
+
+// nameAllows verifica o nível contra o override mais específico que casa com
+// o nome do logger.
+func (l *Logger) nameAllows(name string, level Level) bool {
+	l = l.core()
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	best := -1
+	var min Level
+	for _, n := range l.nameLevels {
+		if n.matches(name) && n.specificity() > best {
+			best, min = n.specificity(), n.level
+		}
+	}
+	return best < 0 || level >= min
+}
@@ -0,0 +1,85 @@
+package lazylog
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// NetworkOptions controla como os transportes HTTP estabelecem conexões (via
+// NewHTTPClient), para redes corporativas onde a saída direta é bloqueada.
+type NetworkOptions struct {
+	// ProxyURL define um proxy explícito (http://, https:// ou socks5://).
+	// Se vazio, as variáveis HTTP_PROXY/HTTPS_PROXY/NO_PROXY são respeitadas.
+	ProxyURL string `yaml:"ProxyURL"`
+	// HostOverrides substitui a resolução DNS de hosts específicos
+	// (ex: "logs.example.com" -> "10.0.0.12").
+	HostOverrides map[string]string `yaml:"HostOverrides"`
+	// DialTimeout limita o tempo de conexão (padrão: 30s).
+	DialTimeout time.Duration `yaml:"DialTimeout"`
+	// MaxConnsPerHost limita as conexões simultâneas por host (0 = sem limite),
+	// para que rajadas de log não esgotem descritores de arquivo.
+	MaxConnsPerHost int `yaml:"MaxConnsPerHost"`
+	// MaxIdleConnsPerHost limita as conexões ociosas mantidas no pool por host
+	// (padrão do net/http: 2).
+	MaxIdleConnsPerHost int `yaml:"MaxIdleConnsPerHost"`
+	// IdleConnTimeout fecha conexões ociosas após o período (padrão: 90s).
+	IdleConnTimeout time.Duration `yaml:"IdleConnTimeout"`
+	// DialContext substitui completamente o dialer (ex: túneis, SOCKS customizado).
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error) `yaml:"-"`
+}
+
+// NewHTTPClient cria o *http.Client usado pelos transportes HTTP a partir das
+// opções de TLS e de rede (ambas opcionais).
+func NewHTTPClient(tlsOpts *TLSConfigOptions, netOpts *NetworkOptions) (*http.Client, error) {
+	tlsCfg, err := tlsOpts.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsCfg
+	if netOpts != nil {
+		if netOpts.ProxyURL != "" {
+			proxy, err := url.Parse(netOpts.ProxyURL)
+			if err != nil {
+				return nil, err
+			}
+			transport.Proxy = http.ProxyURL(proxy)
+		}
+		transport.DialContext = netOpts.dialContext()
+		transport.MaxConnsPerHost = netOpts.MaxConnsPerHost
+		if netOpts.MaxIdleConnsPerHost > 0 {
+			transport.MaxIdleConnsPerHost = netOpts.MaxIdleConnsPerHost
+		}
+		if netOpts.IdleConnTimeout > 0 {
+			transport.IdleConnTimeout = netOpts.IdleConnTimeout
+		}
+	}
+	return &http.Client{Transport: transport, Timeout: 10 * time.Second}, nil
+}
+
+// dialContext combina o dialer configurado com os overrides de DNS.
+func (o *NetworkOptions) dialContext() func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dial := o.DialContext
+	if dial == nil {
+		timeout := o.DialTimeout
+		if timeout <= 0 {
+			timeout = 30 * time.Second
+		}
+		dial = (&net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}).DialContext
+	}
+	if len(o.HostOverrides) == 0 {
+		return dial
+	}
+	overrides := o.HostOverrides
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if host, port, err := net.SplitHostPort(addr); err == nil {
+			if ip, ok := overrides[host]; ok {
+				addr = net.JoinHostPort(ip, port)
+			}
+		}
+		return dial(ctx, network, addr)
+	}
+}
@@ -0,0 +1,147 @@
+package lazylog
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// LogSite é um ponto de log: o template da mensagem (ver MessageTemplate)
+// emitido de um caller ("dir/arquivo.go:linha", vazio se desconhecido).
+type LogSite struct {
+	Template string
+	Caller   string
+	Level    Level // maior nível visto no ponto
+	Entries  uint64
+}
+
+type logSiteKey struct {
+	template string
+	caller   string
+}
+
+// NoisyTracker conta as entries por template de mensagem e caller, para
+// identificar os pontos de log mais verbosos e guiar a limpeza do código:
+//
+//	noisy := lazylog.NewNoisyTracker(0)
+//	logger.EnableCaller(0)
+//	logger.AddHook(noisy.Hook(), false)
+//	...
+//	noisy.Report(os.Stderr, 20)
+//
+// Para analisar arquivos já gravados, use "lzlog analyze".
+type NoisyTracker struct {
+	MaxSites int // pontos distintos antes de agrupar em OtherTemplates
+
+	mu    sync.Mutex
+	sites map[logSiteKey]*LogSite
+	total uint64
+}
+
+// NewNoisyTracker cria um NoisyTracker que guarda até maxSites pontos de
+// log distintos (padrão: 1000).
+func NewNoisyTracker(maxSites int) *NoisyTracker {
+	return &NoisyTracker{MaxSites: maxSites}
+}
+
+// Hook retorna um after-hook que contabiliza cada entry registrada. O caller
+// só é conhecido com EnableCaller; sem ele, as entries são agrupadas apenas
+// pelo template.
+func (t *NoisyTracker) Hook() Hook {
+	return func(entry *Entry) {
+		caller := ""
+		if entry.Caller != nil {
+			caller = entry.Caller.String()
+		}
+		t.Add(entry.Message, caller, entry.Level)
+	}
+}
+
+// Add contabiliza uma entry com a mensagem, o caller e o nível informados.
+func (t *NoisyTracker) Add(message, caller string, level Level) {
+	key := logSiteKey{template: MessageTemplate(message), caller: caller}
+	limit := t.MaxSites
+	if limit <= 0 {
+		limit = 1000
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sites == nil {
+		t.sites = make(map[logSiteKey]*LogSite)
+	}
+	site, ok := t.sites[key]
+	if !ok {
+		if len(t.sites) >= limit {
+			key = logSiteKey{template: OtherTemplates}
+			site = t.sites[key]
+		}
+		if site == nil {
+			site = &LogSite{Template: key.template, Caller: key.caller, Level: level}
+			t.sites[key] = site
+		}
+	}
+	site.Entries++
+	if level > site.Level {
+		site.Level = level
+	}
+	t.total++
+}
+
+// Top retorna os k pontos com mais entries (todos se k <= 0), em ordem
+// decrescente.
+func (t *NoisyTracker) Top(k int) []LogSite {
+	t.mu.Lock()
+	out := make([]LogSite, 0, len(t.sites))
+	for _, site := range t.sites {
+		out = append(out, *site)
+	}
+	t.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Entries != out[j].Entries {
+			return out[i].Entries > out[j].Entries
+		}
+		if out[i].Template != out[j].Template {
+			return out[i].Template < out[j].Template
+		}
+		return out[i].Caller < out[j].Caller
+	})
+	if k > 0 && len(out) > k {
+		out = out[:k]
+	}
+	return out
+}
+
+// Total retorna o número de entries contabilizadas.
+func (t *NoisyTracker) Total() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.total
+}
+
+// Reset zera os contadores.
+func (t *NoisyTracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sites, t.total = nil, 0
+}
+
+// Report escreve os k pontos mais verbosos, um por linha, com a contagem, a
+// fração do total, o maior nível, o caller e o template.
+func (t *NoisyTracker) Report(w io.Writer, k int) error {
+	total := t.Total()
+	for i, site := range t.Top(k) {
+		caller := site.Caller
+		if caller == "" {
+			caller = "-"
+		}
+		share := 0.0
+		if total > 0 {
+			share = 100 * float64(site.Entries) / float64(total)
+		}
+		if _, err := fmt.Fprintf(w, "%3d. %8d %5.1f%% %-5s %s %q\n", i+1, site.Entries, share, site.Level, caller, site.Template); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package lazylog
+
+// nopLogger é o logger compartilhado retornado por Nop (e usado no lugar de
+// um *Logger nil). Como ignora alterações, pode ser compartilhado.
+var nopLogger = &Logger{nop: true}
+
+// nopBuilder é o EntryBuilder desabilitado retornado pelos métodos de um
+// logger Nop.
+var nopBuilder = &EntryBuilder{logger: nopLogger, disabled: true}
+
+// Nop retorna um logger que descarta todas as entries sem alocar. Métodos de
+// configuração (AddTransport, AddHook, SetPackageLevels...) são ignorados e
+// loggers derivados (WithFields, Named, WithError) retornam o próprio Nop.
+// Fatal e Panic continuam encerrando o processo e fazendo panic.
+//
+// Todos os métodos de *Logger também aceitam receiver nil, que se comporta
+// como Nop; bibliotecas podem receber um *Logger opcional sem checar nil:
+//
+//	type Client struct{ Log *lazylog.Logger }
+//
+//	c.Log.Info("conectado") // seguro mesmo com c.Log == nil
+func Nop() *Logger {
+	return nopLogger
+}
@@ -0,0 +1,49 @@
+package lazylog
+
+import "time"
+
+// Once retorna um builder que registra a mensagem apenas na primeira vez em
+// que a chave é usada neste logger (ex: avisos de deprecação). O estado é do
+// logger (compartilhado com os derivados de Named, WithField...), não do
+// processo: outro logger com a mesma chave registra de novo. A chave só é
+// consumida quando a entry é de fato registrada, então um uso num nível
+// desabilitado não impede o próximo:
+//
+//	logger.Once("legacy-api").Warn("deprecated API used")
+func (l *Logger) Once(key string) *EntryBuilder {
+	c := l.core()
+	if c.nop {
+		return nopBuilder
+	}
+	if _, seen := c.onceSeen.Load(key); seen {
+		return &EntryBuilder{logger: l, disabled: true}
+	}
+	return &EntryBuilder{logger: l, claim: func() bool {
+		_, seen := c.onceSeen.LoadOrStore(key, time.Now())
+		return !seen
+	}}
+}
+
+// OnceEvery é como Once, mas volta a registrar a chave depois que interval
+// tiver passado desde a última emissão.
+func (l *Logger) OnceEvery(key string, interval time.Duration) *EntryBuilder {
+	c := l.core()
+	if c.nop {
+		return nopBuilder
+	}
+	return &EntryBuilder{logger: l, claim: func() bool {
+		now := time.Now()
+		for {
+			prev, loaded := c.onceSeen.LoadOrStore(key, now)
+			if !loaded {
+				return true
+			}
+			if now.Sub(prev.(time.Time)) < interval {
+				return false
+			}
+			if c.onceSeen.CompareAndSwap(key, prev, now) {
+				return true
+			}
+		}
+	}}
+}
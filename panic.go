@@ -0,0 +1,64 @@
+package lazylog
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+)
+
+// panicFields estrutura um valor de panic em campos dedicados
+// (panic.value, panic.type e panic.stack), permitindo que coletores
+// agreguem panics por tipo.
+func panicFields(v any) map[string]any {
+	value := fmt.Sprint(v)
+	if err, ok := v.(error); ok {
+		value = err.Error()
+	}
+	return map[string]any{
+		"value": value,
+		"type":  fmt.Sprintf("%T", v),
+		"stack": stackFrames(3),
+	}
+}
+
+// stackFrames retorna a pilha atual como lista de "func (file:line)",
+// omitindo os frames internos do lazylog.
+func stackFrames(skip int) []string {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(skip, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var out []string
+	for {
+		f, more := frames.Next()
+		if !isInternalFrame(f.Function) {
+			out = append(out, f.Function+" ("+f.File+":"+strconv.Itoa(f.Line)+")")
+		}
+		if !more {
+			return out
+		}
+	}
+}
+
+// LogPanic registra um valor recuperado de panic no nível PANIC, com os campos
+// estruturados em "panic". Útil para middlewares que já chamam recover().
+func (l *Logger) LogPanic(v any, fields ...map[string]any) {
+	if !l.enabledFor(PANIC) {
+		return
+	}
+	flds := make(map[string]any)
+	if len(fields) > 0 {
+		for k, val := range fields[0] {
+			flds[k] = val
+		}
+	}
+	flds["panic"] = panicFields(v)
+	l.logWithFields(PANIC, "panic recovered: "+fmt.Sprint(v), flds)
+}
+
+// RecoverAndLog recupera um panic em andamento e o registra via LogPanic.
+// Deve ser usado diretamente com defer: defer logger.RecoverAndLog()
+func (l *Logger) RecoverAndLog() {
+	if v := recover(); v != nil {
+		l.LogPanic(v)
+	}
+}
@@ -0,0 +1,143 @@
+package lazylog
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// packageLevel é um override de nível para um padrão de pacote.
+type packageLevel struct {
+	pattern string // caminho do pacote, ou prefixo se terminar em "/..."
+	level   Level
+}
+
+// matches indica se o pacote pkg casa com o padrão.
+func (p packageLevel) matches(pkg string) bool {
+	if p.pattern == "..." {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(p.pattern, "/..."); ok {
+		return pkg == prefix || strings.HasPrefix(pkg, prefix+"/")
+	}
+	return pkg == p.pattern
+}
+
+// SetPackageLevel define o nível mínimo das entries originadas no pacote
+// informado (ex: "github.com/acme/app/db"), ou em uma subárvore com o sufixo
+// "/..." (ex: "github.com/acme/app/..."). O padrão "..." vale para todos os
+// pacotes. Vale o padrão mais específico (mais longo). Pode ser chamado em
+// runtime.
+//
+// Os overrides apenas restringem: para habilitar DEBUG em um subsistema, os
+// transportes precisam aceitar DEBUG e o nível geral deve ser definido com
+// SetPackageLevel("...", INFO). Com overrides ativos, cada log detecta o
+// pacote do caller, o que tem custo (percorrer a stack).
+func (l *Logger) SetPackageLevel(pattern string, level Level) {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, p := range l.pkgLevels {
+		if p.pattern == pattern {
+			l.pkgLevels[i].level = level
+			return
+		}
+	}
+	l.pkgLevels = append(l.pkgLevels, packageLevel{pattern: pattern, level: level})
+	l.hasPkgLevels.Store(true)
+}
+
+// SetPackageLevels aplica overrides no formato "pacote=NIVEL" separados por
+// vírgula (ex: "...=INFO,github.com/acme/app/db=DEBUG"), substituindo os
+// anteriores. Útil para configurar via variável de ambiente ou endpoint admin.
+func (l *Logger) SetPackageLevels(spec string) error {
+	l = l.core()
+	if l.nop {
+		return nil
+	}
+	levels, err := parsePackageLevels(spec)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pkgLevels = levels
+	l.hasPkgLevels.Store(len(levels) > 0)
+	return nil
+}
+
+// parsePackageLevels interpreta o formato aceito por SetPackageLevels.
+func parsePackageLevels(spec string) ([]packageLevel, error) {
+	var levels []packageLevel
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		pattern, name, ok := strings.Cut(item, "=")
+		if !ok || pattern == "" {
+			return nil, fmt.Errorf("lazylog: invalid package level %q", item)
+		}
+		level, ok := lookupLevel(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("lazylog: unknown level %q", name)
+		}
+		levels = append(levels, packageLevel{pattern: strings.TrimSpace(pattern), level: level})
+	}
+	return levels, nil
+}
+
+// ClearPackageLevels remove todos os overrides de nível por pacote.
+func (l *Logger) ClearPackageLevels() {
+	l = l.core()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pkgLevels = nil
+	l.hasPkgLevels.Store(false)
+}
+
+// packageAllows verifica o nível contra o override mais específico que casa
+// com o pacote do caller.
+func (l *Logger) packageAllows(level Level) bool {
+	l = l.core()
+	pkg := callerPackage()
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	best := -1
+	var min Level
+	for _, p := range l.pkgLevels {
+		if p.matches(pkg) && len(p.pattern) > best {
+			best, min = len(p.pattern), p.level
+		}
+	}
+	return best < 0 || level >= min
+}
+
+// callerPackage retorna o caminho do pacote do primeiro frame fora do lazylog.
+func callerPackage() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !isInternalFrame(f.Function) {
+			return funcPackage(f.Function)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// funcPackage extrai o pacote de um nome qualificado de função
+// (ex: "github.com/acme/app/db.(*Repo).Find" -> "github.com/acme/app/db").
+func funcPackage(fn string) string {
+	slash := strings.LastIndexByte(fn, '/')
+	if dot := strings.IndexByte(fn[slash+1:], '.'); dot >= 0 {
+		return fn[:slash+1+dot]
+	}
+	return fn
+}
@@ -0,0 +1,74 @@
+package lazylog
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// EnableProcessInfo adiciona a toda entry campos que identificam o processo
+// emissor em logs agregados: hostname, pid, binary (nome do executável) e,
+// se definidos com SetAppInfo, app e app_version. Campos explícitos, os de
+// WithFields e os de SetDefaultFields prevalecem sobre eles.
+func (l *Logger) EnableProcessInfo() {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.processInfo = true
+	l.processFields = buildProcessFields(l.appName, l.appVersion)
+	l.refreshStaticFieldsLocked()
+}
+
+// DisableProcessInfo deixa de adicionar os campos de EnableProcessInfo.
+func (l *Logger) DisableProcessInfo() {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.processInfo = false
+	l.processFields = nil
+	l.refreshStaticFieldsLocked()
+}
+
+// SetAppInfo define o nome e a versão da aplicação, escritos como app e
+// app_version quando EnableProcessInfo está ativo (valores vazios são
+// omitidos):
+//
+//	logger.SetAppInfo("billing", buildVersion) // ex: -ldflags "-X main.buildVersion=1.4.2"
+//	logger.EnableProcessInfo()
+func (l *Logger) SetAppInfo(name, version string) {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.appName, l.appVersion = name, version
+	if l.processInfo {
+		l.processFields = buildProcessFields(name, version)
+		l.refreshStaticFieldsLocked()
+	}
+}
+
+// buildProcessFields monta o mapa (imutável depois de criado) com os campos
+// do processo.
+func buildProcessFields(name, version string) map[string]any {
+	fields := map[string]any{
+		"pid":    os.Getpid(),
+		"binary": filepath.Base(os.Args[0]),
+	}
+	if host, err := os.Hostname(); err == nil {
+		fields["hostname"] = host
+	}
+	if name != "" {
+		fields["app"] = name
+	}
+	if version != "" {
+		fields["app_version"] = version
+	}
+	return fields
+}
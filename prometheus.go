@@ -0,0 +1,140 @@
+package lazylog
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExemplarField é o campo marcado com true nas entries cujo trace_id foi
+// anexado como exemplar à métrica de contagem de logs.
+const ExemplarField = "metric_exemplar"
+
+// PrometheusHook conta as entries por nível e expõe o contador no formato
+// OpenMetrics (lazylog_entries_total, ou <Namespace>_entries_total). Quando a
+// entry tem trace_id (campo ou InfoCtx/ErrorCtx...), ele é anexado como
+// exemplar do contador e a entry recebe ExemplarField=true, permitindo ir do
+// gráfico no Grafana direto aos logs correspondentes:
+//
+//	metrics := lazylog.NewPrometheusHook("")
+//	logger.AddHook(metrics.Hook, true)
+//	http.Handle("/metrics", metrics)
+//
+// Não depende do client_golang: o handler escreve o formato texto diretamente
+// e pode ser raspado pelo Prometheus com exemplars habilitados.
+type PrometheusHook struct {
+	Namespace string // prefixo das métricas (padrão "lazylog")
+
+	mu        sync.Mutex
+	counts    map[Level]uint64
+	exemplars map[Level]promExemplar
+}
+
+type promExemplar struct {
+	traceID string
+	at      time.Time
+}
+
+// NewPrometheusHook cria um PrometheusHook com o namespace informado
+// ("" usa "lazylog").
+func NewPrometheusHook(namespace string) *PrometheusHook {
+	return &PrometheusHook{Namespace: namespace}
+}
+
+// Hook contabiliza a entry; registre-o como before-hook (AddHook(h.Hook, true))
+// para que ExemplarField chegue aos transportes.
+func (h *PrometheusHook) Hook(entry *Entry) {
+	traceID, _ := entry.Fields["trace_id"].(string)
+	h.mu.Lock()
+	if h.counts == nil {
+		h.counts = make(map[Level]uint64)
+		h.exemplars = make(map[Level]promExemplar)
+	}
+	h.counts[entry.Level]++
+	if traceID != "" {
+		h.exemplars[entry.Level] = promExemplar{traceID: traceID, at: entry.Timestamp}
+	}
+	h.mu.Unlock()
+	if traceID != "" {
+		if entry.Fields == nil {
+			entry.Fields = make(map[string]interface{})
+		}
+		entry.Fields[ExemplarField] = true
+	}
+}
+
+// ServeHTTP escreve as métricas no formato OpenMetrics.
+func (h *PrometheusHook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+	fmt.Fprint(w, h.Text())
+}
+
+// Text retorna as métricas no formato OpenMetrics (terminado em "# EOF").
+func (h *PrometheusHook) Text() string {
+	ns := h.Namespace
+	if ns == "" {
+		ns = "lazylog"
+	}
+	h.mu.Lock()
+	levels := make([]Level, 0, len(h.counts))
+	for lvl := range h.counts {
+		levels = append(levels, lvl)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	var b strings.Builder
+	fmt.Fprintf(&b, "# TYPE %s_entries counter\n", ns)
+	fmt.Fprintf(&b, "# HELP %s_entries Log entries by level.\n", ns)
+	for _, lvl := range levels {
+		fmt.Fprintf(&b, "%s_entries_total{level=%q} %d", ns, strings.ToLower(lvl.String()), h.counts[lvl])
+		if ex, ok := h.exemplars[lvl]; ok {
+			fmt.Fprintf(&b, " # {trace_id=%q} 1", ex.traceID)
+			if !ex.at.IsZero() {
+				fmt.Fprintf(&b, " %.3f", float64(ex.at.UnixMilli())/1000)
+			}
+		}
+		b.WriteByte('\n')
+	}
+	h.mu.Unlock()
+	b.WriteString("# EOF\n")
+	return b.String()
+}
+
+// StatsHandler expõe Logger.Stats no formato OpenMetrics: escritas, bytes e
+// erros por transporte (<ns>_transport_writes_total, _bytes_total e
+// _errors_total) e o maior tamanho de entry (<ns>_transport_max_entry_bytes),
+// com os rótulos index e transport. namespace vazio usa "lazylog".
+func (l *Logger) StatsHandler(namespace string) http.Handler {
+	if namespace == "" {
+		namespace = "lazylog"
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reports := l.Stats()
+		var b strings.Builder
+		metrics := []struct {
+			name, typ, help string
+			value           func(TransportStats) uint64
+		}{
+			{"transport_writes", "counter", "Successful transport writes.", func(s TransportStats) uint64 { return s.Writes }},
+			{"transport_bytes", "counter", "Formatted bytes written by transport.", func(s TransportStats) uint64 { return s.Bytes }},
+			{"transport_errors", "counter", "Failed transport writes.", func(s TransportStats) uint64 { return s.Errors }},
+			{"transport_max_entry_bytes", "gauge", "Largest entry written by transport.", func(s TransportStats) uint64 { return s.MaxEntry }},
+		}
+		for _, m := range metrics {
+			fmt.Fprintf(&b, "# TYPE %s_%s %s\n", namespace, m.name, m.typ)
+			fmt.Fprintf(&b, "# HELP %s_%s %s\n", namespace, m.name, m.help)
+			suffix := ""
+			if m.typ == "counter" {
+				suffix = "_total"
+			}
+			for _, r := range reports {
+				fmt.Fprintf(&b, "%s_%s%s{index=\"%d\",transport=%q} %d\n", namespace, m.name, suffix, r.Index, fmt.Sprintf("%T", r.Transport), m.value(r.Stats))
+			}
+		}
+		b.WriteString("# EOF\n")
+		w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+		fmt.Fprint(w, b.String())
+	})
+}
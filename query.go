@@ -0,0 +1,157 @@
+package lazylog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query descreve uma busca sobre logs guardados localmente (ver LogStore).
+type Query struct {
+	From, To    time.Time         // intervalo de Timestamp (zero: sem limite)
+	MinLevel    Level             // nível mínimo (valor zero: DEBUG; use TRACE para tudo)
+	FieldEquals map[string]string // campos exigidos, comparados via fmt.Sprint
+	TextSearch  string            // texto (sem diferenciar maiúsculas) na mensagem ou em chave=valor
+	Limit       int               // máximo de resultados (0: sem limite)
+}
+
+// Match indica se a entry satisfaz os critérios da consulta (Limit não é
+// considerado).
+func (q Query) Match(e *Entry) bool {
+	if e.Level < q.MinLevel {
+		return false
+	}
+	if !q.From.IsZero() && e.Timestamp.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && e.Timestamp.After(q.To) {
+		return false
+	}
+	for k, want := range q.FieldEquals {
+		v, ok := e.Fields[k]
+		if !ok || fmt.Sprint(v) != want {
+			return false
+		}
+	}
+	if q.TextSearch == "" {
+		return true
+	}
+	text := strings.ToLower(q.TextSearch)
+	if strings.Contains(strings.ToLower(e.Message), text) {
+		return true
+	}
+	for k, v := range e.Fields {
+		if strings.Contains(strings.ToLower(k+"="+fmt.Sprint(v)), text) {
+			return true
+		}
+	}
+	return false
+}
+
+// LogStore é implementado por destinos que guardam logs localmente e
+// permitem consultá-los, como RingBufferTransport e reader.FileStore.
+// Query retorna as entries que satisfazem q, das mais recentes para as mais
+// antigas, limitadas a q.Limit.
+type LogStore interface {
+	Query(ctx context.Context, q Query) ([]Entry, error)
+}
+
+// QueryHandler expõe um LogStore em HTTP para telas de busca em painéis
+// administrativos. GET com os parâmetros from e to (RFC 3339), level (nível
+// mínimo), field (chave:valor, pode repetir), q (texto) e limit (padrão 100)
+// responde {"entries": [...]} no formato de Entry.MarshalJSON:
+//
+//	http.Handle("/admin/logs", lazylog.QueryHandler(ring))
+//	// GET /admin/logs?level=WARN&field=user_id:42&q=timeout&limit=50
+func QueryHandler(store LogStore) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q, err := ParseQuery(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		entries, err := store.Query(r.Context(), q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if entries == nil {
+			entries = []Entry{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			Entries []Entry `json:"entries"`
+		}{entries})
+	})
+}
+
+// ParseQuery monta uma Query a partir dos parâmetros aceitos por
+// QueryHandler. Sem level, a consulta inclui TRACE; sem limit, usa 100.
+func ParseQuery(params map[string][]string) (Query, error) {
+	get := func(k string) string {
+		if v := params[k]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	q := Query{MinLevel: TRACE, TextSearch: get("q"), Limit: 100}
+	for _, k := range []string{"from", "to"} {
+		s := get(k)
+		if s == "" {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return q, fmt.Errorf("lazylog: invalid %s %q: %w", k, s, err)
+		}
+		if k == "from" {
+			q.From = ts
+		} else {
+			q.To = ts
+		}
+	}
+	if s := get("level"); s != "" {
+		lvl, ok := lookupLevel(s)
+		if !ok {
+			return q, fmt.Errorf("lazylog: unknown level %q", s)
+		}
+		q.MinLevel = lvl
+	}
+	for _, f := range params["field"] {
+		k, v, ok := strings.Cut(f, ":")
+		if !ok || k == "" {
+			return q, fmt.Errorf("lazylog: invalid field %q (use chave:valor)", f)
+		}
+		if q.FieldEquals == nil {
+			q.FieldEquals = make(map[string]string)
+		}
+		q.FieldEquals[k] = v
+	}
+	if s := get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return q, fmt.Errorf("lazylog: invalid limit %q", s)
+		}
+		q.Limit = n
+	}
+	return q, nil
+}
+
+// Query consulta as entries guardadas no buffer.
+func (r *RingBufferTransport) Query(ctx context.Context, q Query) ([]Entry, error) {
+	records := r.Records(0)
+	var out []Entry
+	for i := len(records) - 1; i >= 0; i-- {
+		if q.Limit > 0 && len(out) >= q.Limit {
+			break
+		}
+		if q.Match(&records[i].Entry) {
+			out = append(out, records[i].Entry)
+		}
+	}
+	return out, nil
+}
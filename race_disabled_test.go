@@ -0,0 +1,5 @@
+//go:build !race
+
+package lazylog_test
+
+const raceEnabled = false
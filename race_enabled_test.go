@@ -0,0 +1,7 @@
+//go:build race
+
+package lazylog_test
+
+// raceEnabled indica que os testes rodam com o race detector, que altera a
+// contagem de alocações.
+const raceEnabled = true
@@ -0,0 +1,87 @@
+package reader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/chmenegatti/lazylog"
+)
+
+// DefaultMaxBatchSize é o limite do payload descomprimido usado por
+// DecodeBatchFrame quando maxSize <= 0.
+const DefaultMaxBatchSize = 64 << 20
+
+// ErrBatchTooLarge indica que o payload descomprimido de um frame excede o
+// limite informado a DecodeBatchFrame (proteção contra zip bombs).
+var ErrBatchTooLarge = errors.New("reader: decompressed batch exceeds size limit")
+
+// DecompressorFunc cria um reader que descomprime os dados lidos de r.
+type DecompressorFunc func(r io.Reader) (io.ReadCloser, error)
+
+var (
+	decompressorMu sync.RWMutex
+	decompressors  = map[string]DecompressorFunc{
+		"gzip": func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+	}
+)
+
+// RegisterDecompressor registra o par de lazylog.RegisterCompressor usado por
+// DecodeBatchFrame.
+func RegisterDecompressor(encoding string, fn DecompressorFunc) {
+	decompressorMu.Lock()
+	defer decompressorMu.Unlock()
+	decompressors[encoding] = fn
+}
+
+// DecodeBatchFrame lê o header de um frame gerado por lazylog.EncodeBatchFrame
+// e retorna o payload já descomprimido. Payloads que descomprimidos passariam
+// de maxSize bytes (DefaultMaxBatchSize se maxSize <= 0) retornam
+// ErrBatchTooLarge sem serem lidos por completo.
+func DecodeBatchFrame(frame []byte, maxSize int64) (lazylog.BatchHeader, []byte, error) {
+	var h lazylog.BatchHeader
+	if len(frame) < 7 || string(frame[:4]) != lazylog.BatchFrameMagic {
+		return h, nil, lazylog.ErrInvalidBatchFrame
+	}
+	h.SchemaVersion = binary.BigEndian.Uint16(frame[4:6])
+	n := int(frame[6])
+	rest := frame[7:]
+	if len(rest) < n+4 {
+		return h, nil, lazylog.ErrInvalidBatchFrame
+	}
+	h.Compression = string(rest[:n])
+	size := binary.BigEndian.Uint32(rest[n : n+4])
+	payload := rest[n+4:]
+	if uint64(len(payload)) != uint64(size) {
+		return h, nil, lazylog.ErrInvalidBatchFrame
+	}
+	if h.Compression == "" {
+		return h, payload, nil
+	}
+	decompressorMu.RLock()
+	fn, ok := decompressors[h.Compression]
+	decompressorMu.RUnlock()
+	if !ok {
+		return h, nil, fmt.Errorf("reader: unknown compression %q", h.Compression)
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultMaxBatchSize
+	}
+	r, err := fn(bytes.NewReader(payload))
+	if err != nil {
+		return h, nil, err
+	}
+	defer r.Close()
+	out, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return h, nil, err
+	}
+	if int64(len(out)) > maxSize {
+		return h, nil, fmt.Errorf("%w (%d bytes)", ErrBatchTooLarge, maxSize)
+	}
+	return h, out, nil
+}
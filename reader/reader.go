@@ -0,0 +1,301 @@
+// Package reader lê arquivos de log gerados pelo lazylog de volta como
+// lazylog.Entry, para ferramentas de busca estruturada (lzlog tail) e
+// pós-processamento. Entende a saída do JSONFormatter e, em modo best-effort,
+// a do TextFormatter; Follow acompanha arquivos ativos como tail -F e
+// DecodeBatchFrame decodifica os frames de lazylog.EncodeBatchFrame.
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chmenegatti/lazylog"
+)
+
+// ParseLine converte uma linha de log numa entry, detectando o formato: linhas
+// JSON vão para ParseJSON e as demais para ParseText.
+func ParseLine(line []byte) lazylog.Entry {
+	line = bytes.TrimRight(line, "\r\n")
+	if e, ok := ParseJSON(line); ok {
+		return e
+	}
+	return ParseText(string(line))
+}
+
+// ParseJSON decodifica uma linha do JSONFormatter, com os campos no nível
+// raiz e desfazendo o prefixo "fields." que o formatter aplica às chaves
+// reservadas. Números são mantidos como json.Number, preservando o literal
+// original. Retorna false se a linha não for um objeto JSON.
+func ParseJSON(line []byte) (lazylog.Entry, bool) {
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return lazylog.Entry{}, false
+	}
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	dec.UseNumber()
+	var raw map[string]any
+	if err := dec.Decode(&raw); err != nil {
+		return lazylog.Entry{}, false
+	}
+	e := lazylog.Entry{Level: lazylog.INFO}
+	if ts, ok := raw["timestamp"].(string); ok {
+		e.Timestamp, _ = time.Parse(time.RFC3339Nano, ts)
+	}
+	if lvl, ok := raw["level"].(string); ok {
+		e.Level = lazylog.ParseLevel(lvl)
+	}
+	e.Message, _ = raw["message"].(string)
+	for k, v := range raw {
+		switch k {
+		case "timestamp", "level", "message":
+			continue
+		case "fields.timestamp", "fields.level", "fields.message":
+			k = strings.TrimPrefix(k, "fields.")
+		}
+		if e.Fields == nil {
+			e.Fields = make(map[string]any, len(raw))
+		}
+		e.Fields[k] = v
+	}
+	return e, true
+}
+
+// ParseText interpreta uma linha do TextFormatter: timestamp, [NÍVEL] e
+// mensagem são extraídos e os tokens chave=valor a partir do primeiro deles
+// viram campos (valores com espaços não são separados corretamente). Linhas
+// em outro formato viram uma entry INFO com a linha inteira como mensagem.
+func ParseText(line string) lazylog.Entry {
+	e := lazylog.Entry{Level: lazylog.INFO, Message: line}
+	tsText, rest, ok := strings.Cut(line, " ")
+	if !ok || !strings.HasPrefix(rest, "[") {
+		return e
+	}
+	tag, rest, _ := strings.Cut(rest[1:], "]")
+	if tag == "" {
+		return e
+	}
+	e.Timestamp, _ = time.Parse(time.RFC3339Nano, tsText)
+	e.Level = lazylog.ParseLevel(tag)
+	tokens := strings.Fields(rest)
+	msgEnd := len(tokens)
+	for i, tok := range tokens {
+		if k, _, ok := strings.Cut(tok, "="); ok && k != "" {
+			msgEnd = i
+			break
+		}
+	}
+	e.Message = strings.Join(tokens[:msgEnd], " ")
+	for _, tok := range tokens[msgEnd:] {
+		k, v, ok := strings.Cut(tok, "=")
+		if !ok || k == "" {
+			continue
+		}
+		if e.Fields == nil {
+			e.Fields = make(map[string]any)
+		}
+		e.Fields[k] = v
+	}
+	return e
+}
+
+// Scanner lê entries linha a linha de um io.Reader, ignorando linhas vazias:
+//
+//	s := reader.NewScanner(f)
+//	for s.Scan() {
+//		e := s.Entry()
+//		...
+//	}
+//	if err := s.Err(); err != nil { ... }
+type Scanner struct {
+	s     *bufio.Scanner
+	entry lazylog.Entry
+}
+
+// NewScanner cria um Scanner que aceita linhas de até 1 MiB.
+func NewScanner(r io.Reader) *Scanner {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	return &Scanner{s: s}
+}
+
+// Scan avança para a próxima entry, retornando false no fim ou em erro.
+func (s *Scanner) Scan() bool {
+	for s.s.Scan() {
+		if len(bytes.TrimSpace(s.s.Bytes())) == 0 {
+			continue
+		}
+		s.entry = ParseLine(s.s.Bytes())
+		return true
+	}
+	return false
+}
+
+// Entry retorna a entry lida pelo último Scan.
+func (s *Scanner) Entry() lazylog.Entry { return s.entry }
+
+// Line retorna a linha original do último Scan (válida até o próximo Scan).
+func (s *Scanner) Line() []byte { return s.s.Bytes() }
+
+// Err retorna o primeiro erro de leitura (io.EOF não é erro).
+func (s *Scanner) Err() error { return s.s.Err() }
+
+// FollowOptions configura Follow.
+type FollowOptions struct {
+	FromStart bool          // lê o conteúdo existente (padrão: começa no fim)
+	Poll      time.Duration // intervalo de verificação (padrão: 250ms)
+}
+
+// Follow chama fn para cada linha completa adicionada a path (sem o "\n" e
+// válida apenas durante a chamada) até ctx ser cancelado, como tail -F:
+// quando o arquivo é rotacionado (renomeado e recriado) o restante do arquivo
+// antigo é lido e a leitura continua no novo; quando é truncado
+// (copytruncate), a leitura recomeça do início. Enquanto path não existir,
+// Follow aguarda sua criação.
+func Follow(ctx context.Context, path string, opts FollowOptions, fn func(line []byte)) error {
+	if opts.Poll <= 0 {
+		opts.Poll = 250 * time.Millisecond
+	}
+	f, err := waitOpen(ctx, path, opts.Poll)
+	if f == nil {
+		return err
+	}
+	defer func() { f.Close() }()
+	if !opts.FromStart {
+		if _, err := f.Seek(0, io.SeekEnd); err != nil {
+			return err
+		}
+	}
+	br := bufio.NewReader(f)
+	var partial []byte
+	// drain entrega as linhas completas disponíveis, guardando a última
+	// linha incompleta em partial.
+	drain := func() error {
+		for {
+			chunk, err := br.ReadBytes('\n')
+			partial = append(partial, chunk...)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			fn(bytes.TrimRight(partial, "\r\n"))
+			partial = partial[:0]
+		}
+	}
+	for {
+		if err := drain(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.Poll):
+		}
+		st, err := os.Stat(path)
+		if err != nil {
+			continue // rotação em andamento: aguarda o novo arquivo
+		}
+		cur, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		if !os.SameFile(st, cur) {
+			if err := drain(); err != nil {
+				return err
+			}
+			if len(partial) > 0 {
+				fn(partial)
+				partial = partial[:0]
+			}
+			next, err := os.Open(path)
+			if err != nil {
+				continue
+			}
+			f.Close()
+			f = next
+			br.Reset(f)
+			continue
+		}
+		if pos, err := f.Seek(0, io.SeekCurrent); err == nil && st.Size() < pos {
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			br.Reset(f)
+			partial = partial[:0]
+		}
+	}
+}
+
+func waitOpen(ctx context.Context, path string, poll time.Duration) (*os.File, error) {
+	for {
+		f, err := os.Open(path)
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-time.After(poll):
+		}
+	}
+}
+
+// FileStore consulta arquivos de log gravados localmente (ex: pelo
+// FileTransport com JSONFormatter), implementando lazylog.LogStore. Paths
+// devem vir do mais antigo para o mais recente (ex: app.log.1, app.log); os
+// arquivos são relidos a cada consulta.
+type FileStore struct {
+	Paths []string
+}
+
+var _ lazylog.LogStore = (*FileStore)(nil)
+
+// Query retorna as entries que satisfazem q, das mais recentes para as mais
+// antigas.
+func (s *FileStore) Query(ctx context.Context, q lazylog.Query) ([]lazylog.Entry, error) {
+	var matched []lazylog.Entry
+	for _, path := range s.Paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		sc := NewScanner(f)
+		for n := 0; sc.Scan(); n++ {
+			if n%1024 == 0 && ctx.Err() != nil {
+				f.Close()
+				return nil, ctx.Err()
+			}
+			e := sc.Entry()
+			if !q.Match(&e) {
+				continue
+			}
+			matched = append(matched, e)
+			if q.Limit > 0 && len(matched) > 2*q.Limit {
+				// Guarda apenas as mais recentes para não acumular o arquivo inteiro.
+				matched = append(matched[:0], matched[len(matched)-q.Limit:]...)
+			}
+		}
+		err = sc.Err()
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched = matched[len(matched)-q.Limit:]
+	}
+	slices.Reverse(matched)
+	return matched, nil
+}
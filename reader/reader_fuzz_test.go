@@ -0,0 +1,49 @@
+package reader_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chmenegatti/lazylog"
+	"github.com/chmenegatti/lazylog/reader"
+)
+
+func FuzzReader(f *testing.F) {
+	f.Add([]byte(`{"timestamp":"2024-01-02T03:04:05Z","level":"WARN","message":"slow","fields.level":"x","n":1e400}`))
+	f.Add([]byte("2024-01-02T03:04:05Z [ERROR] falha user=cesar ms=12\n"))
+	f.Add([]byte("2024-01-02T03:04:05Z [ ] =\n[\n{\n\n"))
+	f.Add([]byte("\xff\xfe{\"message\":\"\xc3\x28\"}\r\n"))
+	f.Add([]byte(`{"level":{"nested":true},"timestamp":42,"message":null}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_ = reader.ParseLine(data)
+		s := reader.NewScanner(bytes.NewReader(data))
+		for s.Scan() {
+			_ = s.Entry()
+		}
+
+		// Toda linha do JSONFormatter volta com a mesma mensagem e nível.
+		msg := string(data)
+		if !utf8.ValidString(msg) {
+			return
+		}
+		entry := &lazylog.Entry{
+			Level:     lazylog.WARN,
+			Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Message:   msg,
+			Fields:    map[string]any{"level": msg, msg: "v"},
+		}
+		line, err := (&lazylog.JSONFormatter{}).Format(entry)
+		if err != nil {
+			t.Fatalf("json formatter returned error: %v", err)
+		}
+		e := reader.ParseLine(line)
+		if e.Level != lazylog.WARN || e.Message != msg || !e.Timestamp.Equal(entry.Timestamp) {
+			t.Fatalf("json round trip failed: %+v from %q", e, line)
+		}
+		if e.Fields["level"] != msg {
+			t.Fatalf("reserved field not restored: %v from %q", e.Fields, line)
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package reader_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chmenegatti/lazylog"
+	"github.com/chmenegatti/lazylog/reader"
+)
+
+func TestParseLine(t *testing.T) {
+	ts := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	entry := &lazylog.Entry{Level: lazylog.WARN, Timestamp: ts, Message: "slow query", Fields: map[string]any{
+		"user_id": 42, "level": "forged", "table": "users",
+	}}
+	line, err := (&lazylog.JSONFormatter{}).Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := reader.ParseLine(line)
+	if e.Level != lazylog.WARN || e.Message != "slow query" || !e.Timestamp.Equal(ts) {
+		t.Errorf("unexpected JSON entry: %+v", e)
+	}
+	if e.Fields["user_id"] != json.Number("42") || e.Fields["level"] != "forged" || e.Fields["table"] != "users" {
+		t.Errorf("unexpected JSON fields: %v", e.Fields)
+	}
+
+	line, err = (&lazylog.TextFormatter{}).Format(&lazylog.Entry{Level: lazylog.ERROR, Timestamp: ts, Message: "db down", Fields: map[string]any{"attempt": 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e = reader.ParseLine(line)
+	if e.Level != lazylog.ERROR || e.Message != "db down" || e.Fields["attempt"] != "3" || !e.Timestamp.Equal(ts) {
+		t.Errorf("unexpected text entry: %+v", e)
+	}
+
+	if e := reader.ParseLine([]byte("panic: runtime error")); e.Message != "panic: runtime error" || e.Level != lazylog.INFO {
+		t.Errorf("unexpected fallback entry: %+v", e)
+	}
+}
+
+func TestScanner(t *testing.T) {
+	s := reader.NewScanner(strings.NewReader("{\"level\":\"INFO\",\"message\":\"a\"}\n\n{\"level\":\"ERROR\",\"message\":\"b\"}\n"))
+	var got []string
+	for s.Scan() {
+		got = append(got, s.Entry().Level.String()+":"+s.Entry().Message)
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "INFO:a,ERROR:b" {
+		t.Errorf("unexpected entries: %v", got)
+	}
+}
+
+func TestFollowRotationAndTruncation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		mu    sync.Mutex
+		lines []string
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- reader.Follow(ctx, path, reader.FollowOptions{Poll: 5 * time.Millisecond}, func(line []byte) {
+			mu.Lock()
+			lines = append(lines, string(bytes.Clone(line)))
+			mu.Unlock()
+		})
+	}()
+	waitFor := func(want string) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			mu.Lock()
+			got := strings.Join(lines, ",")
+			mu.Unlock()
+			if got == want {
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		t.Fatalf("expected lines %q, got %q", want, strings.Join(lines, ","))
+	}
+	appendLine := func(s string) {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString(s)
+		f.Close()
+	}
+
+	time.Sleep(20 * time.Millisecond) // Follow começa no fim do arquivo
+	appendLine("one\n")
+	waitFor("one")
+	appendLine("two")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendLine("three\n")
+	waitFor("one,two,three")
+	if err := os.Truncate(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	appendLine("four\n")
+	waitFor("one,two,three,four")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileStoreQuery(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "app.log.1")
+	cur := filepath.Join(dir, "app.log")
+	os.WriteFile(old, []byte(`{"timestamp":"2025-03-01T12:00:00Z","level":"ERROR","message":"db down","user_id":42}`+"\n"), 0o644)
+	os.WriteFile(cur, []byte(`{"timestamp":"2025-03-01T12:01:00Z","level":"INFO","message":"login","user_id":42}
+{"timestamp":"2025-03-01T12:02:00Z","level":"ERROR","message":"db down again","user_id":42}
+{"timestamp":"2025-03-01T12:03:00Z","level":"ERROR","message":"disk full","user_id":7}
+`), 0o644)
+
+	store := &reader.FileStore{Paths: []string{old, cur}}
+	entries, err := store.Query(context.Background(), lazylog.Query{MinLevel: lazylog.ERROR, FieldEquals: map[string]string{"user_id": "42"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Message != "db down again" || entries[1].Message != "db down" {
+		t.Errorf("unexpected matches: %+v", entries)
+	}
+	entries, err = store.Query(context.Background(), lazylog.Query{TextSearch: "down", Limit: 1})
+	if err != nil || len(entries) != 1 || entries[0].Message != "db down again" {
+		t.Errorf("expected only the most recent match, got %+v (%v)", entries, err)
+	}
+}
+
+func TestBatchFrameRoundTrip(t *testing.T) {
+	payload := []byte(strings.Repeat(`{"level":"INFO","message":"hello"}`+"\n", 50))
+	for _, compression := range []string{"", "gzip"} {
+		frame, err := lazylog.EncodeBatchFrame(lazylog.BatchHeader{SchemaVersion: 3, Compression: compression}, payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, got, err := reader.DecodeBatchFrame(frame, 0)
+		if err != nil || h.SchemaVersion != 3 || h.Compression != compression || !bytes.Equal(got, payload) {
+			t.Errorf("round trip failed (%q): %+v %v", compression, h, err)
+		}
+	}
+	if _, _, err := reader.DecodeBatchFrame([]byte("nope"), 0); !errors.Is(err, lazylog.ErrInvalidBatchFrame) {
+		t.Errorf("expected ErrInvalidBatchFrame, got %v", err)
+	}
+}
+
+func TestBatchFrameSizeLimit(t *testing.T) {
+	payload := bytes.Repeat([]byte{'a'}, 1<<20)
+	frame, err := lazylog.EncodeBatchFrame(lazylog.BatchHeader{Compression: "gzip"}, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := reader.DecodeBatchFrame(frame, 1<<10); !errors.Is(err, reader.ErrBatchTooLarge) {
+		t.Errorf("expected ErrBatchTooLarge, got %v", err)
+	}
+	if _, got, err := reader.DecodeBatchFrame(frame, int64(len(payload))); err != nil || len(got) != len(payload) {
+		t.Errorf("payload at the limit must decode: %d bytes, %v", len(got), err)
+	}
+}
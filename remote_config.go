@@ -0,0 +1,383 @@
+package lazylog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultReloadGrace é o período de carência padrão antes de ApplyConfig
+// fechar os transportes substituídos; cobre o timeout do client criado por
+// NewHTTPClient.
+const DefaultReloadGrace = 10 * time.Second
+
+// ErrLoggerClosed é retornado por ApplyConfig depois de Close: os transportes
+// da nova configuração nunca seriam fechados.
+var ErrLoggerClosed = errors.New("lazylog: logger is closed")
+
+// SetReloadGrace define por quanto tempo ApplyConfig mantém abertos os
+// transportes substituídos, para que logs já em andamento (que usam o
+// conjunto anterior) terminem de escrever. d <= 0 fecha imediatamente.
+func (l *Logger) SetReloadGrace(d time.Duration) {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.reloadGrace, l.hasReloadGrace = d, true
+}
+
+// ApplyConfig reconfigura o logger em runtime: cria os transportes da nova
+// configuração, troca-os atomicamente e aplica PackageLevels. Os transportes
+// anteriores são descarregados (Flush) na hora e fechados após o período de
+// carência (ver SetReloadGrace) ou no Close do logger, o que vier primeiro;
+// erros ao fechá-los vão para o logger de diagnóstico. Em caso de erro o
+// logger permanece inalterado; depois de Close, retorna ErrLoggerClosed.
+func (l *Logger) ApplyConfig(cfg LoggerConfig) error {
+	l = l.core()
+	if l.nop {
+		return nil
+	}
+	cfg, err := cfg.Resolve("")
+	if err != nil {
+		return err
+	}
+	levels, err := parsePackageLevels(cfg.PackageLevels)
+	if err != nil {
+		return err
+	}
+	l.mu.RLock()
+	closed := l.closed
+	l.mu.RUnlock()
+	if closed {
+		return ErrLoggerClosed
+	}
+	transports, err := buildTransports(cfg)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	if l.closed {
+		// Close correu enquanto os transportes eram criados.
+		l.mu.Unlock()
+		for _, t := range transports {
+			closeTransport(t)
+		}
+		return ErrLoggerClosed
+	}
+	old := l.transports
+	l.transports = transports
+	l.pkgLevels = levels
+	l.hasPkgLevels.Store(len(levels) > 0)
+	l.refreshMinLevelLocked()
+	grace := DefaultReloadGrace
+	if l.hasReloadGrace {
+		grace = l.reloadGrace
+	}
+	deferred := grace > 0
+	if l.closing == nil {
+		l.closing = make(chan struct{})
+	}
+	closing := l.closing
+	if deferred {
+		l.retiring.Add(1)
+	}
+	l.mu.Unlock()
+
+	var errs []error
+	for _, t := range old {
+		if err := flushTransport(t); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if deferred {
+		go l.retireTransports(old, grace, closing)
+		return errors.Join(errs...)
+	}
+	for _, t := range old {
+		if err := closeTransport(t); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// retireTransports fecha os transportes substituídos por ApplyConfig após o
+// período de carência, ou antes, se o logger for fechado.
+func (l *Logger) retireTransports(old []Transport, grace time.Duration, closing <-chan struct{}) {
+	defer l.retiring.Done()
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-closing:
+	}
+	for _, t := range old {
+		if err := closeTransport(t); err != nil {
+			diagnose(WARN, "retired transport close failed", map[string]any{
+				"transport": fmt.Sprintf("%T", t),
+				"error":     err.Error(),
+			})
+		}
+	}
+}
+
+// ParseLoggerConfig interpreta uma configuração em JSON ou YAML.
+func ParseLoggerConfig(data []byte) (LoggerConfig, error) {
+	var cfg LoggerConfig
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		err := json.Unmarshal(trimmed, &cfg)
+		return cfg, err
+	}
+	err := yaml.Unmarshal(data, &cfg)
+	return cfg, err
+}
+
+// ConfigSource é uma fonte remota de configuração (ex: etcd, Consul). Watch
+// chama update com o conteúdo atual da chave e a cada alteração, até o ctx
+// ser cancelado ou ocorrer um erro irrecuperável.
+type ConfigSource interface {
+	Watch(ctx context.Context, update func(data []byte)) error
+}
+
+// WatchConfig observa a fonte e aplica cada nova configuração com
+// ApplyConfig, permitindo alterar níveis e transportes de uma frota inteira
+// sem redeploy. Configurações inválidas são repassadas a onError (se não nil)
+// e ignoradas. Bloqueia até o ctx ser cancelado ou a fonte falhar.
+func (l *Logger) WatchConfig(ctx context.Context, src ConfigSource, onError func(error)) error {
+	return src.Watch(ctx, func(data []byte) {
+		cfg, err := ParseLoggerConfig(data)
+		if err == nil {
+			err = l.ApplyConfig(cfg)
+		}
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	})
+}
+
+// ConsulSource observa uma chave do KV do Consul via blocking queries.
+type ConsulSource struct {
+	Address string // ex: "http://127.0.0.1:8500"
+	Key     string // ex: "config/lazylog"
+	Token   string // ACL token (opcional)
+	Client  *http.Client
+	Wait    time.Duration // duração máxima de cada blocking query (padrão 5m)
+	// Retry é a espera após falhas e após respostas que não avançam o
+	// X-Consul-Index (padrão 5s), para não repetir queries que não bloqueiam.
+	Retry time.Duration
+}
+
+// Watch implementa ConfigSource.
+func (s *ConsulSource) Watch(ctx context.Context, update func(data []byte)) error {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	wait, retry := s.Wait, s.Retry
+	if wait <= 0 {
+		wait = 5 * time.Minute
+	}
+	if retry <= 0 {
+		retry = 5 * time.Second
+	}
+	var index uint64
+	var last []byte
+	delivered := false
+	for {
+		u := fmt.Sprintf("%s/v1/kv/%s?raw&index=%d&wait=%ds", strings.TrimRight(s.Address, "/"),
+			strings.TrimLeft(s.Key, "/"), index, int(wait.Seconds()))
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return err
+		}
+		if s.Token != "" {
+			req.Header.Set("X-Consul-Token", s.Token)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err := sleepCtx(ctx, retry); err != nil {
+				return err
+			}
+			continue
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		newIndex, _ := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+		if err != nil || resp.StatusCode != http.StatusOK {
+			// 404: a chave ainda não existe; espera ela ser criada.
+			if resp.StatusCode == http.StatusNotFound && newIndex > 0 {
+				index = newIndex
+				continue
+			}
+			if err := sleepCtx(ctx, retry); err != nil {
+				return err
+			}
+			continue
+		}
+		if newIndex < index {
+			newIndex = 0 // índice regrediu (ex: snapshot restaurado)
+		}
+		changed := newIndex != index
+		if newIndex == 0 {
+			// Sem X-Consul-Index (ex: proxy que remove o header) não há como
+			// saber se a chave mudou: compara o conteúdo.
+			changed = !delivered || !bytes.Equal(body, last)
+		}
+		if changed {
+			update(body)
+			last, delivered = body, true
+		}
+		// Com o índice parado (ou ausente), a próxima query com o mesmo índice
+		// pode retornar na hora: espera antes de repeti-la.
+		stalled := newIndex == 0 || newIndex == index
+		index = newIndex
+		if stalled {
+			if err := sleepCtx(ctx, retry); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// EtcdSource observa uma chave do etcd v3 via gateway HTTP/JSON.
+type EtcdSource struct {
+	Endpoint string // ex: "http://127.0.0.1:2379"
+	Key      string
+	Client   *http.Client
+	Retry    time.Duration // espera após falhas (padrão 5s)
+}
+
+// Watch implementa ConfigSource: lê o valor atual e em seguida acompanha o
+// stream de watch a partir da revisão lida.
+func (s *EtcdSource) Watch(ctx context.Context, update func(data []byte)) error {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	retry := s.Retry
+	if retry <= 0 {
+		retry = 5 * time.Second
+	}
+	key := base64.StdEncoding.EncodeToString([]byte(s.Key))
+	for {
+		rev, err := s.read(ctx, client, key, update)
+		if err == nil {
+			err = s.watch(ctx, client, key, rev+1, update)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := sleepCtx(ctx, retry); err != nil {
+			return err
+		}
+	}
+}
+
+type etcdKV struct {
+	Value string `json:"value"`
+}
+
+// read busca o valor atual e retorna a revisão do cluster.
+func (s *EtcdSource) read(ctx context.Context, client *http.Client, key string, update func([]byte)) (int64, error) {
+	var out struct {
+		Header struct {
+			Revision string `json:"revision"`
+		} `json:"header"`
+		KVs []etcdKV `json:"kvs"`
+	}
+	body, _ := json.Marshal(map[string]string{"key": key})
+	if err := s.post(ctx, client, "/v3/kv/range", body, func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(&out)
+	}); err != nil {
+		return 0, err
+	}
+	if len(out.KVs) > 0 {
+		if data, err := base64.StdEncoding.DecodeString(out.KVs[0].Value); err == nil {
+			update(data)
+		}
+	}
+	return strconv.ParseInt(out.Header.Revision, 10, 64)
+}
+
+// watch consome o stream de eventos até ele ser encerrado.
+func (s *EtcdSource) watch(ctx context.Context, client *http.Client, key string, rev int64, update func([]byte)) error {
+	body, _ := json.Marshal(map[string]any{
+		"create_request": map[string]any{"key": key, "start_revision": strconv.FormatInt(rev, 10)},
+	})
+	return s.post(ctx, client, "/v3/watch", body, func(r io.Reader) error {
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for sc.Scan() {
+			var msg struct {
+				Result struct {
+					Events []struct {
+						Type string `json:"type"`
+						KV   etcdKV `json:"kv"`
+					} `json:"events"`
+				} `json:"result"`
+			}
+			if err := json.Unmarshal(sc.Bytes(), &msg); err != nil {
+				return err
+			}
+			for _, ev := range msg.Result.Events {
+				if ev.Type == "DELETE" {
+					continue
+				}
+				if data, err := base64.StdEncoding.DecodeString(ev.KV.Value); err == nil {
+					update(data)
+				}
+			}
+		}
+		return sc.Err()
+	})
+}
+
+func (s *EtcdSource) post(ctx context.Context, client *http.Client, path string, body []byte, handle func(io.Reader) error) error {
+	u, err := url.JoinPath(s.Endpoint, path)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("lazylog: etcd %s got status %d", path, resp.StatusCode)
+	}
+	return handle(resp.Body)
+}
+
+// sleepCtx espera d ou até o ctx ser cancelado.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
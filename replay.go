@@ -0,0 +1,38 @@
+package lazylog
+
+import (
+	"errors"
+	"time"
+)
+
+// LogAt registra uma entry com o timestamp informado pelo chamador, para
+// replay, importação de logs legados ou processamento em lote. Os formatters
+// sempre usam Entry.Timestamp, então o horário original é preservado.
+func (l *Logger) LogAt(ts time.Time, level Level, message string, fields map[string]interface{}) error {
+	return l.LogEntry(&Entry{Level: level, Timestamp: ts, Message: message, Fields: fields})
+}
+
+// LogEntry despacha uma entry já montada pelos transportes e hooks do logger,
+// preservando Timestamp (ou usando time.Now() se ele for zero) e FieldOrder.
+// A entry informada não é alterada.
+func (l *Logger) LogEntry(entry *Entry) error {
+	if !l.enabledFor(entry.Level) {
+		return nil
+	}
+	e := *entry
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	return dispatchEntry(l.snapshot(), &e, nil)
+}
+
+// Replay despacha as entries em ordem, combinando os erros de escrita.
+func (l *Logger) Replay(entries []*Entry) error {
+	var errs []error
+	for _, e := range entries {
+		if err := l.LogEntry(e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,102 @@
+package lazylog
+
+import (
+	"sync"
+)
+
+// RingBufferTransport mantém em memória as últimas entries registradas, para
+// inspeção sem infraestrutura (ver DebugHandler). Cada entry recebe um número
+// de sequência crescente, usado pelo live tail para retomar sem lacunas.
+type RingBufferTransport struct {
+	Level Level
+
+	mu      sync.Mutex
+	records []RingRecord // buffer circular
+	next    int
+	full    bool
+	seq     uint64
+	subs    map[chan RingRecord]struct{}
+}
+
+// RingRecord é uma entry guardada pelo RingBufferTransport.
+type RingRecord struct {
+	Seq   uint64 `json:"seq"`
+	Entry Entry  `json:"entry"`
+}
+
+// NewRingBufferTransport cria um RingBufferTransport com capacidade para size
+// entries (padrão: 1000).
+func NewRingBufferTransport(size int, level Level) *RingBufferTransport {
+	if size <= 0 {
+		size = 1000
+	}
+	return &RingBufferTransport{Level: level, records: make([]RingRecord, size)}
+}
+
+// WriteLog guarda uma cópia da entry e a entrega aos assinantes do live tail
+// (assinantes lentos perdem entries em vez de bloquear o logger).
+func (r *RingBufferTransport) WriteLog(entry *Entry) error {
+	e := entry.Clone()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	rec := RingRecord{Seq: r.seq, Entry: *e}
+	r.records[r.next] = rec
+	r.next = (r.next + 1) % len(r.records)
+	if r.next == 0 {
+		r.full = true
+	}
+	for ch := range r.subs {
+		select {
+		case ch <- rec:
+		default:
+		}
+	}
+	return nil
+}
+
+func (r *RingBufferTransport) MinLevel() Level {
+	return r.Level
+}
+
+// Records retorna as entries guardadas com sequência maior que after, da mais
+// antiga para a mais recente.
+func (r *RingBufferTransport) Records(after uint64) []RingRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.recordsLocked(after)
+}
+
+func (r *RingBufferTransport) recordsLocked(after uint64) []RingRecord {
+	var ordered []RingRecord
+	if r.full {
+		ordered = append(ordered, r.records[r.next:]...)
+	}
+	ordered = append(ordered, r.records[:r.next]...)
+	out := ordered[:0]
+	for _, rec := range ordered {
+		if rec.Seq > after {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// subscribe registra um assinante do live tail e retorna as entries já
+// guardadas após after, sem lacunas entre elas e as que chegarão pelo canal.
+func (r *RingBufferTransport) subscribe(after uint64) (chan RingRecord, []RingRecord) {
+	ch := make(chan RingRecord, 256)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.subs == nil {
+		r.subs = make(map[chan RingRecord]struct{})
+	}
+	r.subs[ch] = struct{}{}
+	return ch, r.recordsLocked(after)
+}
+
+func (r *RingBufferTransport) unsubscribe(ch chan RingRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.subs, ch)
+}
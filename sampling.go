@@ -0,0 +1,102 @@
+package lazylog
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SampleConfig define a amostragem de um nível: a cada segundo, as Initial
+// primeiras ocorrências de uma mesma mensagem são registradas e, depois
+// delas, uma a cada Thereafter (0 descarta todas as seguintes). Initial <= 0
+// desativa a amostragem do nível.
+type SampleConfig struct {
+	Initial    int
+	Thereafter int
+}
+
+type samplingKey struct {
+	level   Level
+	message string
+}
+
+type sampler struct {
+	defaults SampleConfig
+	perLevel map[Level]SampleConfig
+	dropped  atomic.Uint64
+
+	mu        sync.Mutex
+	tickStart time.Time
+	counts    map[samplingKey]int
+}
+
+// EnableSampling protege os transportes durante tempestades de log: a cada
+// segundo, as initial primeiras entries com o mesmo nível e mensagem são
+// registradas e, depois, apenas uma a cada thereafter. perLevel sobrescreve a
+// configuração de níveis específicos (ex: {lazylog.ERROR: {}} nunca amostra
+// erros). A amostragem é aplicada antes de hooks e transportes.
+func (l *Logger) EnableSampling(initial, thereafter int, perLevel map[Level]SampleConfig) {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	s := &sampler{
+		defaults: SampleConfig{Initial: initial, Thereafter: thereafter},
+		perLevel: make(map[Level]SampleConfig, len(perLevel)),
+		counts:   make(map[samplingKey]int),
+	}
+	for lvl, cfg := range perLevel {
+		s.perLevel[lvl] = cfg
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sampler = s
+}
+
+// DisableSampling desativa a amostragem.
+func (l *Logger) DisableSampling() {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sampler = nil
+}
+
+// SampledOut retorna quantas entries foram descartadas pela amostragem desde
+// o último EnableSampling.
+func (l *Logger) SampledOut() uint64 {
+	if s := l.snapshot().sampler; s != nil {
+		return s.dropped.Load()
+	}
+	return 0
+}
+
+// allow indica se a entry deve ser registrada.
+func (s *sampler) allow(level Level, message string) bool {
+	cfg, ok := s.perLevel[level]
+	if !ok {
+		cfg = s.defaults
+	}
+	if cfg.Initial <= 0 {
+		return true
+	}
+	now := time.Now()
+	s.mu.Lock()
+	if now.Sub(s.tickStart) >= time.Second {
+		// Novo intervalo: recomeça a contagem e libera as chaves antigas.
+		s.tickStart = now
+		clear(s.counts)
+	}
+	key := samplingKey{level: level, message: message}
+	s.counts[key]++
+	n := s.counts[key]
+	s.mu.Unlock()
+
+	if n <= cfg.Initial || (cfg.Thereafter > 0 && (n-cfg.Initial)%cfg.Thereafter == 0) {
+		return true
+	}
+	s.dropped.Add(1)
+	return false
+}
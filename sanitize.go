@@ -0,0 +1,85 @@
+package lazylog
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ansiEscape casa sequências CSI (ex: cores) e OSC (ex: títulos, hyperlinks).
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)?`)
+
+// SanitizeString remove sequências de escape ANSI, substitui UTF-8 inválido
+// por U+FFFD e neutraliza caracteres de controle: \n, \r e \t viram as
+// sequências literais "\n", "\r" e "\t"; os demais são removidos.
+func SanitizeString(s string) string {
+	if isCleanString(s) {
+		return s
+	}
+	s = strings.ToValidUTF8(s, "�")
+	s = ansiEscape.ReplaceAllString(s, "")
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case unicode.IsControl(r):
+			// descarta
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isCleanString indica se s é ASCII imprimível (caminho rápido, sem alocação).
+func isCleanString(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c >= 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// EnableSanitization passa a sanitizar (via SanitizeString) a mensagem, as
+// chaves e os valores string dos campos de toda entry antes da saída, para que
+// input malicioso não injete sequências de escape no terminal, quebre linhas
+// de log ou corrompa a saída JSON.
+func (l *Logger) EnableSanitization() {
+	l = l.core()
+	if l.nop {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sanitize = true
+}
+
+// sanitizeEntry aplica SanitizeString à mensagem e aos campos da entry.
+func sanitizeEntry(entry *Entry) {
+	entry.Message = SanitizeString(entry.Message)
+	entry.Fields = sanitizeFields(entry.Fields)
+}
+
+func sanitizeFields(fields map[string]interface{}) map[string]interface{} {
+	if len(fields) == 0 {
+		return fields
+	}
+	out := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		switch val := v.(type) {
+		case string:
+			v = SanitizeString(val)
+		case map[string]interface{}:
+			v = sanitizeFields(val)
+		}
+		out[SanitizeString(k)] = v
+	}
+	return out
+}
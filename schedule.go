@@ -0,0 +1,192 @@
+package lazylog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Schedule define janelas de tempo em que um transporte fica ativo (ver
+// ScheduledTransport). A entry é avaliada pelo seu Timestamp, convertido
+// para Location.
+type Schedule struct {
+	Windows  []TimeWindow
+	Location *time.Location // padrão: time.Local
+}
+
+// TimeWindow é uma janela recorrente (dias da semana + horário) ou, com From
+// e To definidos, um intervalo absoluto (ex: janela de manutenção).
+type TimeWindow struct {
+	Days       []time.Weekday // vazio = todos os dias
+	Start, End time.Duration  // desde a meia-noite; End < Start atravessa a meia-noite
+	From, To   time.Time      // intervalo absoluto [From, To)
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseSchedule interpreta janelas separadas por ";", cada uma no formato
+// "[dias] HH:MM-HH:MM" ou "AAAA-MM-DDTHH:MM/AAAA-MM-DDTHH:MM":
+//
+//	mon-fri 08:00-20:00                    // horário comercial
+//	sat,sun 10:00-14:00; mon-fri 22:00-06:00
+//	2025-03-01T22:00/2025-03-02T02:00      // janela de manutenção
+//
+// tz é um nome IANA (ex: "America/Sao_Paulo"); vazio usa o fuso local.
+func ParseSchedule(spec, tz string) (*Schedule, error) {
+	loc := time.Local
+	if tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, fmt.Errorf("lazylog: invalid schedule timezone %q: %w", tz, err)
+		}
+	}
+	s := &Schedule{Location: loc}
+	for _, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		w, err := parseTimeWindow(part, loc)
+		if err != nil {
+			return nil, err
+		}
+		s.Windows = append(s.Windows, w)
+	}
+	if len(s.Windows) == 0 {
+		return nil, fmt.Errorf("lazylog: empty schedule %q", spec)
+	}
+	return s, nil
+}
+
+func parseTimeWindow(spec string, loc *time.Location) (TimeWindow, error) {
+	if from, to, ok := strings.Cut(spec, "/"); ok {
+		const layout = "2006-01-02T15:04"
+		f, err1 := time.ParseInLocation(layout, strings.TrimSpace(from), loc)
+		t, err2 := time.ParseInLocation(layout, strings.TrimSpace(to), loc)
+		if err1 != nil || err2 != nil || !t.After(f) {
+			return TimeWindow{}, fmt.Errorf("lazylog: invalid schedule interval %q", spec)
+		}
+		return TimeWindow{From: f, To: t}, nil
+	}
+	var w TimeWindow
+	hours := spec
+	if days, rest, ok := strings.Cut(spec, " "); ok {
+		var err error
+		if w.Days, err = parseWeekdays(days); err != nil {
+			return TimeWindow{}, err
+		}
+		hours = strings.TrimSpace(rest)
+	}
+	start, end, ok := strings.Cut(hours, "-")
+	var err1, err2 error
+	w.Start, err1 = parseClock(start)
+	w.End, err2 = parseClock(end)
+	if !ok || err1 != nil || err2 != nil || w.Start == w.End {
+		return TimeWindow{}, fmt.Errorf("lazylog: invalid schedule window %q", spec)
+	}
+	return w, nil
+}
+
+// parseWeekdays aceita "*", "mon-fri" e listas como "mon,wed,fri".
+func parseWeekdays(spec string) ([]time.Weekday, error) {
+	if spec == "*" {
+		return nil, nil
+	}
+	var days []time.Weekday
+	for _, item := range strings.Split(strings.ToLower(spec), ",") {
+		from, to, isRange := strings.Cut(item, "-")
+		first, ok1 := weekdayNames[from]
+		last, ok2 := first, true
+		if isRange {
+			last, ok2 = weekdayNames[to]
+		}
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("lazylog: invalid schedule days %q", spec)
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			days = append(days, d)
+			if d == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Active informa se t cai em alguma das janelas.
+func (s *Schedule) Active(t time.Time) bool {
+	loc := s.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	t = t.In(loc)
+	for _, w := range s.Windows {
+		if w.active(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w TimeWindow) active(t time.Time) bool {
+	if !w.From.IsZero() {
+		return !t.Before(w.From) && t.Before(w.To)
+	}
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if w.Start < w.End {
+		return offset >= w.Start && offset < w.End && w.hasDay(t.Weekday())
+	}
+	// Atravessa a meia-noite: a madrugada pertence à janela do dia anterior.
+	if offset >= w.Start {
+		return w.hasDay(t.Weekday())
+	}
+	return offset < w.End && w.hasDay((t.Weekday()+6)%7)
+}
+
+func (w TimeWindow) hasDay(d time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, day := range w.Days {
+		if day == d {
+			return true
+		}
+	}
+	return false
+}
+
+// ScheduledTransport é um decorator que só repassa as entries cujo Timestamp
+// cai no Schedule (ex: alertas no Slack apenas em horário comercial, arquivo
+// DEBUG apenas durante uma janela de manutenção). Em LoggerConfig, use
+// Schedule e Timezone no TransportConfig.
+type ScheduledTransport struct {
+	Transport Transport
+	Schedule  *Schedule
+}
+
+func (t *ScheduledTransport) WriteLog(entry *Entry) error {
+	if t.Schedule != nil && !t.Schedule.Active(entry.Timestamp) {
+		return nil
+	}
+	return t.Transport.WriteLog(entry)
+}
+
+func (t *ScheduledTransport) MinLevel() Level {
+	return t.Transport.MinLevel()
+}
+
+// Close fecha o transporte envolvido (se ele implementar io.Closer).
+func (t *ScheduledTransport) Close() error {
+	return closeTransport(t.Transport)
+}
@@ -0,0 +1,106 @@
+package lazylog
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// FieldKind é o tipo esperado de um campo em um EventSchema.
+type FieldKind string
+
+const (
+	KindAny    FieldKind = "any"
+	KindString FieldKind = "string"
+	KindNumber FieldKind = "number"
+	KindBool   FieldKind = "bool"
+	KindObject FieldKind = "object"
+)
+
+// EventSchema define o contrato de um evento: campos obrigatórios e o tipo de
+// cada campo conhecido.
+type EventSchema struct {
+	Required []string
+	Fields   map[string]FieldKind
+}
+
+// LogSchema mapeia nomes de evento para seus contratos. O nome do evento é o
+// campo "event" (se string) ou, na ausência dele, a mensagem.
+type LogSchema map[string]EventSchema
+
+// SchemaViolationHandler recebe as violações de uma entry (ex: t.Error em CI).
+type SchemaViolationHandler func(entry *Entry, violations []string)
+
+// EnableSchemaValidation registra um before-hook que valida as entries contra
+// o schema, pensado para desenvolvimento/CI. Entries fora do contrato recebem
+// o campo "_schema_violations" e são repassadas a onViolation (se não nil).
+// Eventos que não constam no schema não são validados.
+func (l *Logger) EnableSchemaValidation(schema LogSchema, onViolation SchemaViolationHandler) {
+	l.AddHook(func(entry *Entry) {
+		name := EventName(entry)
+		if name == "" {
+			name = entry.Message
+		}
+		es, ok := schema[name]
+		if !ok {
+			return
+		}
+		violations := es.Validate(entry.Fields)
+		if len(violations) == 0 {
+			return
+		}
+		if entry.Fields == nil {
+			entry.Fields = make(map[string]interface{})
+		}
+		entry.Fields["_schema_violations"] = violations
+		if onViolation != nil {
+			onViolation(entry, violations)
+		}
+	}, true)
+}
+
+// Validate retorna as violações de fields em relação ao schema.
+func (s EventSchema) Validate(fields map[string]interface{}) []string {
+	var violations []string
+	for _, k := range s.Required {
+		if _, ok := fields[k]; !ok {
+			violations = append(violations, fmt.Sprintf("missing required field %q", k))
+		}
+	}
+	keys := make([]string, 0, len(s.Fields))
+	for k := range s.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, ok := fields[k]
+		if !ok || matchesKind(v, s.Fields[k]) {
+			continue
+		}
+		violations = append(violations, fmt.Sprintf("field %q: expected %s, got %T", k, s.Fields[k], v))
+	}
+	return violations
+}
+
+// matchesKind indica se v é compatível com o tipo esperado.
+func matchesKind(v interface{}, kind FieldKind) bool {
+	if kind == KindAny || kind == "" {
+		return true
+	}
+	if v == nil {
+		return false
+	}
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.String:
+		return kind == KindString
+	case reflect.Bool:
+		return kind == KindBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return kind == KindNumber
+	case reflect.Map, reflect.Struct:
+		return kind == KindObject
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+#!/usr/bin/env sh
+# Roda a suíte de benchmarks em formato compatível com benchstat.
+#
+# Uso:
+#   scripts/bench.sh old.txt            # grava a baseline (ex: na main)
+#   scripts/bench.sh new.txt old.txt    # grava e compara com a baseline
+set -e
+
+OUT="${1:-bench.txt}"
+BASE="$2"
+COUNT="${BENCH_COUNT:-10}"
+
+go test -run='^$' -bench="${BENCH_PATTERN:-.}" -benchmem -count="$COUNT" . | tee "$OUT"
+
+if [ -n "$BASE" ]; then
+	if command -v benchstat >/dev/null 2>&1; then
+		benchstat "$BASE" "$OUT"
+	else
+		echo "benchstat não encontrado: go install golang.org/x/perf/cmd/benchstat@latest" >&2
+		exit 1
+	fi
+fi
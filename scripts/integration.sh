@@ -0,0 +1,29 @@
+#!/usr/bin/env sh
+# Sobe os sinks da suíte de integração em containers e roda os testes com a
+# build tag "integration".
+#
+# Uso:
+#   scripts/integration.sh
+set -e
+
+ES_IMAGE="${ES_IMAGE:-docker.elastic.co/elasticsearch/elasticsearch:8.15.0}"
+ES_PORT="${ES_PORT:-19200}"
+
+cleanup() {
+	docker rm -f lazylog-it-es >/dev/null 2>&1 || true
+}
+trap cleanup EXIT
+
+docker run -d --name lazylog-it-es -p "$ES_PORT:9200" \
+	-e discovery.type=single-node -e xpack.security.enabled=false \
+	-e ES_JAVA_OPTS="-Xms512m -Xmx512m" "$ES_IMAGE" >/dev/null
+
+echo "aguardando elasticsearch..."
+for _ in $(seq 1 60); do
+	if curl -fs "http://localhost:$ES_PORT/_cluster/health" >/dev/null 2>&1; then
+		break
+	fi
+	sleep 2
+done
+
+LAZYLOG_IT_ES_URL="http://localhost:$ES_PORT" go test -tags integration -run '^TestIntegration' -count=1 -v .
@@ -0,0 +1,162 @@
+package lazylog
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSpoolBatchSize é o tamanho de segmento usado quando BatchSize é zero.
+const DefaultSpoolBatchSize = 100
+
+// SpoolTransport acumula entries em segmentos no disco (Dir/<id>.seg) e os
+// entrega em lote ao Target a cada BatchSize entries ou em Flush/Close.
+// Cada entrega registra em <id>.seg.ack quantas entries do segmento já foram
+// aceitas pelo Target, de forma que uma entrega interrompida (crash, erro do
+// Target) é retomada do ponto em que parou, sem duplicar as anteriores.
+// Segmentos cuja entrega falhou permanecem no disco até a próxima chamada a
+// Recover (feita automaticamente por NewSpoolTransport na inicialização).
+type SpoolTransport struct {
+	Target    Transport
+	Dir       string
+	BatchSize int
+
+	mu    sync.Mutex
+	seg   *os.File
+	path  string
+	count int
+}
+
+// NewSpoolTransport cria o diretório de spool e executa Recover, reenviando
+// segmentos órfãos de uma execução anterior.
+func NewSpoolTransport(target Transport, dir string, batchSize int) (*SpoolTransport, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	s := &SpoolTransport{Target: target, Dir: dir, BatchSize: batchSize}
+	return s, s.Recover()
+}
+
+func (s *SpoolTransport) WriteLog(entry *Entry) error {
+	line, err := marshalForwarded(entry)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seg == nil {
+		s.path = filepath.Join(s.Dir, fmt.Sprintf("%020d.seg", time.Now().UnixNano()))
+		if s.seg, err = os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644); err != nil {
+			s.seg = nil
+			return err
+		}
+	}
+	if _, err := s.seg.Write(line); err != nil {
+		return err
+	}
+	s.count++
+	batch := s.BatchSize
+	if batch <= 0 {
+		batch = DefaultSpoolBatchSize
+	}
+	if s.count >= batch {
+		return s.flushLocked()
+	}
+	return nil
+}
+
+func (s *SpoolTransport) MinLevel() Level {
+	return s.Target.MinLevel()
+}
+
+// Flush entrega o segmento atual ao Target e descarrega o Target.
+func (s *SpoolTransport) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.flushLocked(); err != nil {
+		return err
+	}
+	return flushTransport(s.Target)
+}
+
+func (s *SpoolTransport) flushLocked() error {
+	if s.seg == nil {
+		return nil
+	}
+	err := s.seg.Close()
+	path := s.path
+	s.seg, s.path, s.count = nil, "", 0
+	if err != nil {
+		return err
+	}
+	return s.deliver(path)
+}
+
+// Recover reenvia ao Target os segmentos existentes em Dir que não foram
+// totalmente entregues (ex: após um crash), em ordem de criação.
+func (s *SpoolTransport) Recover() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	paths, err := filepath.Glob(filepath.Join(s.Dir, "*.seg"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+	var errs []error
+	for _, p := range paths {
+		if p == s.path {
+			continue
+		}
+		if err := s.deliver(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliver envia as entries ainda não confirmadas do segmento e o remove ao final.
+func (s *SpoolTransport) deliver(path string) error {
+	ackPath := path + ".ack"
+	acked := 0
+	if data, err := os.ReadFile(ackPath); err == nil {
+		acked, _ = strconv.Atoi(strings.TrimSpace(string(data)))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for i := 0; sc.Scan(); i++ {
+		if i < acked {
+			continue
+		}
+		// Linhas corrompidas (escrita interrompida) são descartadas.
+		if entry, err := unmarshalForwarded(sc.Bytes()); err == nil {
+			if err := s.Target.WriteLog(&entry); err != nil {
+				return err
+			}
+		}
+		if err := os.WriteFile(ackPath, []byte(strconv.Itoa(i+1)), 0o644); err != nil {
+			return err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	os.Remove(ackPath)
+	return os.Remove(path)
+}
+
+// Close entrega o segmento pendente e fecha o Target.
+func (s *SpoolTransport) Close() error {
+	return errors.Join(s.Flush(), closeTransport(s.Target))
+}
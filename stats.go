@@ -0,0 +1,190 @@
+package lazylog
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TransportStats são os contadores de um transporte desde sua criação.
+type TransportStats struct {
+	Writes      uint64    // escritas bem-sucedidas
+	Bytes       uint64    // bytes gravados nas escritas bem-sucedidas
+	MaxEntry    uint64    // maior entry gravada, em bytes (média: Bytes/Writes)
+	Errors      uint64    // escritas que falharam
+	LastWriteAt time.Time // última escrita bem-sucedida
+	LastError   error
+	LastErrorAt time.Time
+	// Recoveries conta as vezes em que o transporte voltou a escrever após
+	// uma sequência de falhas; LastDowntime é a duração da última.
+	Recoveries   uint64
+	LastDowntime time.Duration
+}
+
+// StatsReporter é implementado por transportes que mantêm estatísticas de
+// escrita. Os transportes embutidos (Writer, Console, File, Lumberjack, HTTP,
+// Forward e Batch) o implementam.
+type StatsReporter interface {
+	Stats() TransportStats
+}
+
+// transportStats acumula as estatísticas de um transporte (embutido por valor
+// nos transportes; o valor zero está pronto para uso).
+type transportStats struct {
+	writes   atomic.Uint64
+	bytes    atomic.Uint64
+	errors   atomic.Uint64
+	maxEntry atomic.Uint64
+
+	mu           sync.Mutex
+	lastWriteAt  time.Time
+	lastErr      error
+	lastErrAt    time.Time
+	outageSince  time.Time // primeira falha da sequência atual (zero se saudável)
+	outageFailed uint64    // escritas que falharam desde outageSince
+	recoveries   uint64
+	lastDowntime time.Duration
+}
+
+// record contabiliza uma escrita de n bytes em t com o resultado err. Na
+// primeira escrita bem-sucedida após uma sequência de falhas, emite uma
+// entry de recuperação pelo caminho de diagnóstico (SetDiagnostics).
+func (s *transportStats) record(t Transport, n int, err error) {
+	now := time.Now()
+	if err != nil {
+		s.errors.Add(1)
+		s.mu.Lock()
+		s.lastErr, s.lastErrAt = err, now
+		if s.outageSince.IsZero() {
+			s.outageSince = now
+		}
+		s.outageFailed++
+		s.mu.Unlock()
+		return
+	}
+	s.writes.Add(1)
+	if n > 0 {
+		s.bytes.Add(uint64(n))
+		for {
+			cur := s.maxEntry.Load()
+			if uint64(n) <= cur || s.maxEntry.CompareAndSwap(cur, uint64(n)) {
+				break
+			}
+		}
+	}
+	s.mu.Lock()
+	s.lastWriteAt = now
+	var recovered map[string]any
+	if !s.outageSince.IsZero() {
+		s.recoveries++
+		s.lastDowntime = now.Sub(s.outageSince)
+		recovered = map[string]any{
+			"transport":   fmt.Sprintf("%T", t),
+			"downtime_ms": float64(s.lastDowntime) / float64(time.Millisecond),
+			"dropped":     s.outageFailed,
+			"last_error":  s.lastErr.Error(),
+		}
+		s.outageSince, s.outageFailed = time.Time{}, 0
+	}
+	s.mu.Unlock()
+	if recovered != nil {
+		diagnose(INFO, "lazylog: transport recovered", recovered)
+	}
+}
+
+func (s *transportStats) snapshot() TransportStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return TransportStats{
+		Writes:       s.writes.Load(),
+		Bytes:        s.bytes.Load(),
+		MaxEntry:     s.maxEntry.Load(),
+		Errors:       s.errors.Load(),
+		LastWriteAt:  s.lastWriteAt,
+		LastError:    s.lastErr,
+		LastErrorAt:  s.lastErrAt,
+		Recoveries:   s.recoveries,
+		LastDowntime: s.lastDowntime,
+	}
+}
+
+func (w *WriterTransport) Stats() TransportStats     { return w.stats.snapshot() }
+func (c *ConsoleTransport) Stats() TransportStats    { return c.stats.snapshot() }
+func (f *FileTransport) Stats() TransportStats       { return f.stats.snapshot() }
+func (l *LumberjackTransport) Stats() TransportStats { return l.stats.snapshot() }
+func (h *HTTPTransport) Stats() TransportStats       { return h.stats.snapshot() }
+func (f *ForwardTransport) Stats() TransportStats    { return f.stats.snapshot() }
+func (t *BatchTransport) Stats() TransportStats      { return t.stats.snapshot() }
+
+// TransportStatsReport associa as estatísticas ao transporte do logger.
+type TransportStatsReport struct {
+	Index     int       // posição do transporte no logger
+	Transport Transport // transporte registrado (pode ser um decorator)
+	Stats     TransportStats
+}
+
+// Stats retorna as estatísticas de cada transporte do logger que as mantém
+// (descendo por decorators como AsyncTransport e TransportWithFilter até o
+// transporte que efetivamente escreve).
+func (l *Logger) Stats() []TransportStatsReport {
+	var reports []TransportStatsReport
+	for i, t := range l.snapshot().transports {
+		if r, ok := findStatsReporter(t); ok {
+			reports = append(reports, TransportStatsReport{Index: i, Transport: t, Stats: r.Stats()})
+		}
+	}
+	return reports
+}
+
+// Health retorna um erro para cada transporte cuja escrita mais recente
+// falhou (unidos com errors.Join), ou nil se todos estiverem saudáveis.
+func (l *Logger) Health() error {
+	var errs []error
+	for _, r := range l.Stats() {
+		if r.Stats.LastError != nil && r.Stats.LastErrorAt.After(r.Stats.LastWriteAt) {
+			errs = append(errs, fmt.Errorf("lazylog: transport #%d (%T) last write failed at %s: %w",
+				r.Index, r.Transport, r.Stats.LastErrorAt.Format(time.RFC3339), r.Stats.LastError))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func findStatsReporter(t Transport) (StatsReporter, bool) {
+	for {
+		if r, ok := t.(StatsReporter); ok {
+			return r, true
+		}
+		inner, ok := unwrapTransport(t)
+		if !ok {
+			return nil, false
+		}
+		t = inner
+	}
+}
+
+// unwrapTransport retorna o transporte envolvido pelos decorators conhecidos.
+func unwrapTransport(t Transport) (Transport, bool) {
+	switch v := t.(type) {
+	case *AsyncTransport:
+		return v.Transport, true
+	case *TransportWithFilter:
+		return v.Transport, true
+	case *ScheduledTransport:
+		return v.Transport, true
+	case *CostGuardTransport:
+		return v.Transport, true
+	case *VolumeTransport:
+		return v.Transport, true
+	case *FeatureFlagTransport:
+		return v.Transport, true
+	case *TracingTransport:
+		return v.Transport, true
+	case *AggregatingTransport:
+		return v.Target, true
+	case *SpoolTransport:
+		return v.Target, true
+	}
+	return nil, false
+}
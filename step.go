@@ -0,0 +1,65 @@
+package lazylog
+
+import (
+	"sync"
+	"time"
+)
+
+// StepHandle representa uma etapa em andamento iniciada por Logger.Step.
+// Os métodos são seguros para receiver nil (retornado por loggers Nop).
+type StepHandle struct {
+	logger *Logger
+	name   string
+	fields map[string]any
+	start  time.Time
+	once   sync.Once
+}
+
+// Step registra o início de uma etapa (ex: "migrate db") e retorna um handle
+// cujo Done registra o fim com duração e resultado, padronizando o log de
+// tarefas em CLIs e jobs batch:
+//
+//	step := logger.Step("migrate db")
+//	err := migrate()
+//	step.Done(err)
+func (l *Logger) Step(name string, fields ...map[string]any) *StepHandle {
+	if l.core().nop {
+		return nil
+	}
+	flds := map[string]any{"step": name}
+	if len(fields) > 0 {
+		for k, v := range fields[0] {
+			flds[k] = v
+		}
+	}
+	s := &StepHandle{logger: l, name: name, fields: flds, start: time.Now()}
+	l.logWithFields(INFO, name+" started", withField(flds, "status", "started"))
+	return s
+}
+
+// Elapsed retorna o tempo decorrido desde o início da etapa.
+func (s *StepHandle) Elapsed() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return time.Since(s.start)
+}
+
+// Done registra o fim da etapa: INFO com status=success se err for nil, ou
+// ERROR com status=failure e o campo error. Chamadas repetidas são ignoradas.
+func (s *StepHandle) Done(err error) {
+	if s == nil {
+		return
+	}
+	s.once.Do(func() {
+		flds := withField(s.fields, "elapsed_ms", float64(s.Elapsed())/float64(time.Millisecond))
+		if err != nil {
+			flds["status"] = "failure"
+			flds["error"] = err.Error()
+			s.logger.logWithFields(ERROR, s.name+" failed", flds)
+			return
+		}
+		flds["status"] = "success"
+		s.logger.logWithFields(INFO, s.name+" finished", flds)
+	})
+}
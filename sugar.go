@@ -0,0 +1,73 @@
+package lazylog
+
+import "fmt"
+
+// BadKey é a chave usada pelas variantes *w para um valor sem par (número
+// ímpar de argumentos).
+const BadKey = "!BADKEY"
+
+// Tracew registra uma mensagem TRACE com pares chave/valor alternados.
+func (l *Logger) Tracew(msg string, keysAndValues ...any) {
+	l.logw(TRACE, msg, keysAndValues)
+}
+
+// Debugw registra uma mensagem DEBUG com pares chave/valor alternados.
+func (l *Logger) Debugw(msg string, keysAndValues ...any) {
+	l.logw(DEBUG, msg, keysAndValues)
+}
+
+// Infow registra uma mensagem INFO com pares chave/valor alternados, sem
+// montar um mapa no ponto de chamada:
+//
+//	logger.Infow("pedido criado", "order_id", 42, "customer", "acme")
+func (l *Logger) Infow(msg string, keysAndValues ...any) {
+	l.logw(INFO, msg, keysAndValues)
+}
+
+// Warnw registra uma mensagem WARN com pares chave/valor alternados.
+func (l *Logger) Warnw(msg string, keysAndValues ...any) {
+	l.logw(WARN, msg, keysAndValues)
+}
+
+// Errorw registra uma mensagem ERROR com pares chave/valor alternados.
+func (l *Logger) Errorw(msg string, keysAndValues ...any) {
+	l.logw(ERROR, msg, keysAndValues)
+}
+
+// logw converte os pares em campos tipados, na ordem informada. Um valor sem
+// par vai em BadKey; chaves que não são string são convertidas com
+// fmt.Sprint; um Field isolado é aceito no lugar de um par.
+func (l *Logger) logw(level Level, msg string, keysAndValues []any) {
+	if !l.enabledFor(level) {
+		return
+	}
+	l.logWithFieldSlice(level, msg, keyValueFields(keysAndValues))
+}
+
+func keyValueFields(keysAndValues []any) []Field {
+	if len(keysAndValues) == 0 {
+		return nil
+	}
+	fields := make([]Field, 0, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i++ {
+		switch k := keysAndValues[i].(type) {
+		case Field:
+			fields = append(fields, k)
+			continue
+		case string:
+			if i+1 < len(keysAndValues) {
+				fields = append(fields, Field{Key: k, Value: keysAndValues[i+1]})
+				i++
+				continue
+			}
+		default:
+			if i+1 < len(keysAndValues) {
+				fields = append(fields, Field{Key: fmt.Sprint(k), Value: keysAndValues[i+1]})
+				i++
+				continue
+			}
+		}
+		fields = append(fields, Field{Key: BadKey, Value: keysAndValues[i]})
+	}
+	return fields
+}
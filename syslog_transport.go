@@ -34,12 +34,14 @@ func (s *SyslogTransport) WriteLog(entry *Entry) error {
 	}
 	msg := string(bytes)
 	switch entry.Level {
-	case DEBUG:
+	case TRACE, DEBUG:
 		return s.Writer.Debug(msg)
 	case WARN:
 		return s.Writer.Warning(msg)
 	case ERROR:
 		return s.Writer.Err(msg)
+	case PANIC, FATAL:
+		return s.Writer.Crit(msg)
 	default:
 		return s.Writer.Info(msg)
 	}
@@ -0,0 +1,35 @@
+package lazylog
+
+import "time"
+
+// Stopwatch mede durações com nomes de campo e unidade padronizados
+// (milissegundos, float64).
+type Stopwatch struct {
+	start time.Time
+}
+
+// Timer inicia um Stopwatch:
+//
+//	timer := lazylog.Timer()
+//	defer logger.InfoFields("handler done", timer.Elapsed())
+func Timer() *Stopwatch {
+	return &Stopwatch{start: time.Now()}
+}
+
+// Duration retorna o tempo decorrido desde o início.
+func (s *Stopwatch) Duration() time.Duration {
+	return time.Since(s.start)
+}
+
+// Elapsed retorna o campo "elapsed_ms". O valor é calculado quando a entry é
+// despachada, então funciona corretamente com defer.
+func (s *Stopwatch) Elapsed() Field {
+	return s.ElapsedAs("elapsed_ms")
+}
+
+// ElapsedAs é como Elapsed, com nome de campo customizado.
+func (s *Stopwatch) ElapsedAs(key string) Field {
+	return Field{Key: key, Value: LazyValue(func() any {
+		return float64(s.Duration()) / float64(time.Millisecond)
+	})}
+}
@@ -0,0 +1,67 @@
+package lazylog
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSConfigOptions reúne as opções de TLS/mTLS dos transportes HTTP
+// (HTTPTransport e clientes criados por NewHTTPClient), podendo ser expressas
+// em LoggerConfig (campo TLS). O SyslogTransport usa o syslog local e não as
+// consome.
+type TLSConfigOptions struct {
+	CAFile             string `yaml:"CAFile"`   // CA para validar o servidor
+	CertFile           string `yaml:"CertFile"` // certificado do cliente (mTLS)
+	KeyFile            string `yaml:"KeyFile"`  // chave do cliente (mTLS)
+	ServerName         string `yaml:"ServerName"`
+	InsecureSkipVerify bool   `yaml:"InsecureSkipVerify"`
+	MinVersion         string `yaml:"MinVersion"` // "1.0", "1.1", "1.2" ou "1.3"
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSConfig constrói um *tls.Config a partir das opções. Retorna nil, nil
+// quando o receiver é nil, permitindo que transportes tratem TLS como opcional.
+func (o *TLSConfigOptions) TLSConfig() (*tls.Config, error) {
+	if o == nil {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		ServerName:         o.ServerName,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+	if o.MinVersion != "" {
+		v, ok := tlsVersions[o.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("lazylog: unknown TLS version %q", o.MinVersion)
+		}
+		cfg.MinVersion = v
+	}
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("lazylog: no certificates found in %q", o.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if o.CertFile != "" || o.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,74 @@
+package lazylog
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// TraceContext é o contexto de trace W3C (https://www.w3.org/TR/trace-context/)
+// recebido nos headers traceparent e tracestate.
+type TraceContext struct {
+	TraceID    string // 32 dígitos hexadecimais
+	SpanID     string // 16 dígitos hexadecimais (parent-id do chamador)
+	Sampled    bool
+	TraceState string // conteúdo bruto de tracestate, se houver
+}
+
+// ParseTraceparent interpreta um header traceparent
+// ("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"). Versões
+// futuras são aceitas desde que os quatro primeiros campos sejam válidos.
+func ParseTraceparent(value string) (TraceContext, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return TraceContext{}, fmt.Errorf("lazylog: invalid traceparent %q", value)
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	switch {
+	case !isLowerHex(version, 2) || version == "ff":
+		return TraceContext{}, fmt.Errorf("lazylog: invalid traceparent version %q", version)
+	case version == "00" && len(parts) != 4:
+		return TraceContext{}, fmt.Errorf("lazylog: invalid traceparent %q", value)
+	case !isLowerHex(traceID, 32) || traceID == strings.Repeat("0", 32):
+		return TraceContext{}, fmt.Errorf("lazylog: invalid trace-id %q", traceID)
+	case !isLowerHex(spanID, 16) || spanID == strings.Repeat("0", 16):
+		return TraceContext{}, fmt.Errorf("lazylog: invalid parent-id %q", spanID)
+	case !isLowerHex(flags, 2):
+		return TraceContext{}, fmt.Errorf("lazylog: invalid trace-flags %q", flags)
+	}
+	f, _ := strconv.ParseUint(flags, 16, 8)
+	return TraceContext{TraceID: traceID, SpanID: spanID, Sampled: f&1 == 1}, nil
+}
+
+// TraceContextFromHeaders extrai o contexto de trace dos headers traceparent e
+// tracestate, sem depender do OpenTelemetry. Retorna false se traceparent
+// estiver ausente ou inválido (tracestate sozinho é ignorado, como manda a
+// especificação).
+func TraceContextFromHeaders(h http.Header) (TraceContext, bool) {
+	tc, err := ParseTraceparent(h.Get("traceparent"))
+	if err != nil {
+		return TraceContext{}, false
+	}
+	tc.TraceState = strings.Join(h.Values("tracestate"), ",")
+	return tc, true
+}
+
+// Fields retorna trace_id e span_id, os nomes usados pelos métodos *Ctx e
+// pelo PrometheusHook.
+func (tc TraceContext) Fields() map[string]any {
+	return map[string]any{"trace_id": tc.TraceID, "span_id": tc.SpanID}
+}
+
+func isLowerHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+package lazylog
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// DefaultTraceSampleRate é a fração de escritas instrumentadas quando
+// TracingTransport.SampleRate não é informado.
+const DefaultTraceSampleRate = 0.01
+
+// Span é a abstração mínima de um span de tracing.
+type Span interface {
+	SetAttribute(key string, value any)
+	RecordError(err error)
+	End()
+}
+
+// Tracer inicia spans. Para OpenTelemetry, basta um adaptador que chame
+// trace.Tracer.Start e embrulhe o trace.Span retornado.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// TracingTransport é um decorator que envolve cada escrita do transporte em um
+// span (nome do transporte, nível, bytes, duração e resultado), amostrado a uma
+// taxa baixa para que a latência do pipeline de logs apareça nos traces.
+type TracingTransport struct {
+	Transport  Transport
+	Tracer     Tracer
+	Name       string  // nome do transporte no span; usa o tipo Go se vazio
+	SampleRate float64 // 0 < SampleRate <= 1; usa DefaultTraceSampleRate se zero
+}
+
+func (t *TracingTransport) WriteLog(entry *Entry) error {
+	rate := t.SampleRate
+	if rate <= 0 {
+		rate = DefaultTraceSampleRate
+	}
+	if t.Tracer == nil || rand.Float64() >= rate {
+		return t.Transport.WriteLog(entry)
+	}
+	name := t.Name
+	if name == "" {
+		name = fmt.Sprintf("%T", t.Transport)
+	}
+	_, span := t.Tracer.Start(entry.Context(), "lazylog.WriteLog")
+	defer span.End()
+	span.SetAttribute("lazylog.transport", name)
+	span.SetAttribute("lazylog.level", entry.Level.String())
+
+	start := time.Now()
+	n, err := writeLogN(t.Transport, entry)
+	span.SetAttribute("lazylog.duration_ms", float64(time.Since(start))/float64(time.Millisecond))
+	if n >= 0 {
+		span.SetAttribute("lazylog.bytes", n)
+	}
+	if err != nil {
+		span.SetAttribute("lazylog.outcome", "error")
+		span.RecordError(err)
+		return err
+	}
+	span.SetAttribute("lazylog.outcome", "ok")
+	return nil
+}
+
+func (t *TracingTransport) MinLevel() Level {
+	return t.Transport.MinLevel()
+}
+
+// Close fecha o transporte envolvido, se ele implementar io.Closer.
+func (t *TracingTransport) Close() error {
+	return closeTransport(t.Transport)
+}
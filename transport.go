@@ -1,5 +1,7 @@
 package lazylog
 
+import "io"
+
 // Transport define a interface para destinos de log (ex: arquivo, console, etc).
 type Transport interface {
 	// WriteLog recebe uma Entry e a escreve no destino configurado.
@@ -8,6 +10,21 @@ type Transport interface {
 	MinLevel() Level
 }
 
+// sizedTransport é implementado pelos transportes embutidos que sabem informar
+// quantos bytes foram gravados por entry (usado em instrumentação e métricas).
+type sizedTransport interface {
+	writeLogN(entry *Entry) (int, error)
+}
+
+// writeLogN escreve a entry no transporte, retornando os bytes gravados quando
+// o transporte sabe informá-los (ou -1 caso contrário).
+func writeLogN(t Transport, entry *Entry) (int, error) {
+	if st, ok := t.(sizedTransport); ok {
+		return st.writeLogN(entry)
+	}
+	return -1, t.WriteLog(entry)
+}
+
 // FilterFunc permite lógica customizada para decidir se um log deve ser aceito pelo transporte.
 type FilterFunc func(entry *Entry) bool
 
@@ -27,3 +44,16 @@ func (t *TransportWithFilter) WriteLog(entry *Entry) error {
 func (t *TransportWithFilter) MinLevel() Level {
 	return t.Transport.MinLevel()
 }
+
+// Close fecha o transporte envolvido (se ele implementar io.Closer).
+func (t *TransportWithFilter) Close() error {
+	return closeTransport(t.Transport)
+}
+
+// closeTransport fecha t se ele implementar io.Closer.
+func closeTransport(t Transport) error {
+	if c, ok := t.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
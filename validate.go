@@ -0,0 +1,111 @@
+package lazylog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// TransportValidator é implementado por transportes capazes de verificar, sem
+// registrar uma entry real, se uma escrita funcionaria (arquivo gravável,
+// endpoint alcançável, TLS e autenticação aceitos...).
+type TransportValidator interface {
+	Validate(ctx context.Context) error
+}
+
+// TransportValidationError identifica o transporte que falhou em Validate.
+type TransportValidationError struct {
+	Index     int // posição do transporte no logger
+	Transport Transport
+	Err       error
+}
+
+func (e *TransportValidationError) Error() string {
+	return fmt.Sprintf("lazylog: transport #%d (%T): %v", e.Index, e.Transport, e.Err)
+}
+
+func (e *TransportValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Validate executa uma verificação (dry-run) em cada transporte do logger e
+// retorna um *TransportValidationError para cada um que falharia, unidos com
+// errors.Join. Indicado para checagens de inicialização.
+func (l *Logger) Validate(ctx context.Context) error {
+	var errs []error
+	for i, t := range l.snapshot().transports {
+		if err := validateTransport(ctx, t); err != nil {
+			errs = append(errs, &TransportValidationError{Index: i, Transport: t, Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateTransport valida t, descendo pelos decorators conhecidos até o
+// transporte que efetivamente escreve.
+func validateTransport(ctx context.Context, t Transport) error {
+	if inner, ok := unwrapTransport(t); ok {
+		return validateTransport(ctx, inner)
+	}
+	switch v := t.(type) {
+	case TransportValidator:
+		return v.Validate(ctx)
+	case ReadinessProber:
+		return v.Ready(ctx)
+	}
+	return nil
+}
+
+// Validate conecta (como Ready) e valida o transporte real.
+func (t *LazyTransport) Validate(ctx context.Context) error {
+	inner, err := t.connect(ctx, true)
+	if err != nil {
+		return err
+	}
+	return validateTransport(ctx, inner)
+}
+
+// Validate verifica se o arquivo continua aberto para escrita.
+func (f *FileTransport) Validate(ctx context.Context) error {
+	_, err := f.File.Write(nil)
+	return err
+}
+
+// Validate faz uma requisição HEAD ao endpoint (com headers e autenticação),
+// exercitando DNS, conexão e handshake TLS. Qualquer resposta que não seja
+// 401/403 ou 5xx é considerada válida, já que muitos endpoints de ingestão
+// não aceitam HEAD.
+func (h *HTTPTransport) Validate(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, h.URL, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range h.Headers {
+		req.Header.Set(k, v)
+	}
+	if h.Auth != nil {
+		if err := h.Auth.Authenticate(req); err != nil {
+			return err
+		}
+	}
+	client := h.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("lazylog: http transport rejected credentials (status %d)", resp.StatusCode)
+	case resp.StatusCode >= 500:
+		return fmt.Errorf("lazylog: http transport got status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package lazylog
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// OtherTemplates agrupa, no VolumeTracker, os templates que excederam
+// MaxTemplates.
+const OtherTemplates = "(other)"
+
+// TemplateVolume é o volume acumulado de um template de mensagem.
+type TemplateVolume struct {
+	Template string // ver MessageTemplate
+	Entries  uint64
+	Bytes    uint64
+}
+
+// VolumeTracker acumula bytes formatados e entries por template de mensagem
+// (MessageTemplate), para identificar os pontos do código que dominam o
+// volume de logs. É alimentado por VolumeTransport:
+//
+//	volume := lazylog.NewVolumeTracker(0)
+//	logger.AddTransport(&lazylog.VolumeTransport{Transport: httpTransport, Tracker: volume})
+//	for _, v := range volume.TopK(10) { ... }
+//	http.Handle("/metrics/volume", volume)
+type VolumeTracker struct {
+	Namespace    string // prefixo das métricas (padrão "lazylog")
+	MaxTemplates int    // templates distintos antes de agrupar em OtherTemplates
+
+	mu        sync.Mutex
+	templates map[string]*TemplateVolume
+}
+
+// NewVolumeTracker cria um VolumeTracker que guarda até maxTemplates
+// templates distintos (padrão: 1000).
+func NewVolumeTracker(maxTemplates int) *VolumeTracker {
+	return &VolumeTracker{MaxTemplates: maxTemplates}
+}
+
+// Add contabiliza uma entry de n bytes com a mensagem informada.
+func (v *VolumeTracker) Add(message string, n int) {
+	template := MessageTemplate(message)
+	limit := v.MaxTemplates
+	if limit <= 0 {
+		limit = 1000
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.templates == nil {
+		v.templates = make(map[string]*TemplateVolume)
+	}
+	tv, ok := v.templates[template]
+	if !ok {
+		if len(v.templates) >= limit {
+			template = OtherTemplates
+			tv = v.templates[template]
+		}
+		if tv == nil {
+			tv = &TemplateVolume{Template: template}
+			v.templates[template] = tv
+		}
+	}
+	tv.Entries++
+	if n > 0 {
+		tv.Bytes += uint64(n)
+	}
+}
+
+// TopK retorna os k templates com mais bytes (todos se k <= 0), em ordem
+// decrescente.
+func (v *VolumeTracker) TopK(k int) []TemplateVolume {
+	v.mu.Lock()
+	out := make([]TemplateVolume, 0, len(v.templates))
+	for _, tv := range v.templates {
+		out = append(out, *tv)
+	}
+	v.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Bytes != out[j].Bytes {
+			return out[i].Bytes > out[j].Bytes
+		}
+		return out[i].Template < out[j].Template
+	})
+	if k > 0 && len(out) > k {
+		out = out[:k]
+	}
+	return out
+}
+
+// Reset zera os contadores.
+func (v *VolumeTracker) Reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.templates = nil
+}
+
+// ServeHTTP escreve os 50 maiores templates no formato OpenMetrics.
+func (v *VolumeTracker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+	fmt.Fprint(w, v.Text(50))
+}
+
+// Text retorna os k maiores templates como métricas OpenMetrics
+// (<ns>_template_bytes_total e <ns>_template_entries_total).
+func (v *VolumeTracker) Text(k int) string {
+	ns := v.Namespace
+	if ns == "" {
+		ns = "lazylog"
+	}
+	top := v.TopK(k)
+	var b strings.Builder
+	for _, metric := range []string{"bytes", "entries"} {
+		fmt.Fprintf(&b, "# TYPE %s_template_%s counter\n", ns, metric)
+		fmt.Fprintf(&b, "# HELP %s_template_%s Formatted log %s by message template.\n", ns, metric, metric)
+		for _, tv := range top {
+			value := tv.Bytes
+			if metric == "entries" {
+				value = tv.Entries
+			}
+			fmt.Fprintf(&b, "%s_template_%s_total{template=%q} %d\n", ns, metric, tv.Template, value)
+		}
+	}
+	b.WriteString("# EOF\n")
+	return b.String()
+}
+
+// VolumeTransport é um decorator que contabiliza no Tracker os bytes
+// formatados de cada entry escrita com sucesso pelo transporte envolvido.
+type VolumeTransport struct {
+	Transport Transport
+	Tracker   *VolumeTracker
+}
+
+func (t *VolumeTransport) WriteLog(entry *Entry) error {
+	n, err := writeLogMeasured(t.Transport, entry)
+	if err == nil && t.Tracker != nil {
+		t.Tracker.Add(entry.Message, n)
+	}
+	return err
+}
+
+func (t *VolumeTransport) MinLevel() Level {
+	return t.Transport.MinLevel()
+}
+
+// Close fecha o transporte envolvido (se ele implementar io.Closer).
+func (t *VolumeTransport) Close() error {
+	return closeTransport(t.Transport)
+}
+
+// writeLogMeasured escreve a entry e retorna seu tamanho formatado: o
+// informado pelo transporte ou, se ele não o informar, o da entry em JSON.
+func writeLogMeasured(t Transport, entry *Entry) (int, error) {
+	n, err := writeLogN(t, entry)
+	if err != nil || n >= 0 {
+		return n, err
+	}
+	if data, ferr := (&JSONFormatter{}).Format(entry); ferr == nil {
+		return len(data), nil
+	}
+	return 0, nil
+}
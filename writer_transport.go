@@ -8,9 +8,18 @@ type WriterTransport struct {
 	Writer    io.Writer
 	Level     Level
 	Formatter Formatter
+
+	stats transportStats
 }
 
 func (w *WriterTransport) WriteLog(entry *Entry) error {
+	_, err := w.writeLogN(entry)
+	return err
+}
+
+// writeLogN escreve a entry e retorna a quantidade de bytes gravados.
+func (w *WriterTransport) writeLogN(entry *Entry) (n int, err error) {
+	defer func() { w.stats.record(w, n, err) }()
 	formatter := w.Formatter
 	if formatter == nil {
 		formatter = &TextFormatter{}
@@ -18,11 +27,13 @@ func (w *WriterTransport) WriteLog(entry *Entry) error {
 	bytes, err := formatter.Format(entry)
 	if err != nil {
 		// fallback simples
-		_, err2 := w.Writer.Write([]byte(entry.Timestamp.Format("2006-01-02T15:04:05Z07:00") + " [" + entry.Level.String() + "] " + entry.Message + "\n"))
-		return err2
+		return w.Writer.Write([]byte(entry.Timestamp.Format("2006-01-02T15:04:05Z07:00") + " [" + entry.Level.String() + "] " + entry.Message + "\n"))
 	}
-	_, err = w.Writer.Write(bytes)
-	return err
+	return w.Writer.Write(bytes)
+}
+
+func (w *WriterTransport) writesTypedFields() bool {
+	return typedFormatter(w.Formatter)
 }
 
 func (w *WriterTransport) MinLevel() Level {